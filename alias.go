@@ -0,0 +1,36 @@
+package jsonsql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// KeyAlias makes Scan accept the old keys aliases for the field of struct type S whose
+// current JSON key is key, wherever S appears inside a document. Renaming a field therefore
+// does not orphan documents stored under its previous name and needs no table-wide migration:
+//
+//	jsonsql.Configure[Settings](jsonsql.KeyAlias[Preferences]("display_name", "nickname"))
+//
+// The current key takes precedence when a document contains both. Value always writes the current key.
+//
+// KeyAlias panics if S is not a struct type or has no field with the JSON key key.
+func KeyAlias[S any](key string, aliases ...string) Option {
+	t := reflect.TypeFor[S]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("jsonsql: KeyAlias: %v is not a struct type", t))
+	}
+	if !slices.ContainsFunc(typeFields(t), func(f field) bool { return f.name == key }) {
+		panic(fmt.Sprintf("jsonsql: KeyAlias: %v has no field with JSON key %q", t, key))
+	}
+
+	return func(c *config) {
+		if c.aliases == nil {
+			c.aliases = map[reflect.Type]map[string][]string{}
+		}
+		if c.aliases[t] == nil {
+			c.aliases[t] = map[string][]string{}
+		}
+		c.aliases[t][key] = append(c.aliases[t][key], aliases...)
+	}
+}
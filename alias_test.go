@@ -0,0 +1,55 @@
+package jsonsql
+
+import "testing"
+
+type testRenamed struct {
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+}
+
+func TestKeyAlias(t *testing.T) {
+	resetOptions[[]testRenamed](t)
+	Configure[[]testRenamed](KeyAlias[testRenamed]("display_name", "nickname", "name"))
+
+	var v Value[[]testRenamed]
+	src := `[{"nickname":"Alice"},{"name":"Bob"},{"display_name":"Carol","nickname":"old"}]`
+	if err := v.Scan(src); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := []string{"Alice", "Bob", "Carol"}
+	for i, name := range expected {
+		if v.V[i].DisplayName != name {
+			t.Errorf("element %d: expected %s, got %s", i, name, v.V[i].DisplayName)
+		}
+	}
+
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `[{"display_name":"Alice","email":""},{"display_name":"Bob","email":""},{"display_name":"Carol","email":""}]` {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestKeyAlias_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"not a struct", func() { KeyAlias[map[string]any]("a", "b") }},
+		{"unknown key", func() { KeyAlias[testRenamed]("DisplayName", "nickname") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*ArrayOf[struct{}])(nil)
+	_ ContextScanner = (*ArrayOf[struct{}])(nil)
+	_ driver.Valuer  = ArrayOf[struct{}]{}
+	_ sql.Scanner    = (*NullableArrayOf[struct{}])(nil)
+	_ ContextScanner = (*NullableArrayOf[struct{}])(nil)
+	_ driver.Valuer  = NullableArrayOf[struct{}]{}
+)
+
+// ArrayOf[T] is a generic type for NOT NULL Postgres jsonb[] and json[] columns.
+// Scan parses the array literal returned by the driver and decodes every element into a T
+// with the options registered for T; Value writes V back as an array literal.
+// Elements must not be NULL or JSON null. Multidimensional arrays are not supported.
+type ArrayOf[T any] struct {
+	V []T
+}
+
+// NewArrayOf creates a new ArrayOf[T] with the given elements.
+func NewArrayOf[T any](v ...T) ArrayOf[T] {
+	return ArrayOf[T]{V: v}
+}
+
+// Get returns the elements.
+func (a ArrayOf[T]) Get() []T {
+	return a.V
+}
+
+// Scan implements sql.Scanner interface.
+// Returns ErrNullNotAllowed if src is nil (NOT NULL constraint violation) or an element is null.
+func (a *ArrayOf[T]) Scan(src any) error {
+	return a.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (a *ArrayOf[T]) ScanContext(ctx context.Context, src any) error {
+	v, null, err := scanArray[T](ctx, src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.ArrayOf.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	a.V = v
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It returns the Postgres array literal of the JSON encoded elements; a nil V is written as an empty array.
+func (a ArrayOf[T]) Value() (driver.Value, error) {
+	data, err := arrayLiteral(a.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.ArrayOf.Value: %w", err)
+	}
+	return data, nil
+}
+
+// NullableArrayOf[T] is a generic type for NULL-able Postgres jsonb[] and json[] columns.
+// Valid is false when the column itself is NULL; elements follow the rules of ArrayOf[T].
+type NullableArrayOf[T any] struct {
+	V     []T
+	Valid bool
+}
+
+// NullableArrayOfFrom creates a new NullableArrayOf[T] with Valid=true and the given elements.
+func NullableArrayOfFrom[T any](v ...T) NullableArrayOf[T] {
+	return NullableArrayOf[T]{V: v, Valid: true}
+}
+
+// Get returns the elements and a boolean indicating whether they are valid.
+func (n NullableArrayOf[T]) Get() ([]T, bool) {
+	return n.V, n.Valid
+}
+
+// Scan implements sql.Scanner interface.
+// Sets Valid=false for nil.
+func (n *NullableArrayOf[T]) Scan(src any) error {
+	return n.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (n *NullableArrayOf[T]) ScanContext(ctx context.Context, src any) error {
+	v, null, err := scanArray[T](ctx, src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.NullableArrayOf.Scan: %w", err)
+	}
+	n.V, n.Valid = v, !null
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// Returns nil (NULL) when Valid is false.
+func (n NullableArrayOf[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	data, err := arrayLiteral(n.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.NullableArrayOf.Value: %w", err)
+	}
+	return data, nil
+}
+
+// ErrInvalidArray is returned by Scan when the source is not a one-dimensional Postgres array literal.
+var ErrInvalidArray = permanent("jsonsql: invalid array literal")
+
+// scanArray parses a Postgres array literal and decodes its elements.
+// It reports null=true when src is SQL NULL.
+func scanArray[T any](ctx context.Context, src any) (v []T, null bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	src, err = resolveSource(src)
+	if err != nil {
+		return nil, false, err
+	}
+	if src == nil {
+		return nil, true, nil
+	}
+	data, err := sourceBytes(src)
+	if err != nil {
+		return nil, false, err
+	}
+
+	elems, err := parseArrayLiteral(data)
+	if err != nil {
+		return nil, false, err
+	}
+	v = make([]T, len(elems))
+	for i, elem := range elems {
+		if elem == nil {
+			return nil, false, fmt.Errorf("element %d: %w", i, ErrNullNotAllowed)
+		}
+		null, err := decodeSourceContext(ctx, elem, &v[i], ErrorOnEmpty)
+		if err != nil {
+			return nil, false, fmt.Errorf("element %d: %w", i, err)
+		}
+		if null {
+			return nil, false, fmt.Errorf("element %d: %w", i, ErrNullNotAllowed)
+		}
+	}
+	return v, false, nil
+}
+
+// parseArrayLiteral splits a one-dimensional Postgres array literal such as {"{\"a\":1}",NULL}
+// into its elements, with nil for NULL elements.
+func parseArrayLiteral(data []byte) ([][]byte, error) {
+	data = bytes.TrimSpace(data)
+	// Skip an optional dimension decoration such as [0:1]=.
+	if len(data) > 0 && data[0] == '[' {
+		i := bytes.IndexByte(data, '=')
+		if i < 0 {
+			return nil, ErrInvalidArray
+		}
+		data = bytes.TrimSpace(data[i+1:])
+	}
+	if len(data) < 2 || data[0] != '{' || data[len(data)-1] != '}' {
+		return nil, ErrInvalidArray
+	}
+	data = data[1 : len(data)-1]
+
+	elems := [][]byte{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return elems, nil
+	}
+	for i := 0; ; {
+		for i < len(data) && isJSONSpace(data[i]) {
+			i++
+		}
+		if i == len(data) {
+			return nil, ErrInvalidArray
+		}
+
+		var elem []byte
+		switch data[i] {
+		case '{':
+			return nil, fmt.Errorf("%w: multidimensional arrays are not supported", ErrInvalidArray)
+		case '"':
+			elem = []byte{}
+			for i++; ; i++ {
+				if i == len(data) {
+					return nil, ErrInvalidArray
+				}
+				if data[i] == '\\' && i+1 < len(data) {
+					i++
+				} else if data[i] == '"' {
+					i++
+					break
+				}
+				elem = append(elem, data[i])
+			}
+		default:
+			start := i
+			for i < len(data) && data[i] != ',' {
+				i++
+			}
+			elem = bytes.TrimSpace(data[start:i])
+			if bytes.EqualFold(elem, []byte("NULL")) {
+				elem = nil
+			}
+		}
+		elems = append(elems, elem)
+
+		for i < len(data) && isJSONSpace(data[i]) {
+			i++
+		}
+		if i == len(data) {
+			return elems, nil
+		}
+		if data[i] != ',' {
+			return nil, ErrInvalidArray
+		}
+		i++
+	}
+}
+
+// arrayLiteral encodes the elements of v with the options registered for T
+// and returns them as a Postgres array literal of quoted elements.
+func arrayLiteral[T any](v []T) ([]byte, error) {
+	cfg := configFor[T]()
+	out := []byte{'{'}
+	for i, elem := range v {
+		data, err := encodeValue(elem, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, '"')
+		for _, b := range data {
+			if b == '"' || b == '\\' {
+				out = append(out, '\\')
+			}
+			out = append(out, b)
+		}
+		out = append(out, '"')
+	}
+	return append(out, '}'), nil
+}
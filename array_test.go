@@ -0,0 +1,123 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestArrayOf_Scan(t *testing.T) {
+	var a ArrayOf[testProfile]
+	src := `{"{\"name\": \"Alice\", \"email\": \"a@example.com\"}","{\"name\": \"Bob\"}"}`
+	if err := a.Scan([]byte(src)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(a.V) != 2 || a.V[0].Name != "Alice" || a.V[0].Email != "a@example.com" || a.V[1].Name != "Bob" {
+		t.Errorf("unexpected result: %+v", a.V)
+	}
+}
+
+func TestArrayOf_ScanLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []any
+	}{
+		{"empty", `{}`, []any{}},
+		{"unquoted scalars", `{1,true, 2.5 }`, []any{float64(1), true, 2.5}},
+		{"quoted string", `{"\"a,b\\\\c\""}`, []any{`a,b\c`}},
+		{"dimension decoration", `[1:2]={1,2}`, []any{float64(1), float64(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a ArrayOf[any]
+			if err := a.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if len(a.V) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, a.V)
+			}
+			for i := range a.V {
+				if a.V[i] != tt.expected[i] {
+					t.Errorf("element %d: expected %v, got %v", i, tt.expected[i], a.V[i])
+				}
+			}
+		})
+	}
+}
+
+func TestArrayOf_ScanErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{"not an array", `[1,2]`, ErrInvalidArray},
+		{"unterminated quote", `{"abc}`, ErrInvalidArray},
+		{"multidimensional", `{{1},{2}}`, ErrInvalidArray},
+		{"NULL element", `{1,NULL}`, ErrNullNotAllowed},
+		{"JSON null element", `{1,null}`, ErrNullNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a ArrayOf[any]
+			if err := a.Scan(tt.input); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	var a ArrayOf[any]
+	if err := a.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for nil, got %v", err)
+	}
+}
+
+func TestArrayOf_Value(t *testing.T) {
+	result, err := NewArrayOf(testProfile{Name: `A"l\ice`}, testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := `{"{\"name\":\"A\\\"l\\\\ice\",\"email\":\"\"}","{\"name\":\"Bob\",\"email\":\"\"}"}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var scanned ArrayOf[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(scanned.V) != 2 || scanned.V[0].Name != `A"l\ice` {
+		t.Errorf("unexpected round trip result: %+v", scanned.V)
+	}
+
+	result, err = ArrayOf[int]{}.Value()
+	if err != nil || string(result.([]byte)) != "{}" {
+		t.Errorf("expected {} for nil slice, got %s, %v", result, err)
+	}
+}
+
+func TestNullableArrayOf(t *testing.T) {
+	n := NullableArrayOfFrom(1, 2)
+	if err := n.Scan(nil); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.Valid || n.V != nil {
+		t.Errorf("expected NULL, got %+v", n)
+	}
+
+	result, err := n.Value()
+	if err != nil || result != nil {
+		t.Errorf("expected nil for NULL, got %v, %v", result, err)
+	}
+
+	if err := n.Scan("{3}"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || len(n.V) != 1 || n.V[0] != 3 {
+		t.Errorf("unexpected result: %+v", n)
+	}
+}
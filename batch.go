@@ -0,0 +1,109 @@
+package jsonsql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// BatchValues encodes the documents of values for a multi-row INSERT or a bulk load, returning
+// one driver value per element with the same result as calling Value on each.
+// For plain JSON configurations the documents are encoded with pooled encoders into shared
+// chunks of memory, so encoding many small documents allocates far less than calling Value
+// on each. Errors are reported with the index of the failing element.
+func BatchValues[T any](values []Value[T]) ([]driver.Value, error) {
+	out, err := encodeBatch(len(values), func(i int) (T, bool) { return values[i].V, true })
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.BatchValues: %w", err)
+	}
+	return out, nil
+}
+
+// BatchNullables is like BatchValues for Nullable[T]; invalid elements are returned as nil (NULL),
+// or as the configured document when NullAsJSON or NullAsEmptyObject is enabled for T.
+func BatchNullables[T any](values []Nullable[T]) ([]driver.Value, error) {
+	if err := checkNullableParam[T](); err != nil {
+		return nil, fmt.Errorf("jsonsql.BatchNullables: %w", err)
+	}
+	out, err := encodeBatch(len(values), func(i int) (T, bool) { return values[i].V, values[i].Valid })
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.BatchNullables: %w", err)
+	}
+	return out, nil
+}
+
+// BatchJSON is like BatchValues for plain values, encoding each like ValueJSON.
+func BatchJSON[T any](values []T) ([]driver.Value, error) {
+	out, err := encodeBatch(len(values), func(i int) (T, bool) { return values[i], true })
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.BatchJSON: %w", err)
+	}
+	return out, nil
+}
+
+// batchChunkSize is the size of the memory chunks shared by batch encoded documents.
+// Larger documents get their own allocation.
+const batchChunkSize = 64 << 10
+
+// batchBuffers pools the buffers batch encoders write into.
+var batchBuffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// encodeBatch encodes n elements returned by elem with the options registered for T.
+// Elements for which elem reports false are returned as the NULL value of the config.
+func encodeBatch[T any](n int, elem func(i int) (T, bool)) ([]driver.Value, error) {
+	cfg := configFor[T]()
+	out := make([]driver.Value, n)
+	// json.RawMessage documents are stored as is by encodeValue rather than re-encoded.
+	if !cfg.plainJSON() || reflect.TypeFor[T]() == rawMessageType {
+		for i := range n {
+			v, ok := elem(i)
+			if !ok {
+				out[i] = cfg.nullValue()
+				continue
+			}
+			data, err := encodeValue(v, cfg)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			out[i] = cfg.driverValue(data)
+		}
+		return out, nil
+	}
+
+	buf := batchBuffers.Get().(*bytes.Buffer)
+	defer batchBuffers.Put(buf)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(!cfg.noEscapeHTML)
+
+	var chunk []byte
+	for i := range n {
+		v, ok := elem(i)
+		if !ok {
+			out[i] = cfg.nullValue()
+			continue
+		}
+		buf.Reset()
+		if err := enc.Encode(v); err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		// Encoder.Encode terminates each value with a newline.
+		data := buf.Bytes()[:buf.Len()-1]
+		if len(data) > batchChunkSize/4 {
+			out[i] = cfg.driverValue(bytes.Clone(data))
+			continue
+		}
+		if cap(chunk)-len(chunk) < len(data) {
+			chunk = make([]byte, 0, batchChunkSize)
+		}
+		start := len(chunk)
+		chunk = append(chunk, data...)
+		// Limit the capacity so appending to one document cannot overwrite the next.
+		out[i] = cfg.driverValue(chunk[start:len(chunk):len(chunk)])
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package jsonsql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testBatchDoc struct {
+	ID   int    `json:"id"`
+	Note string `json:"note"`
+}
+
+func TestBatchValues(t *testing.T) {
+	resetOptions[testBatchDoc](t)
+
+	values := make([]Value[testBatchDoc], 5000)
+	for i := range values {
+		values[i] = NewValue(testBatchDoc{ID: i, Note: "<b>" + strings.Repeat("x", i%50) + "</b>"})
+	}
+	out, err := BatchValues(values)
+	if err != nil {
+		t.Fatalf("BatchValues failed: %v", err)
+	}
+	if len(out) != len(values) {
+		t.Fatalf("expected %d values, got %d", len(values), len(out))
+	}
+	for i, v := range values {
+		expected, err := v.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if string(out[i].([]byte)) != string(expected.([]byte)) {
+			t.Fatalf("element %d: expected %s, got %s", i, expected, out[i])
+		}
+	}
+
+	// Appending to one document must not overwrite its neighbour.
+	_ = append(out[0].([]byte), "garbage"...)
+	if string(out[1].([]byte)) != `{"id":1,"note":"\u003cb\u003ex\u003c/b\u003e"}` {
+		t.Errorf("neighbour modified: %s", out[1])
+	}
+}
+
+func TestBatchValues_Options(t *testing.T) {
+	resetOptions[testBatchDoc](t)
+	Configure[testBatchDoc](KeyCase(CamelCase, KeysAsIs), EscapeHTML(false))
+
+	out, err := BatchValues([]Value[testBatchDoc]{NewValue(testBatchDoc{ID: 1, Note: "<b>"})})
+	if err != nil {
+		t.Fatalf("BatchValues failed: %v", err)
+	}
+	if string(out[0].([]byte)) != `{"id":1,"note":"<b>"}` {
+		t.Errorf("unexpected result: %s", out[0])
+	}
+}
+
+func TestBatchValues_LargeDocument(t *testing.T) {
+	resetOptions[testBatchDoc](t)
+
+	note := strings.Repeat("y", batchChunkSize)
+	out, err := BatchJSON([]testBatchDoc{{ID: 1, Note: note}, {ID: 2}})
+	if err != nil {
+		t.Fatalf("BatchJSON failed: %v", err)
+	}
+	if string(out[0].([]byte)) != fmt.Sprintf(`{"id":1,"note":"%s"}`, note) {
+		t.Errorf("unexpected large document")
+	}
+	if string(out[1].([]byte)) != `{"id":2,"note":""}` {
+		t.Errorf("unexpected result: %s", out[1])
+	}
+}
+
+func TestBatchNullables(t *testing.T) {
+	out, err := BatchNullables([]Nullable[map[string]int]{
+		NullableFrom(map[string]int{"a": 1}),
+		Null[map[string]int](),
+	})
+	if err != nil {
+		t.Fatalf("BatchNullables failed: %v", err)
+	}
+	if string(out[0].([]byte)) != `{"a":1}` {
+		t.Errorf("unexpected result: %s", out[0])
+	}
+	if out[1] != nil {
+		t.Errorf("expected nil for NULL element, got %v", out[1])
+	}
+
+	if _, err := BatchNullables([]Nullable[*int]{}); err == nil {
+		t.Error("expected error for Nullable[*int]")
+	}
+}
+
+func TestBatchJSON_Error(t *testing.T) {
+	_, err := BatchJSON([]any{1, make(chan int)})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "jsonsql.BatchJSON: element 1:") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func BenchmarkBatchValues(b *testing.B) {
+	values := make([]Value[testBatchDoc], 1000)
+	for i := range values {
+		values[i] = NewValue(testBatchDoc{ID: i, Note: "note"})
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := BatchValues(values); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
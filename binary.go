@@ -0,0 +1,54 @@
+package jsonsql
+
+import (
+	"encoding"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ encoding.BinaryMarshaler   = Value[struct{}]{}
+	_ encoding.BinaryUnmarshaler = (*Value[struct{}])(nil)
+	_ encoding.BinaryMarshaler   = Nullable[struct{}]{}
+	_ encoding.BinaryUnmarshaler = (*Nullable[struct{}])(nil)
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+// It returns the same document bytes as Value, so values can be stored in caches or gob streams.
+func (v Value[T]) MarshalBinary() ([]byte, error) {
+	data, err := encodeValue(v.V, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Value.MarshalBinary: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// It decodes data with the same rules as Scan.
+func (v *Value[T]) UnmarshalBinary(data []byte) error {
+	return v.Scan(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+// It returns the same document bytes as Value, or JSON literal "null" when Valid is false.
+// With a DocumentCodec, which has no null document, NULL is returned as empty data instead.
+func (n Nullable[T]) MarshalBinary() ([]byte, error) {
+	cfg := configFor[T]()
+	if !n.Valid {
+		if cfg.documentCodec != nil {
+			return []byte{}, nil
+		}
+		return []byte("null"), nil
+	}
+	data, err := encodeValue(n.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Nullable.MarshalBinary: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// It decodes data with the same rules as Scan.
+func (n *Nullable[T]) UnmarshalBinary(data []byte) error {
+	return n.Scan(data)
+}
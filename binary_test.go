@@ -0,0 +1,120 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestValue_BinaryRoundtrip(t *testing.T) {
+	original := NewValue(testProfile{Name: "Alice", Email: "alice@example.com"})
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	var restored Value[testProfile]
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if restored.V != original.V {
+		t.Errorf("expected %+v, got %+v", original.V, restored.V)
+	}
+}
+
+func TestNullable_BinaryRoundtrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		original Nullable[testProfile]
+	}{
+		{"valid", NullableFrom(testProfile{Name: "Alice"})},
+		{"null", Null[testProfile]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.original.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary failed: %v", err)
+			}
+
+			restored := NullableFrom(testProfile{Name: "Previous"})
+			if err := restored.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if restored != tt.original {
+				t.Errorf("expected %+v, got %+v", tt.original, restored)
+			}
+		})
+	}
+}
+
+func TestBinary_Gob(t *testing.T) {
+	type session struct {
+		ID      int
+		Profile Value[testProfile]
+		Meta    Nullable[map[string]string]
+		Empty   Nullable[testProfile]
+	}
+
+	original := session{
+		ID:      1,
+		Profile: NewValue(testProfile{Name: "Alice"}),
+		Meta:    NullableFrom(map[string]string{"k": "v"}),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(original); err != nil {
+		t.Fatalf("gob encode failed: %v", err)
+	}
+
+	var restored session
+	if err := gob.NewDecoder(&buf).Decode(&restored); err != nil {
+		t.Fatalf("gob decode failed: %v", err)
+	}
+
+	if restored.ID != 1 || restored.Profile.V.Name != "Alice" || restored.Meta.V["k"] != "v" || restored.Empty.Valid {
+		t.Errorf("unexpected result: %+v", restored)
+	}
+}
+
+func TestBinary_DocumentCodec(t *testing.T) {
+	resetOptions[string](t)
+	Configure[string](DocumentCodec(testCodec{}))
+
+	data, err := NewValue("hello").MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if string(data) != "codec:hello" {
+		t.Errorf("unexpected data %q", data)
+	}
+	var v Value[string]
+	if err := v.UnmarshalBinary(data); err != nil || v.V != "hello" {
+		t.Errorf("expected hello, got %q, err: %v", v.V, err)
+	}
+
+	for _, original := range []Nullable[string]{NullableFrom("hello"), Null[string]()} {
+		data, err := original.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+		restored := NullableFrom("previous")
+		if err := restored.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		if restored != original {
+			t.Errorf("expected %+v, got %+v", original, restored)
+		}
+	}
+}
+
+func TestBinary_MaxDocumentSize(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](MaxDocumentSize(10))
+
+	if _, err := NewValue(testProfile{Name: "Alice"}).MarshalBinary(); err == nil {
+		t.Error("expected error for a document over MaxDocumentSize")
+	}
+}
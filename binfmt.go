@@ -0,0 +1,210 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Codec converts between Go values and an encoded document format.
+// It allows the built-in format implementations to be replaced by third-party libraries.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// DocumentCodec makes Value, Nullable and Sensitive store documents with codec instead of JSON,
+// for example BSON through the bsoncodec subpackage. Passing nil restores JSON.
+//
+// The codec receives V directly, so JSON options do not apply to it, and only SQL NULL and
+// empty input (see EmptyInput) are treated as null.
+func DocumentCodec(codec Codec) Option {
+	return func(c *config) {
+		c.documentCodec = codec
+	}
+}
+
+// decodeCodec decodes a resolved, non-nil source value into v with the DocumentCodec configured in cfg.
+func decodeCodec[T any](src any, v *T, cfg *config, emptyDefault EmptyPolicy) (null bool, err error) {
+	data, err := sourceBytes(src)
+	if err != nil {
+		return false, err
+	}
+	if len(data) == 0 {
+		return scanEmpty(v, cfg, emptyDefault)
+	}
+	if cfg.resetOnScan {
+		var zero T
+		*v = zero
+	}
+	return false, cfg.documentCodec.Unmarshal(data, v)
+}
+
+// sourceBytes returns the bytes of a resolved source value holding an encoded document.
+func sourceBytes(src any) ([]byte, error) {
+	switch s := src.(type) {
+	case []byte:
+		return s, nil
+	case string:
+		return []byte(s), nil
+	}
+	return nil, unsupportedSrcType(src)
+}
+
+// binaryFormat describes a binary document format stored in BLOB/bytea columns instead of JSON text.
+// The built-in implementations convert from and to the JSON data model, so json struct tags
+// and all JSON options (time formats, number hooks, ...) apply unchanged.
+type binaryFormat struct {
+	name string
+	// null is the encoding of a null document.
+	null []byte
+	// fromJSON converts a JSON document to the binary format.
+	fromJSON func(data []byte) ([]byte, error)
+	// toJSON converts a binary document to JSON.
+	toJSON func(data []byte) ([]byte, error)
+	// codec returns the Codec plugged into cfg for this format, or nil for the built-in one.
+	codec func(cfg *config) Codec
+}
+
+// maxBinaryDepth limits the nesting depth accepted when decoding binary documents.
+const maxBinaryDepth = 10000
+
+// errBinaryTooDeep is returned when a binary document exceeds maxBinaryDepth.
+var errBinaryTooDeep = errors.New("jsonsql: binary document nested too deeply")
+
+// decodeBinarySource decodes a database source value in format f into v according to the options
+// registered for T, like decodeSourceContext for JSON documents.
+func decodeBinarySource[T any](ctx context.Context, src any, v *T, f *binaryFormat, emptyDefault EmptyPolicy) (null bool, err error) {
+	return decodeSourceWith(ctx, src, v, configFor[T](), emptyDefault, func(src any, v *T, cfg *config, emptyDefault EmptyPolicy) (bool, error) {
+		return decodeBinaryPayload(src, v, cfg, f, emptyDefault)
+	})
+}
+
+// decodeBinaryPayload decodes a resolved source value in format f into v according to cfg.
+// It follows the same null rules as decodePayload.
+func decodeBinaryPayload[T any](src any, v *T, cfg *config, f *binaryFormat, emptyDefault EmptyPolicy) (null bool, err error) {
+	if src == nil {
+		return true, nil
+	}
+	data, err := sourceBytes(src)
+	if err != nil {
+		return false, err
+	}
+	if len(data) == 0 {
+		return scanEmpty(v, cfg, emptyDefault)
+	}
+	if bytes.Equal(data, f.null) {
+		return true, nil
+	}
+
+	if cfg.resetOnScan {
+		var zero T
+		*v = zero
+	}
+	if codec := f.codec(cfg); codec != nil {
+		return false, codec.Unmarshal(data, v)
+	}
+
+	js, err := f.toJSON(data)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s data: %w", f.name, err)
+	}
+	return false, unmarshal(js, v, cfg)
+}
+
+// encodeBinary encodes v in format f according to the options registered for T,
+// like encodeValue for JSON documents.
+func encodeBinary[T any](v T, f *binaryFormat) ([]byte, error) {
+	return encodeValueWith(context.Background(), v, configFor[T](), func(v any, cfg *config) ([]byte, error) {
+		if codec := f.codec(cfg); codec != nil {
+			return codec.Marshal(v)
+		}
+		js, err := marshal(v, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return f.fromJSON(js)
+	})
+}
+
+// writeJSONFloat writes f as a JSON number. NaN and infinities have no JSON representation.
+func writeJSONFloat(buf *bytes.Buffer, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("unsupported float value %v", f)
+	}
+	buf.Write(strconv.AppendFloat(nil, f, 'g', -1, 64))
+	return nil
+}
+
+// jsonNode is a parsed JSON value that keeps the order of object keys,
+// used as the intermediate form when converting JSON to binary formats.
+type jsonNode struct {
+	// kind is 'n' (null), 'b' (bool), '#' (number), 's' (string), '[' (array) or '{' (object).
+	kind  byte
+	bool  bool
+	num   json.Number
+	str   string
+	keys  []string
+	elems []jsonNode
+}
+
+// parseJSONTree parses a single JSON document into a jsonNode tree.
+func parseJSONTree(data []byte) (jsonNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := parseJSONNode(dec)
+	if err != nil {
+		return jsonNode{}, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return jsonNode{}, ErrTrailingData
+	}
+	return node, nil
+}
+
+// parseJSONNode reads the next JSON value from dec into a jsonNode, recursing into arrays and
+// objects. dec must have UseNumber set; numbers are kept as the json.Number text, not converted.
+// At end of input it returns io.EOF from dec.Token unchanged; it does not check for trailing data.
+func parseJSONNode(dec *json.Decoder) (jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return jsonNode{}, err
+	}
+
+	switch t := tok.(type) {
+	case nil:
+		return jsonNode{kind: 'n'}, nil
+	case bool:
+		return jsonNode{kind: 'b', bool: t}, nil
+	case json.Number:
+		return jsonNode{kind: '#', num: t}, nil
+	case string:
+		return jsonNode{kind: 's', str: t}, nil
+	case json.Delim:
+		node := jsonNode{kind: byte(t)}
+		for dec.More() {
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return jsonNode{}, err
+				}
+				node.keys = append(node.keys, key.(string))
+			}
+			elem, err := parseJSONNode(dec)
+			if err != nil {
+				return jsonNode{}, err
+			}
+			node.elems = append(node.elems, elem)
+		}
+		if _, err := dec.Token(); err != nil {
+			return jsonNode{}, err
+		}
+		return node, nil
+	}
+	return jsonNode{}, fmt.Errorf("jsonsql: unexpected JSON token %v", tok)
+}
@@ -0,0 +1,41 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocumentCodec(t *testing.T) {
+	resetOptions[string](t)
+	Configure[string](DocumentCodec(testCodec{}))
+
+	result, err := NewValue("hello").Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != "codec:hello" {
+		t.Errorf("unexpected result: %s", result)
+	}
+
+	var v Value[string]
+	if err := v.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V != "hello" {
+		t.Errorf("expected hello, got %s", v.V)
+	}
+	if err := v.Scan([]byte{}); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+
+	var n Nullable[string]
+	if err := n.Scan("codec:null"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V != "null" {
+		t.Errorf("expected valid \"null\" string, got %+v", n)
+	}
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("expected NULL, got %+v, %v", n, err)
+	}
+}
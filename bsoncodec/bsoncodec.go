@@ -0,0 +1,36 @@
+// Package bsoncodec stores jsonsql documents as BSON, for pipelines that exchange documents
+// with MongoDB-adjacent systems through bytea/BLOB columns.
+// It lives in its own module so that the MongoDB driver stays an optional dependency.
+//
+//	jsonsql.Configure[Event](bsoncodec.Option())
+//
+//	var e jsonsql.Value[Event]
+//	err := row.Scan(&e)
+package bsoncodec
+
+import (
+	"github.com/jinford/jsonsql"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Compile-time interface satisfaction check
+var _ jsonsql.Codec = Codec{}
+
+// Codec is a jsonsql.Codec encoding documents with the MongoDB BSON library.
+// Top-level values must be structs, maps or bson.D, as BSON documents cannot hold bare scalars.
+type Codec struct{}
+
+// Marshal implements jsonsql.Codec.
+func (Codec) Marshal(v any) ([]byte, error) {
+	return bson.Marshal(v)
+}
+
+// Unmarshal implements jsonsql.Codec.
+func (Codec) Unmarshal(data []byte, v any) error {
+	return bson.Unmarshal(data, v)
+}
+
+// Option returns a jsonsql option making Value, Nullable and Sensitive store documents as BSON.
+func Option() jsonsql.Option {
+	return jsonsql.DocumentCodec(Codec{})
+}
@@ -0,0 +1,48 @@
+package bsoncodec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type testEvent struct {
+	Kind  string `bson:"kind"`
+	Count int64  `bson:"count"`
+}
+
+func TestOption_RoundTrip(t *testing.T) {
+	jsonsql.Configure[testEvent](Option())
+	t.Cleanup(func() { jsonsql.Configure[testEvent]() })
+
+	result, err := jsonsql.NewValue(testEvent{Kind: "click", Count: 3}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected, _ := bson.Marshal(bson.D{{Key: "kind", Value: "click"}, {Key: "count", Value: int64(3)}})
+	if !bytes.Equal(result.([]byte), expected) {
+		t.Errorf("expected % x, got % x", expected, result)
+	}
+
+	var n jsonsql.Nullable[testEvent]
+	if err := n.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V != (testEvent{Kind: "click", Count: 3}) {
+		t.Errorf("unexpected result: %+v", n)
+	}
+
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("expected NULL, got %+v, %v", n, err)
+	}
+}
+
+func TestCodec_InvalidData(t *testing.T) {
+	var e testEvent
+	if err := (Codec{}).Unmarshal([]byte{0x01, 0x02}, &e); err == nil {
+		t.Error("expected error for invalid BSON")
+	}
+}
@@ -0,0 +1,615 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Cbor[struct{}])(nil)
+	_ ContextScanner = (*Cbor[struct{}])(nil)
+	_ driver.Valuer  = Cbor[struct{}]{}
+	_ sql.Scanner    = (*NullableCbor[struct{}])(nil)
+	_ ContextScanner = (*NullableCbor[struct{}])(nil)
+	_ driver.Valuer  = NullableCbor[struct{}]{}
+)
+
+// Cbor[T] is a generic type for NOT NULL BLOB/bytea columns holding CBOR (RFC 8949) documents.
+// It has the same API and null semantics as Value[T], but stores V as CBOR instead of JSON text.
+// Documents go through the same checks as Value[T]: MaxDocumentSize, ValidateValues and the
+// observability hooks apply.
+//
+// The built-in encoder converts through the JSON data model, so json struct tags and all options
+// apply unchanged; byte slices are stored as base64 text strings as they are in JSON.
+// A third-party CBOR library can be plugged in with CborCodec.
+type Cbor[T any] struct {
+	V T
+}
+
+// NewCbor creates a new Cbor[T] with the given value.
+func NewCbor[T any](v T) Cbor[T] {
+	return Cbor[T]{V: v}
+}
+
+// Get returns the value.
+func (c Cbor[T]) Get() T {
+	return c.V
+}
+
+// Set replaces the value with x.
+func (c *Cbor[T]) Set(x T) {
+	c.V = x
+}
+
+// Replace replaces the value with the result of calling f with the current value.
+func (c *Cbor[T]) Replace(f func(T) T) {
+	c.V = f(c.V)
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes CBOR data from the database into V.
+// Returns ErrNullNotAllowed if src is nil or the CBOR null value (NOT NULL constraint violation),
+// and ErrEmptyInput for empty input unless another EmptyPolicy is configured.
+func (c *Cbor[T]) Scan(src any) error {
+	return c.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (c *Cbor[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeBinarySource(ctx, src, &c.V, cborFormat, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Cbor.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It encodes V to CBOR bytes for database storage.
+func (c Cbor[T]) Value() (driver.Value, error) {
+	data, err := encodeBinary(c.V, cborFormat)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Cbor.Value: %w", err)
+	}
+	return data, nil
+}
+
+// NullableCbor[T] is a generic type for NULL-able BLOB/bytea columns holding CBOR documents.
+// It has the same API and null semantics as Nullable[T], except that NULL is always stored as
+// SQL NULL: NullAsJSON and NullAsEmptyObject do not apply.
+type NullableCbor[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNullableCbor creates a new NullableCbor[T] with the given value and valid flag.
+// If valid is false, V is set to the zero value of T.
+func NewNullableCbor[T any](v T, valid bool) NullableCbor[T] {
+	if !valid {
+		return NullCbor[T]()
+	}
+	return NullableCborFrom(v)
+}
+
+// NullableCborFrom creates a new NullableCbor[T] with Valid=true and the given value.
+func NullableCborFrom[T any](v T) NullableCbor[T] {
+	return NullableCbor[T]{V: v, Valid: true}
+}
+
+// NullCbor creates a new NullableCbor[T] with Valid=false (represents NULL).
+func NullCbor[T any]() NullableCbor[T] {
+	return NullableCbor[T]{Valid: false}
+}
+
+// NullableCborFromPtr creates a NullableCbor[T] from a pointer.
+// Returns NullCbor[T]() if ptr is nil.
+func NullableCborFromPtr[T any](ptr *T) NullableCbor[T] {
+	if ptr == nil {
+		return NullCbor[T]()
+	}
+	return NullableCborFrom(*ptr)
+}
+
+// ToPtr returns a pointer to the value if Valid is true, otherwise nil.
+func (n NullableCbor[T]) ToPtr() *T {
+	if !n.Valid {
+		return nil
+	}
+	return &n.V
+}
+
+// Get returns the value and a boolean indicating whether it is valid.
+func (n NullableCbor[T]) Get() (T, bool) {
+	return n.V, n.Valid
+}
+
+// TryGet returns the value, or ErrNullNotAllowed when n is NULL.
+func (n NullableCbor[T]) TryGet() (T, error) {
+	if !n.Valid {
+		var zero T
+		return zero, ErrNullNotAllowed
+	}
+	return n.V, nil
+}
+
+// Set replaces the value with x and sets Valid=true.
+func (n *NullableCbor[T]) Set(x T) {
+	n.V = x
+	n.Valid = true
+}
+
+// SetNull resets n to NULL (Valid=false, V=zero value).
+func (n *NullableCbor[T]) SetNull() {
+	var zero T
+	n.V = zero
+	n.Valid = false
+}
+
+// Replace replaces the value with the result of calling f with the current value and sets Valid=true.
+// When n is NULL, f receives the zero value of T.
+func (n *NullableCbor[T]) Replace(f func(T) T) {
+	n.Set(f(n.V))
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes CBOR data from the database into V.
+// Sets Valid=false for nil, empty input, or the CBOR null value.
+func (n *NullableCbor[T]) Scan(src any) error {
+	return n.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (n *NullableCbor[T]) ScanContext(ctx context.Context, src any) error {
+	if err := checkNullableParam[T](); err != nil {
+		return fmt.Errorf("jsonsql.NullableCbor.Scan: %w", err)
+	}
+	null, err := decodeBinarySource(ctx, src, &n.V, cborFormat, NullOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.NullableCbor.Scan: %w", err)
+	}
+	if null {
+		n.SetNull()
+		return nil
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// Returns nil (NULL) when Valid is false.
+// Otherwise encodes V to CBOR bytes.
+func (n NullableCbor[T]) Value() (driver.Value, error) {
+	if err := checkNullableParam[T](); err != nil {
+		return nil, fmt.Errorf("jsonsql.NullableCbor.Value: %w", err)
+	}
+	if !n.Valid {
+		reportNull[T](configFor[T](), nil)
+		return nil, nil
+	}
+	data, err := encodeBinary(n.V, cborFormat)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.NullableCbor.Value: %w", err)
+	}
+	return data, nil
+}
+
+// CborCodec replaces the built-in CBOR encoder used by Cbor and NullableCbor,
+// for example with a fxamacker/cbor EncMode/DecMode pair. Passing nil restores the built-in one.
+// A plugged codec receives V directly, so JSON options do not apply to it.
+func CborCodec(codec Codec) Option {
+	return func(c *config) {
+		c.cborCodec = codec
+	}
+}
+
+var cborFormat = &binaryFormat{
+	name:     "CBOR",
+	null:     []byte{0xf6},
+	fromJSON: jsonToCBOR,
+	toJSON:   cborToJSON,
+	codec:    func(c *config) Codec { return c.cborCodec },
+}
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// jsonToCBOR converts a JSON document to CBOR, keeping the order of object keys.
+func jsonToCBOR(data []byte) ([]byte, error) {
+	node, err := parseJSONTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendCBOR(nil, node)
+}
+
+func appendCBOR(out []byte, node jsonNode) ([]byte, error) {
+	switch node.kind {
+	case 'n':
+		return append(out, 0xf6), nil
+	case 'b':
+		if node.bool {
+			return append(out, 0xf5), nil
+		}
+		return append(out, 0xf4), nil
+	case '#':
+		return appendCBORNumber(out, node.num)
+	case 's':
+		out = appendCBORHead(out, cborText, uint64(len(node.str)))
+		return append(out, node.str...), nil
+	case '[':
+		out = appendCBORHead(out, cborArray, uint64(len(node.elems)))
+	case '{':
+		out = appendCBORHead(out, cborMap, uint64(len(node.elems)))
+	}
+
+	var err error
+	for i, elem := range node.elems {
+		if node.kind == '{' {
+			out = appendCBORHead(out, cborText, uint64(len(node.keys[i])))
+			out = append(out, node.keys[i]...)
+		}
+		if out, err = appendCBOR(out, elem); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// appendCBORNumber encodes integers as CBOR integers, using bignum tags beyond 64 bits,
+// and all other numbers as float64.
+func appendCBORNumber(out []byte, num json.Number) ([]byte, error) {
+	s := num.String()
+	if strings.ContainsAny(s, ".eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cborSimple<<5|27)
+		return binary.BigEndian.AppendUint64(out, math.Float64bits(f)), nil
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if i < 0 {
+			return appendCBORHead(out, cborNegInt, uint64(-1-i)), nil
+		}
+		return appendCBORHead(out, cborUint, uint64(i)), nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return appendCBORHead(out, cborUint, u), nil
+	}
+
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("jsonsql: invalid number %q", s)
+	}
+	tag := uint64(2)
+	if n.Sign() < 0 {
+		tag = 3
+		n.Neg(n).Sub(n, big.NewInt(1))
+	}
+	out = appendCBORHead(out, cborTag, tag)
+	b := n.Bytes()
+	out = appendCBORHead(out, cborBytes, uint64(len(b)))
+	return append(out, b...), nil
+}
+
+func appendCBORHead(out []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(out, major<<5|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(out, major<<5|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, major<<5|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(out, major<<5|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(out, major<<5|27), arg)
+}
+
+// errCBORBreak is returned by cborDecoder.value when it reads the break stop code.
+var errCBORBreak = errors.New("unexpected break")
+
+// cborToJSON converts a single CBOR data item to JSON.
+// Byte strings become base64 strings, integer map keys become strings,
+// and undefined becomes null. Tags other than bignums are ignored.
+func cborToJSON(data []byte) ([]byte, error) {
+	d := &cborDecoder{data: data}
+	if err := d.value(0); err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, ErrTrailingData
+	}
+	return d.out.Bytes(), nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+	out  bytes.Buffer
+}
+
+// head reads the initial byte and argument of a data item.
+// indefinite is set for indefinite-length strings and containers.
+func (d *cborDecoder) head() (major, info byte, arg uint64, indefinite bool, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, false, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos]
+	d.pos++
+	major, info = b>>5, b&0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info == 31:
+		return major, info, 0, true, nil
+	case info > 27:
+		return 0, 0, 0, false, fmt.Errorf("invalid additional information %d", info)
+	}
+	size := 1 << (info - 24)
+	if len(d.data)-d.pos < size {
+		return 0, 0, 0, false, errors.New("unexpected end of data")
+	}
+	p := d.data[d.pos : d.pos+size]
+	d.pos += size
+	switch size {
+	case 1:
+		arg = uint64(p[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(p))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(p))
+	default:
+		arg = binary.BigEndian.Uint64(p)
+	}
+	return major, info, arg, false, nil
+}
+
+// take returns the next n bytes of data.
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of data")
+	}
+	p := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return p, nil
+}
+
+// str reads a byte or text string of the given major type, concatenating indefinite-length chunks.
+func (d *cborDecoder) str(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.take(arg)
+	}
+	var buf []byte
+	for {
+		m, info, n, indef, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if m == cborSimple && info == 31 {
+			return buf, nil
+		}
+		if m != major || indef {
+			return nil, errors.New("invalid chunk in indefinite-length string")
+		}
+		chunk, err := d.take(n)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, chunk...)
+	}
+}
+
+func (d *cborDecoder) value(depth int) error {
+	if depth > maxBinaryDepth {
+		return errBinaryTooDeep
+	}
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return err
+	}
+	if indefinite && major != cborBytes && major != cborText && major != cborArray && major != cborMap {
+		if major == cborSimple {
+			return errCBORBreak
+		}
+		return fmt.Errorf("invalid indefinite length for major type %d", major)
+	}
+
+	switch major {
+	case cborUint:
+		d.out.Write(strconv.AppendUint(nil, arg, 10))
+	case cborNegInt:
+		d.out.WriteString(cborNegative(arg).String())
+	case cborBytes, cborText:
+		s, err := d.str(major, arg, indefinite)
+		if err != nil {
+			return err
+		}
+		var enc []byte
+		if major == cborBytes {
+			enc, err = json.Marshal(s)
+		} else {
+			enc, err = json.Marshal(string(s))
+		}
+		if err != nil {
+			return err
+		}
+		d.out.Write(enc)
+	case cborArray:
+		d.out.WriteByte('[')
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if !indefinite && arg-i > uint64(len(d.data)-d.pos) {
+				return errors.New("unexpected end of data")
+			}
+			mark := d.out.Len()
+			if i > 0 {
+				d.out.WriteByte(',')
+			}
+			if err := d.value(depth + 1); err != nil {
+				if indefinite && err == errCBORBreak {
+					d.out.Truncate(mark)
+					break
+				}
+				return err
+			}
+		}
+		d.out.WriteByte(']')
+	case cborMap:
+		d.out.WriteByte('{')
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if !indefinite && arg-i > uint64(len(d.data)-d.pos) {
+				return errors.New("unexpected end of data")
+			}
+			key, err := d.key()
+			if err != nil {
+				if indefinite && err == errCBORBreak {
+					break
+				}
+				return err
+			}
+			if i > 0 {
+				d.out.WriteByte(',')
+			}
+			enc, _ := json.Marshal(key)
+			d.out.Write(enc)
+			d.out.WriteByte(':')
+			if err := d.value(depth + 1); err != nil {
+				if err == errCBORBreak {
+					return errors.New("missing map value")
+				}
+				return err
+			}
+		}
+		d.out.WriteByte('}')
+	case cborTag:
+		if arg == 2 || arg == 3 {
+			return d.bignum(arg == 3)
+		}
+		return d.value(depth + 1)
+	default:
+		return d.simple(info, arg)
+	}
+	return nil
+}
+
+// key reads a map key, which must be a text string or an integer.
+func (d *cborDecoder) key() (string, error) {
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return "", err
+	}
+	switch major {
+	case cborText:
+		s, err := d.str(major, arg, indefinite)
+		return string(s), err
+	case cborUint:
+		return strconv.FormatUint(arg, 10), nil
+	case cborNegInt:
+		return cborNegative(arg).String(), nil
+	case cborSimple:
+		if info == 31 {
+			return "", errCBORBreak
+		}
+	}
+	return "", fmt.Errorf("unsupported map key of major type %d", major)
+}
+
+// bignum reads the byte string following a bignum tag.
+func (d *cborDecoder) bignum(negative bool) error {
+	major, _, arg, indefinite, err := d.head()
+	if err != nil {
+		return err
+	}
+	if major != cborBytes {
+		return errors.New("bignum tag must enclose a byte string")
+	}
+	b, err := d.str(major, arg, indefinite)
+	if err != nil {
+		return err
+	}
+	n := new(big.Int).SetBytes(b)
+	if negative {
+		n.Add(n, big.NewInt(1)).Neg(n)
+	}
+	d.out.WriteString(n.String())
+	return nil
+}
+
+func (d *cborDecoder) simple(info byte, arg uint64) error {
+	var f float64
+	switch info {
+	case 20:
+		d.out.WriteString("false")
+		return nil
+	case 21:
+		d.out.WriteString("true")
+		return nil
+	case 22, 23:
+		d.out.WriteString("null")
+		return nil
+	case 25:
+		f = halfToFloat(uint16(arg))
+	case 26:
+		f = float64(math.Float32frombits(uint32(arg)))
+	case 27:
+		f = math.Float64frombits(arg)
+	default:
+		return fmt.Errorf("unsupported simple value %d", arg)
+	}
+	return writeJSONFloat(&d.out, f)
+}
+
+// cborNegative returns the value -1-arg of a CBOR negative integer.
+func cborNegative(arg uint64) *big.Int {
+	n := new(big.Int).SetUint64(arg)
+	return n.Add(n, big.NewInt(1)).Neg(n)
+}
+
+// halfToFloat converts an IEEE 754 half-precision float to float64.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,243 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCbor_RoundTrip(t *testing.T) {
+	v := NewCbor(testProfile{Name: "Alice", Email: "alice@example.com"})
+
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Cbor[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V != v.V {
+		t.Errorf("expected %+v, got %+v", v.V, scanned.V)
+	}
+}
+
+func TestCbor_Encoding(t *testing.T) {
+	result, err := NewCbor(map[string]any{"a": 1, "b": []any{true, nil, -2, "x"}}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'b', 0x84, 0xf5, 0xf6, 0x21, 0x61, 'x'}
+	if !bytes.Equal(result.([]byte), expected) {
+		t.Errorf("expected % x, got % x", expected, result)
+	}
+}
+
+func TestCbor_Numbers(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](UseNumber(true))
+
+	input := map[string]any{
+		"big":   json.Number("123456789012345678901234567890"),
+		"neg":   json.Number("-123456789012345678901234567890"),
+		"max":   json.Number("18446744073709551615"),
+		"min":   json.Number("-9223372036854775808"),
+		"float": json.Number("1.5"),
+	}
+	result, err := NewCbor(input).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Cbor[map[string]any]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	for k, want := range input {
+		if scanned.V[k] != want {
+			t.Errorf("%s: expected %v, got %v", k, want, scanned.V[k])
+		}
+	}
+}
+
+func TestCbor_DecodeForeign(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"half float", []byte{0xf9, 0x3e, 0x00}, `1.5`},
+		{"single float", []byte{0xfa, 0x47, 0xc3, 0x50, 0x00}, `100000`},
+		{"byte string", []byte{0x43, 0x01, 0x02, 0x03}, `"AQID"`},
+		{"indefinite text", []byte{0x7f, 0x62, 'a', 'b', 0x61, 'c', 0xff}, `"abc"`},
+		{"indefinite array", []byte{0x9f, 0x01, 0x02, 0xff}, `[1,2]`},
+		{"indefinite map", []byte{0xbf, 0x61, 'a', 0x01, 0xff}, `{"a":1}`},
+		{"integer keys", []byte{0xa1, 0x20, 0xf7}, `{"-1":null}`},
+		{"ignored tag", []byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, `1363896240`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Cbor[json.RawMessage]
+			if err := v.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if string(v.V) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, v.V)
+			}
+		})
+	}
+}
+
+func TestCbor_InvalidData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"truncated", []byte{0x82, 0x01}},
+		{"trailing data", []byte{0x01, 0x02}},
+		{"stray break", []byte{0xff}},
+		{"huge length", []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"NaN", []byte{0xf9, 0x7e, 0x00}},
+		{"array key", []byte{0xa1, 0x80, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Cbor[any]
+			if err := v.Scan(tt.input); err == nil {
+				t.Errorf("expected error, got %v", v.V)
+			}
+		})
+	}
+}
+
+func TestCbor_Null(t *testing.T) {
+	var v Cbor[testProfile]
+	if err := v.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for nil, got %v", err)
+	}
+	if err := v.Scan([]byte{0xf6}); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for CBOR null, got %v", err)
+	}
+	if err := v.Scan([]byte{}); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+}
+
+func TestNullableCbor(t *testing.T) {
+	result, err := NullableCbor[testProfile]{}.Value()
+	if err != nil || result != nil {
+		t.Fatalf("expected nil for invalid value, got %v, %v", result, err)
+	}
+
+	result, err = NullableCborFrom(testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var n NullableCbor[testProfile]
+	if err := n.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V.Name != "Bob" {
+		t.Errorf("expected valid Bob, got %+v", n)
+	}
+
+	for _, src := range []any{nil, []byte{}, []byte{0xf6}} {
+		n := NullableCborFrom(testProfile{Name: "Bob"})
+		if err := n.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) failed: %v", src, err)
+		}
+		if n.Valid || n.V.Name != "" {
+			t.Errorf("Scan(%v): expected null, got %+v", src, n)
+		}
+	}
+}
+
+type testCodec struct{}
+
+func (testCodec) Marshal(v any) ([]byte, error) {
+	return append([]byte("codec:"), v.(string)...), nil
+}
+
+func (testCodec) Unmarshal(data []byte, v any) error {
+	*v.(*string) = string(bytes.TrimPrefix(data, []byte("codec:")))
+	return nil
+}
+
+func TestCborCodec(t *testing.T) {
+	resetOptions[string](t)
+	Configure[string](CborCodec(testCodec{}))
+
+	result, err := NewCbor("hello").Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != "codec:hello" {
+		t.Errorf("unexpected result: %s", result)
+	}
+
+	var v Cbor[string]
+	if err := v.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V != "hello" {
+		t.Errorf("expected hello, got %s", v.V)
+	}
+}
+
+func TestCbor_Checks(t *testing.T) {
+	resetOptions[testProfile](t)
+	var scans, values int
+	Configure[testProfile](MaxDocumentSize(20), Observe(Hooks{
+		OnScan:  func(HookEvent) { scans++ },
+		OnValue: func(HookEvent) { values++ },
+	}))
+
+	data, err := NewCbor(testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var v Cbor[testProfile]
+	if err := v.Scan(data); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, err := NewCbor(testProfile{Name: "Bob", Email: "bob@example.com"}).Value(); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if scans != 1 || values != 2 {
+		t.Errorf("expected 1 scan and 2 values reported, got %d and %d", scans, values)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := v.ScanContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNullableCbor_Accessors(t *testing.T) {
+	n := NullCbor[testProfile]()
+	if n.ToPtr() != nil {
+		t.Error("expected nil pointer for NULL")
+	}
+	n.Replace(func(p testProfile) testProfile {
+		p.Name = "Bob"
+		return p
+	})
+	if p := n.ToPtr(); p == nil || p.Name != "Bob" {
+		t.Errorf("expected Bob, got %+v", n)
+	}
+	n.SetNull()
+	if _, err := n.TryGet(); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if NullableCborFromPtr[testProfile](nil).Valid || !NewNullableCbor(testProfile{}, true).Valid {
+		t.Error("unexpected Valid flags")
+	}
+}
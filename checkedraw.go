@@ -0,0 +1,128 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner      = (*CheckedRaw)(nil)
+	_ ContextScanner   = (*CheckedRaw)(nil)
+	_ driver.Valuer    = CheckedRaw(nil)
+	_ json.Marshaler   = CheckedRaw(nil)
+	_ json.Unmarshaler = (*CheckedRaw)(nil)
+)
+
+// CheckedRaw is a JSON document that Scan checks for well-formedness without decoding it,
+// for gateway services that pass documents through and only need to reject corrupt rows
+// quickly. Decoding is deferred to DecodeChecked.
+//
+// A nil CheckedRaw represents SQL NULL; the JSON literal null is kept as a document.
+// MaxDocumentSize registered with SetDefaults or Configure[CheckedRaw] applies to Scan.
+type CheckedRaw []byte
+
+// Scan implements sql.Scanner interface.
+// It copies the document into the existing buffer of r after checking that it is a single
+// well-formed JSON value, failing with ErrInvalidJSON otherwise and with ErrEmptyInput for
+// empty input. SQL NULL scans as nil.
+func (r *CheckedRaw) Scan(src any) error {
+	return r.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (r *CheckedRaw) ScanContext(ctx context.Context, src any) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("jsonsql.CheckedRaw.Scan: %w", err)
+	}
+	src, err := resolveSource(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.CheckedRaw.Scan: %w", err)
+	}
+	if src == nil {
+		*r = nil
+		return nil
+	}
+	var data []byte
+	switch s := src.(type) {
+	case json.RawMessage:
+		data = s
+	default:
+		if data, err = sourceBytes(src); err != nil {
+			return fmt.Errorf("jsonsql.CheckedRaw.Scan: %w", err)
+		}
+	}
+	if err := checkSize(len(data), configFor[CheckedRaw]()); err != nil {
+		return fmt.Errorf("jsonsql.CheckedRaw.Scan: %w", err)
+	}
+	if err := r.set(data); err != nil {
+		return fmt.Errorf("jsonsql.CheckedRaw.Scan: %w", err)
+	}
+	return nil
+}
+
+// set stores the trimmed document data in r after checking it.
+func (r *CheckedRaw) set(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return ErrEmptyInput
+	}
+	if !json.Valid(data) {
+		// Decode again only to report the location of the error.
+		return invalidJSON(json.Unmarshal(data, new(json.RawMessage)))
+	}
+	*r = append((*r)[:0], data...)
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It returns the document unchanged, compacted with CompactOnWrite, or nil (NULL) for
+// a nil CheckedRaw.
+func (r CheckedRaw) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+	cfg := configFor[CheckedRaw]()
+	data := []byte(r)
+	if cfg.compact {
+		var err error
+		if data, err = compactDocument(data); err != nil {
+			return nil, fmt.Errorf("jsonsql.CheckedRaw.Value: %w", err)
+		}
+	} else if !json.Valid(data) {
+		return nil, fmt.Errorf("jsonsql.CheckedRaw.Value: %w", ErrInvalidJSON)
+	}
+	return cfg.driverValue(data), nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// A nil CheckedRaw is encoded as null.
+func (r CheckedRaw) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+	return r, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (r *CheckedRaw) UnmarshalJSON(data []byte) error {
+	return r.set(data)
+}
+
+// DecodeChecked decodes the document held by r into a T with the options registered for T,
+// like Value[T].Scan. It returns ErrNullNotAllowed for SQL NULL and the JSON literal null.
+func DecodeChecked[T any](r CheckedRaw) (T, error) {
+	var v T
+	if r == nil {
+		return v, fmt.Errorf("jsonsql.DecodeChecked: %w", ErrNullNotAllowed)
+	}
+	if err := decodeRaw([]byte(r), &v); err != nil {
+		return v, fmt.Errorf("jsonsql.DecodeChecked: %w", err)
+	}
+	return v, nil
+}
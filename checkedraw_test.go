@@ -0,0 +1,94 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCheckedRaw_Scan(t *testing.T) {
+	var r CheckedRaw
+	if err := r.Scan([]byte(` {"name":"Alice","email":"alice@example.com"} `)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if string(r) != `{"name":"Alice","email":"alice@example.com"}` {
+		t.Errorf("unexpected document %s", r)
+	}
+
+	p, err := DecodeChecked[testProfile](r)
+	if err != nil {
+		t.Fatalf("DecodeChecked failed: %v", err)
+	}
+	if p.Name != "Alice" || p.Email != "alice@example.com" {
+		t.Errorf("unexpected value %+v", p)
+	}
+
+	result, err := r.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != string(r) {
+		t.Errorf("expected document unchanged, got %s", result)
+	}
+
+	for _, bad := range []string{`{"name":`, `{} {}`, `{'a':1}`} {
+		if err := r.Scan(bad); !errors.Is(err, ErrInvalidJSON) {
+			t.Errorf("Scan(%s): expected ErrInvalidJSON, got %v", bad, err)
+		}
+	}
+	if err := r.Scan(""); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+	if err := r.Scan(42); err == nil {
+		t.Error("expected error for unsupported source type")
+	}
+}
+
+func TestCheckedRaw_Null(t *testing.T) {
+	r := CheckedRaw(`{}`)
+	if err := r.Scan(nil); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if r != nil {
+		t.Errorf("expected nil, got %s", r)
+	}
+	if result, err := r.Value(); err != nil || result != nil {
+		t.Errorf("expected NULL, got %v, %v", result, err)
+	}
+	if _, err := DecodeChecked[testProfile](r); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+
+	if err := r.Scan("null"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if string(r) != "null" {
+		t.Errorf("expected JSON null to be kept, got %s", r)
+	}
+}
+
+func TestCheckedRaw_MaxDocumentSize(t *testing.T) {
+	resetOptions[CheckedRaw](t)
+	Configure[CheckedRaw](MaxDocumentSize(8))
+
+	var r CheckedRaw
+	if err := r.Scan(`{"name":"Alice"}`); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestCheckedRaw_JSON(t *testing.T) {
+	var doc struct {
+		Payload CheckedRaw `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(`{"payload": [1, 2]}`), &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"payload":[1,2]}` {
+		t.Errorf("unexpected document %s", data)
+	}
+}
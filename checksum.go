@@ -0,0 +1,98 @@
+package jsonsql
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// ErrChecksumMismatch is returned by VerifyChecksum when a document does not match its stored checksum.
+var ErrChecksumMismatch = permanent("jsonsql: document does not match its checksum")
+
+// ChecksumHash sets the hash function used by ValueWithChecksum and VerifyChecksum.
+// The default is SHA-256.
+func ChecksumHash(newHash func() hash.Hash) Option {
+	return func(c *config) {
+		c.checksumHash = newHash
+	}
+}
+
+// checksum returns the lowercase hex checksum of the stored document data according to c.
+func (c *config) checksum(data []byte) string {
+	newHash := c.checksumHash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	h := newHash()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ValueWithChecksum returns the driver value of v together with the lowercase hex checksum
+// of the stored bytes, for schemas that keep a hash column next to the JSON column:
+//
+//	doc, sum, err := row.Settings.ValueWithChecksum()
+//	_, err = db.Exec(`UPDATE users SET settings = $1, settings_sha256 = $2 WHERE id = $3`, doc, sum, id)
+//
+// The checksum covers exactly the bytes written, after all options and codecs are applied.
+func (v Value[T]) ValueWithChecksum() (doc, sum driver.Value, err error) {
+	cfg := configFor[T]()
+	data, err := encodeValue(v.V, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsonsql.Value.ValueWithChecksum: %w", err)
+	}
+	return cfg.driverValue(data), cfg.checksum(data), nil
+}
+
+// ValueWithChecksum behaves like Value[T].ValueWithChecksum. When n is NULL and stored as SQL NULL,
+// both values are nil; a document configured with NullAsJSON or NullAsEmptyObject is checksummed.
+func (n Nullable[T]) ValueWithChecksum() (doc, sum driver.Value, err error) {
+	if err := checkNullableParam[T](); err != nil {
+		return nil, nil, fmt.Errorf("jsonsql.Nullable.ValueWithChecksum: %w", err)
+	}
+	cfg := configFor[T]()
+	if !n.Valid {
+		if cfg.nullDocument == nil {
+			return nil, nil, nil
+		}
+		return cfg.nullValue(), cfg.checksum(cfg.nullDocument), nil
+	}
+	data, err := encodeValue(n.V, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsonsql.Nullable.ValueWithChecksum: %w", err)
+	}
+	return cfg.driverValue(data), cfg.checksum(data), nil
+}
+
+// VerifyChecksum reports whether the stored document src matches the checksum sum written by
+// ValueWithChecksum, using the hash function configured for T. Both may be []byte or string as
+// returned by the driver; a NULL document matches a NULL checksum. It returns ErrChecksumMismatch
+// when they differ:
+//
+//	var doc, sum []byte
+//	err := db.QueryRow(`SELECT settings, settings_sha256 FROM users WHERE id = $1`, id).Scan(&doc, &sum)
+//	if err := jsonsql.VerifyChecksum[Settings](doc, sum); err != nil { ... }
+func VerifyChecksum[T any](src, sum any) error {
+	if src == nil || sum == nil {
+		if src == nil && sum == nil {
+			return nil
+		}
+		return ErrChecksumMismatch
+	}
+	data, err := sourceBytes(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.VerifyChecksum: %w", err)
+	}
+	expected, err := sourceBytes(sum)
+	if err != nil {
+		return fmt.Errorf("jsonsql.VerifyChecksum: %w", err)
+	}
+	actual := configFor[T]().checksum(data)
+	if subtle.ConstantTimeCompare([]byte(actual), expected) != 1 {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
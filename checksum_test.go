@@ -0,0 +1,66 @@
+package jsonsql
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestValueWithChecksum(t *testing.T) {
+	doc, sum, err := NewValue(testProfile{Name: "Alice", Email: "alice@example.com"}).ValueWithChecksum()
+	if err != nil {
+		t.Fatalf("ValueWithChecksum failed: %v", err)
+	}
+	data := doc.([]byte)
+	digest := sha256.Sum256(data)
+	if sum != hex.EncodeToString(digest[:]) {
+		t.Errorf("unexpected checksum %v", sum)
+	}
+	if err := VerifyChecksum[testProfile](data, sum); err != nil {
+		t.Errorf("VerifyChecksum failed: %v", err)
+	}
+	if err := VerifyChecksum[testProfile](string(data), []byte(sum.(string))); err != nil {
+		t.Errorf("VerifyChecksum with string source failed: %v", err)
+	}
+	if err := VerifyChecksum[testProfile](`{"name":"Mallory","email":"alice@example.com"}`, sum); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if err := VerifyChecksum[testProfile](data, nil); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for NULL checksum, got %v", err)
+	}
+	if err := VerifyChecksum[testProfile](nil, nil); err != nil {
+		t.Errorf("NULL document should match NULL checksum: %v", err)
+	}
+	if err := VerifyChecksum[testProfile](42, sum); !errors.Is(err, ErrUnsupportedSrcType) {
+		t.Errorf("expected ErrUnsupportedSrcType, got %v", err)
+	}
+}
+
+func TestValueWithChecksum_Nullable(t *testing.T) {
+	resetOptions[testProfile](t)
+
+	doc, sum, err := Null[testProfile]().ValueWithChecksum()
+	if err != nil || doc != nil || sum != nil {
+		t.Errorf("expected nil, nil, nil for NULL, got %v, %v, %v", doc, sum, err)
+	}
+
+	Configure[testProfile](NullAsJSON(true), ChecksumHash(md5.New))
+	doc, sum, err = Null[testProfile]().ValueWithChecksum()
+	if err != nil {
+		t.Fatalf("ValueWithChecksum failed: %v", err)
+	}
+	digest := md5.Sum([]byte("null"))
+	if string(doc.([]byte)) != "null" || sum != hex.EncodeToString(digest[:]) {
+		t.Errorf("unexpected result %s, %v", doc, sum)
+	}
+
+	doc, sum, err = NullableFrom(testProfile{Name: "Bob"}).ValueWithChecksum()
+	if err != nil {
+		t.Fatalf("ValueWithChecksum failed: %v", err)
+	}
+	if err := VerifyChecksum[testProfile](doc, sum); err != nil {
+		t.Errorf("VerifyChecksum failed: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+// Command jsonsql checks and maintains JSON columns from the command line.
+//
+// This build includes the pgx, mysql and sqlite drivers and registers the generic types
+// "any" (any JSON value) and "object" (JSON objects), which detect documents that are not
+// valid JSON or not objects. To check documents against your own Go types, build a variant
+// registering them; see package github.com/jinford/jsonsql/jsonsqlcli.
+//
+//	jsonsql validate -driver pgx -dsn "$DSN" -table users -column settings -type object
+package main
+
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jinford/jsonsql/jsonsqlcli"
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	jsonsqlcli.Register[any]("any")
+	jsonsqlcli.Register[map[string]any]("object")
+	jsonsqlcli.Main()
+}
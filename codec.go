@@ -0,0 +1,583 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// EmptyPolicy selects how Scan handles empty input (an empty or whitespace-only []byte or string).
+type EmptyPolicy int
+
+const (
+	// EmptyDefault keeps the default of each wrapper: Nullable[T] scans empty input as NULL,
+	// Value[T] fails with ErrEmptyInput.
+	EmptyDefault EmptyPolicy = iota
+	// ErrorOnEmpty makes Scan fail with ErrEmptyInput.
+	ErrorOnEmpty
+	// NullOnEmpty treats empty input like NULL: Nullable[T] becomes Valid=false
+	// and Value[T] fails with ErrNullNotAllowed.
+	NullOnEmpty
+	// ZeroOnEmpty scans empty input as the zero value of T (Valid=true for Nullable[T]).
+	ZeroOnEmpty
+)
+
+// ErrEmptyInput is returned by Scan for empty input under the ErrorOnEmpty policy.
+var ErrEmptyInput = permanent("jsonsql: empty JSON input")
+
+// EmptyInput sets the policy Scan applies to empty input, typically found in legacy columns.
+func EmptyInput(policy EmptyPolicy) Option {
+	return func(c *config) {
+		c.emptyPolicy = policy
+	}
+}
+
+// EmptyObjectAsNull makes Scan treat the empty JSON object {} like JSON null, for schemas where
+// an empty document means the value is absent: Nullable[T] becomes Valid=false and Value[T] fails
+// with ErrNullNotAllowed. Combine it with NullAsEmptyObject to write invalid values back as {}.
+func EmptyObjectAsNull(enabled bool) Option {
+	return func(c *config) {
+		c.emptyObjectNull = enabled
+	}
+}
+
+// EmptyArrayAsNull makes Scan treat the empty JSON array [] like JSON null; see EmptyObjectAsNull.
+func EmptyArrayAsNull(enabled bool) Option {
+	return func(c *config) {
+		c.emptyArrayNull = enabled
+	}
+}
+
+// isEmptyDocumentNull reports whether data is an empty object or array that cfg treats as null.
+func isEmptyDocumentNull(data []byte, cfg *config) bool {
+	if !cfg.emptyObjectNull && !cfg.emptyArrayNull {
+		return false
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || len(bytes.TrimSpace(data[1:len(data)-1])) != 0 {
+		return false
+	}
+	switch {
+	case data[0] == '{' && data[len(data)-1] == '}':
+		return cfg.emptyObjectNull
+	case data[0] == '[' && data[len(data)-1] == ']':
+		return cfg.emptyArrayNull
+	}
+	return false
+}
+
+// ErrTrailingData is returned by Scan when non-whitespace data follows the JSON value,
+// which usually indicates a corrupted row. See AllowTrailingData.
+var ErrTrailingData = permanent("jsonsql: unexpected data after JSON value")
+
+// maxSourceDepth limits how many driver.Valuer indirections resolveSource follows.
+const maxSourceDepth = 8
+
+// resolveSource unwraps source values produced by third-party drivers and wrappers
+// (sql.RawBytes, *[]byte, *string, driver.Valuer, fmt.Stringer and named byte slice or
+// string types) into a plain source value.
+// nil pointers resolve to nil.
+func resolveSource(src any) (any, error) {
+	for range maxSourceDepth {
+		switch s := src.(type) {
+		case nil, []byte, string, json.RawMessage:
+			return src, nil
+		case sql.RawBytes:
+			return []byte(s), nil
+		case *[]byte:
+			if s == nil {
+				return nil, nil
+			}
+			return *s, nil
+		case *string:
+			if s == nil {
+				return nil, nil
+			}
+			return *s, nil
+		case time.Time:
+			// time.Time implements fmt.Stringer but is a native driver scalar.
+			return src, nil
+		}
+
+		rv := reflect.ValueOf(src)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, nil
+		}
+
+		switch s := src.(type) {
+		case driver.Valuer:
+			v, err := s.Value()
+			if err != nil {
+				return nil, err
+			}
+			src = v
+			continue
+		case fmt.Stringer:
+			return s.String(), nil
+		}
+
+		// Named byte slice and string types used by other libraries for raw JSON,
+		// such as sqlx types.JSONText without its Valuer.
+		switch {
+		case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+			return rv.Bytes(), nil
+		case rv.Kind() == reflect.String:
+			return rv.String(), nil
+		default:
+			return src, nil
+		}
+	}
+	return nil, fmt.Errorf("jsonsql: too many driver.Valuer indirections for %T", src)
+}
+
+// jsonBytes extracts the raw JSON bytes from a database source value scanned into a T.
+func jsonBytes[T any](src any, cfg *config) ([]byte, error) {
+	switch s := src.(type) {
+	case []byte:
+		return s, nil
+	case string:
+		return []byte(s), nil
+	case json.RawMessage:
+		return s, nil
+	}
+	if cfg.coerceScalars {
+		if data, ok := coerceScalar(src, reflect.TypeFor[T]()); ok {
+			return data, nil
+		}
+	}
+	if isStructuredSource(src) {
+		return json.Marshal(src)
+	}
+	return nil, unsupportedSrcType(src)
+}
+
+// isJSONNull reports whether data is the JSON literal null (with optional whitespace).
+func isJSONNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// decodeSource decodes a database source value into v according to the options registered for T.
+// It reports null=true, leaving v untouched, when the source represents NULL:
+// SQL NULL, JSON literal null, or empty input under the NullOnEmpty policy.
+// emptyDefault is the wrapper's policy for empty input when none is configured.
+func decodeSource[T any](src any, v *T, emptyDefault EmptyPolicy) (null bool, err error) {
+	return decodeSourceContext(context.Background(), src, v, emptyDefault)
+}
+
+// decodeSourceContext is like decodeSource for the ScanContext methods. It fails with the
+// context error if ctx is done before decoding starts, and passes ctx to the observability hooks.
+func decodeSourceContext[T any](ctx context.Context, src any, v *T, emptyDefault EmptyPolicy) (null bool, err error) {
+	return decodeSourceConfig(ctx, src, v, configFor[T](), emptyDefault)
+}
+
+// decodeSourceConfig is like decodeSourceContext with the config cfg instead of the options
+// registered for T.
+func decodeSourceConfig[T any](ctx context.Context, src any, v *T, cfg *config, emptyDefault EmptyPolicy) (null bool, err error) {
+	return decodeSourceWith(ctx, src, v, cfg, emptyDefault, decodePayload[T])
+}
+
+// payloadDecoder decodes a resolved source value into v according to cfg, as decodePayload
+// does for JSON documents.
+type payloadDecoder[T any] func(src any, v *T, cfg *config, emptyDefault EmptyPolicy) (null bool, err error)
+
+// decodeSourceWith is like decodeSourceConfig with decode instead of decodePayload, for wrappers
+// storing other document formats.
+func decodeSourceWith[T any](ctx context.Context, src any, v *T, cfg *config, emptyDefault EmptyPolicy, decode payloadDecoder[T]) (null bool, err error) {
+	return decodeSourceLogged(ctx, src, v, cfg, emptyDefault, decode, true)
+}
+
+// decodeSourceLogged is like decodeSourceWith. logPayload reports whether a failed decode may
+// dump the payload to the DebugLog logger; wrappers holding secrets pass false.
+func decodeSourceLogged[T any](ctx context.Context, src any, v *T, cfg *config, emptyDefault EmptyPolicy, decode payloadDecoder[T], logPayload bool) (null bool, err error) {
+	if !cfg.observer.active() && cfg.debugLogger == nil {
+		_, null, err = decodeChecked(ctx, src, v, cfg, emptyDefault, decode)
+		return null, err
+	}
+	start := time.Now()
+	src, null, err = decodeChecked(ctx, src, v, cfg, emptyDefault, decode)
+	if err != nil && cfg.debugLogger != nil {
+		logDecodeFailure(ctx, cfg, reflect.TypeFor[T](), src, err, logPayload)
+	}
+	cfg.observer.report(HookEvent{
+		Context:  ctx,
+		Op:       OpScan,
+		Type:     reflect.TypeFor[T]().String(),
+		Size:     payloadSize(src),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return null, err
+}
+
+// decodeChecked resolves src and decodes it into v with decode after checking ctx and the type
+// parameter. It returns the resolved source value, or src itself if it could not be resolved.
+func decodeChecked[T any](ctx context.Context, src any, v *T, cfg *config, emptyDefault EmptyPolicy, decode payloadDecoder[T]) (resolved any, null bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return src, false, err
+	}
+	resolved, err = resolveSource(src)
+	if err != nil {
+		return src, false, err
+	}
+	if cfg.typeErr != nil {
+		return resolved, false, cfg.typeErr
+	}
+	null, err = decodeDocumentWith(resolved, v, cfg, emptyDefault, decode)
+	return resolved, null, err
+}
+
+// decodeDocument decodes a resolved source value into v according to cfg. See decodeSource.
+func decodeDocument[T any](src any, v *T, cfg *config, emptyDefault EmptyPolicy) (null bool, err error) {
+	return decodeDocumentWith(src, v, cfg, emptyDefault, decodePayload[T])
+}
+
+// decodeDocumentWith is like decodeDocument with decode instead of decodePayload.
+func decodeDocumentWith[T any](src any, v *T, cfg *config, emptyDefault EmptyPolicy, decode payloadDecoder[T]) (null bool, err error) {
+	if err := checkSize(payloadSize(src), cfg); err != nil {
+		return false, err
+	}
+	null, err = decode(src, v, cfg, emptyDefault)
+	if err != nil || null || !cfg.validate {
+		return null, err
+	}
+	return false, validate(v)
+}
+
+// decodePayload decodes a resolved source value into v according to cfg, without the checks
+// of decodeDocument.
+func decodePayload[T any](src any, v *T, cfg *config, emptyDefault EmptyPolicy) (null bool, err error) {
+	if src == nil {
+		return true, nil
+	}
+	if cfg.documentCodec != nil {
+		return decodeCodec(src, v, cfg, emptyDefault)
+	}
+	data, err := jsonBytes[T](src, cfg)
+	if err != nil {
+		return false, err
+	}
+	switch src.(type) {
+	case []byte, string, json.RawMessage:
+		// Coerced scalars are already JSON.
+		if cfg.extractMode != ExtractJSON {
+			data = extracted(data, reflect.TypeFor[T](), cfg.extractMode)
+		}
+	}
+
+	data, err = normalize(data, cfg)
+	if err != nil {
+		return false, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return scanEmpty(v, cfg, emptyDefault)
+	}
+
+	// JSON literal null (with optional whitespace)
+	if isJSONNull(data) || isEmptyDocumentNull(data, cfg) {
+		return true, nil
+	}
+
+	if cfg.rejectDuplicates {
+		if err := checkDuplicateKeys(data); err != nil {
+			return false, err
+		}
+	}
+	if raw, ok := any(v).(*json.RawMessage); ok && scanRaw(data, raw, cfg) {
+		return false, nil
+	}
+	if !cfg.walks() && decodeScalar(data, v) {
+		return false, nil
+	}
+	if cfg.resetOnScan {
+		var zero T
+		*v = zero
+	}
+	if cfg.skipsElements(reflect.TypeFor[T]()) {
+		return false, unmarshalElements(data, v, cfg)
+	}
+	err = unmarshal(data, v, cfg)
+	if err != nil && !cfg.collectErrors {
+		return false, err
+	}
+	if rerr := checkRequired(data, reflect.TypeFor[T](), cfg); rerr != nil {
+		return false, errors.Join(err, rerr)
+	}
+	return false, err
+}
+
+// scanEmpty applies the empty input policy configured in cfg, or emptyDefault, to v.
+func scanEmpty[T any](v *T, cfg *config, emptyDefault EmptyPolicy) (null bool, err error) {
+	policy := cfg.emptyPolicy
+	if policy == EmptyDefault {
+		policy = emptyDefault
+	}
+	switch policy {
+	case NullOnEmpty:
+		return true, nil
+	case ZeroOnEmpty:
+		var zero T
+		*v = zero
+		return false, nil
+	default:
+		return false, ErrEmptyInput
+	}
+}
+
+// normalize prepares scanned data for decoding according to cfg.
+func normalize(data []byte, cfg *config) ([]byte, error) {
+	data, err := normalizeSyntax(data, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.localKeys != KeysAsIs {
+		data = transformKeys(data, cfg.localKeys)
+	}
+	return data, nil
+}
+
+// normalizeSyntax is like normalize without the key transformation, turning scanned data into
+// standard JSON holding the stored keys.
+func normalizeSyntax(data []byte, cfg *config) ([]byte, error) {
+	if hasNonASCIIPrefix(data) {
+		var err error
+		if data, err = trimPrefix(data, cfg.prefixPolicy); err != nil {
+			return nil, err
+		}
+	}
+	data, err := checkUTF8(data, cfg.utf8Policy)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.lenient {
+		if data, err = standardize(data); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+		}
+	}
+	return data, nil
+}
+
+// unmarshal decodes data into v, which must be a non-nil pointer, according to cfg.
+func unmarshal(data []byte, v any, cfg *config) error {
+	// The walker and json.Decoder do not reject data after the first value themselves.
+	slow := cfg.walks() || cfg.useNumber
+	if slow {
+		var err error
+		if data, err = trimTrailing(data, cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.walks() && json.Valid(data) {
+		rv := reflect.ValueOf(v).Elem()
+		if cfg.needsWalk(rv.Type()) {
+			w := &walker{cfg: cfg}
+			if err := w.decode(bytes.TrimSpace(data), rv); err != nil {
+				return err
+			}
+			return errors.Join(w.errs...)
+		}
+	}
+
+	err := decodeJSON(data, v, cfg)
+	var syntaxErr *json.SyntaxError
+	if !slow && errors.As(err, &syntaxErr) {
+		// json.Unmarshal validates the input before decoding, so v is untouched
+		// and can be decoded again from the first value alone.
+		trimmed, terr := trimTrailing(data, cfg)
+		if terr != nil {
+			return terr
+		}
+		if len(trimmed) != len(data) {
+			return invalidJSON(locateError(trimmed, decodeJSON(trimmed, v, cfg)))
+		}
+	}
+	return invalidJSON(locateError(data, err))
+}
+
+// trimTrailing handles non-whitespace data after the first JSON value in data.
+// It returns ErrTrailingData, or data cut after the first value when trailing data is allowed.
+// data is returned unchanged when it is not a complete value followed by extra data.
+func trimTrailing(data []byte, cfg *config) ([]byte, error) {
+	if json.Valid(data) {
+		// A valid document is a single value; Valid does not allocate, unlike a Decoder.
+		return data, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return data, nil
+	}
+	end := int(dec.InputOffset())
+	if len(bytes.TrimSpace(data[end:])) == 0 {
+		return data, nil
+	}
+	if !cfg.allowTrailing {
+		return nil, fmt.Errorf("%w at offset %d", ErrTrailingData, end)
+	}
+	return data[:end], nil
+}
+
+// encodeValue encodes v for storage by the wrappers, using the DocumentCodec configured in cfg
+// or JSON otherwise.
+func encodeValue[T any](v T, cfg *config) ([]byte, error) {
+	return encodeValueWith(context.Background(), v, cfg, encodePayload)
+}
+
+// payloadEncoder encodes v according to cfg, as encodePayload does for JSON documents.
+type payloadEncoder func(v any, cfg *config) ([]byte, error)
+
+// encodeValueWith is like encodeValue with encode instead of encodePayload, for wrappers
+// storing other document formats. ctx is reported to the observability hooks.
+func encodeValueWith[T any](ctx context.Context, v T, cfg *config, encode payloadEncoder) ([]byte, error) {
+	if !cfg.observer.active() {
+		return encodeChecked(v, cfg, encode)
+	}
+	start := time.Now()
+	data, err := encodeChecked(v, cfg, encode)
+	cfg.observer.report(HookEvent{
+		Context:  ctx,
+		Op:       OpValue,
+		Type:     reflect.TypeFor[T]().String(),
+		Size:     len(data),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return data, err
+}
+
+// reportNull reports a NULL write of a T to the observability hooks. data is the stored
+// document, or nil for SQL NULL.
+func reportNull[T any](cfg *config, data []byte) {
+	if !cfg.observer.active() {
+		return
+	}
+	cfg.observer.report(HookEvent{
+		Context: context.Background(),
+		Op:      OpValue,
+		Type:    reflect.TypeFor[T]().String(),
+		Size:    len(data),
+	})
+}
+
+// encodeChecked encodes v with encode after checking the type parameter.
+func encodeChecked(v any, cfg *config, encode payloadEncoder) ([]byte, error) {
+	if cfg.typeErr != nil {
+		return nil, cfg.typeErr
+	}
+	return encodeDocumentWith(v, cfg, encode)
+}
+
+// encodeDocument encodes v like encodeValue without reporting to the observability hooks.
+func encodeDocument(v any, cfg *config) ([]byte, error) {
+	return encodeDocumentWith(v, cfg, encodePayload)
+}
+
+// encodeDocumentWith is like encodeDocument with encode instead of encodePayload.
+func encodeDocumentWith(v any, cfg *config, encode payloadEncoder) ([]byte, error) {
+	if cfg.validate {
+		if err := validate(v); err != nil {
+			return nil, err
+		}
+	}
+	data, err := encode(v, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(len(data), cfg); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// encodePayload encodes v with the DocumentCodec configured in cfg, or as JSON.
+func encodePayload(v any, cfg *config) ([]byte, error) {
+	if cfg.documentCodec != nil {
+		return cfg.documentCodec.Marshal(v)
+	}
+	return marshal(v, cfg)
+}
+
+// plainJSON reports whether cfg encodes with encoding/json alone, so that marshal is
+// equivalent to encodeJSON and the observability hooks are not used.
+// It must be kept in sync with encodeValue and marshal.
+func (c *config) plainJSON() bool {
+	return c.documentCodec == nil && c.typeErr == nil && !c.observer.active() && !c.walks() &&
+		c.storedKeys == KeysAsIs && c.nulMode == NULKeep && !c.sanitizeUTF8 && c.indent == "" &&
+		c.maxSize <= 0 && !c.validate
+}
+
+// marshal encodes v according to cfg.
+func marshal(v any, cfg *config) ([]byte, error) {
+	data, err := marshalCompact(v, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.storedKeys != KeysAsIs {
+		data = transformKeys(data, cfg.storedKeys)
+	}
+	data = sanitize(data, v, cfg)
+	if cfg.indent == "" {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", cfg.indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalCompact encodes v according to cfg without indentation.
+func marshalCompact(v any, cfg *config) ([]byte, error) {
+	if raw, ok := v.(json.RawMessage); ok {
+		return encodeRaw(raw, cfg)
+	}
+	if cfg.walks() {
+		rv := reflect.ValueOf(v)
+		if rv.IsValid() && cfg.needsWalk(rv.Type()) {
+			var buf bytes.Buffer
+			w := &walker{cfg: cfg}
+			if err := w.encode(&buf, rv); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+	}
+	return encodeJSON(v, cfg)
+}
+
+// decodeJSON decodes data into v with encoding/json according to cfg.
+func decodeJSON(data []byte, v any, cfg *config) error {
+	if !cfg.useNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// encodeJSON encodes v with encoding/json according to cfg.
+func encodeJSON(v any, cfg *config) ([]byte, error) {
+	if !cfg.noEscapeHTML {
+		return json.Marshal(v)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	// Encoder.Encode terminates each value with a newline.
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// typeName returns the name of the dynamic type of v for use in reports and errors.
+func typeName(v any) string {
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,277 @@
+package jsonsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+type testStringer struct {
+	s string
+}
+
+func (s testStringer) String() string {
+	return s.s
+}
+
+type testValuer struct {
+	v   driver.Value
+	err error
+}
+
+func (v testValuer) Value() (driver.Value, error) {
+	return v.v, v.err
+}
+
+func TestScan_AdditionalSourceTypes(t *testing.T) {
+	input := `{"name":"Alice","email":"alice@example.com"}`
+	bytesInput := []byte(input)
+	stringInput := input
+
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{"sql.RawBytes", sql.RawBytes(input)},
+		{"*[]byte", &bytesInput},
+		{"*string", &stringInput},
+		{"fmt.Stringer", testStringer{s: input}},
+		{"driver.Valuer", testValuer{v: []byte(input)}},
+		{"nested driver.Valuer", testValuer{v: testValuer{v: input}}},
+		{"Value[T]", NewValue(testProfile{Name: "Alice", Email: "alice@example.com"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testProfile]
+			if err := v.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if v.V.Name != "Alice" {
+				t.Errorf("expected Name=Alice, got %s", v.V.Name)
+			}
+
+			var n Nullable[testProfile]
+			if err := n.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if !n.Valid || n.V.Name != "Alice" {
+				t.Errorf("unexpected result: %+v", n)
+			}
+		})
+	}
+}
+
+func TestScan_NilPointerSources(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{"*[]byte", (*[]byte)(nil)},
+		{"*string", (*string)(nil)},
+		{"driver.Valuer returning nil", testValuer{}},
+		{"Nullable[T] null", Null[testProfile]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NullableFrom(testProfile{Name: "Previous"})
+			if err := n.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if n.Valid {
+				t.Error("expected Valid=false")
+			}
+
+			var v Value[testProfile]
+			if err := v.Scan(tt.input); !errors.Is(err, ErrNullNotAllowed) {
+				t.Errorf("expected ErrNullNotAllowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScan_ValuerError(t *testing.T) {
+	errValuer := errors.New("valuer failed")
+	var v Value[testProfile]
+
+	if err := v.Scan(testValuer{err: errValuer}); !errors.Is(err, errValuer) {
+		t.Errorf("expected valuer error, got %v", err)
+	}
+}
+
+func TestScan_ValuerReturningUnsupportedType(t *testing.T) {
+	var v Value[testProfile]
+
+	if err := v.Scan(testValuer{v: int64(1)}); err == nil {
+		t.Fatal("expected error for unsupported resolved type")
+	}
+}
+
+func TestScan_TrailingData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"garbage", `{"name":"Alice"} trailing junk`},
+		{"second value", `{"name":"Alice"}{"name":"Bob"}`},
+		{"number", `{"name":"Alice"} 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testProfile]
+			if err := v.Scan(tt.input); !errors.Is(err, ErrTrailingData) {
+				t.Errorf("expected ErrTrailingData, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScan_TrailingData_UseNumber(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](UseNumber(true))
+
+	var v Value[map[string]any]
+	if err := v.Scan(`{"id":1} junk`); !errors.Is(err, ErrTrailingData) {
+		t.Errorf("expected ErrTrailingData, got %v", err)
+	}
+}
+
+func TestScan_TrailingData_Allowed(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](AllowTrailingData(true))
+
+	var v Value[testProfile]
+	if err := v.Scan(`{"name":"Alice"} trailing junk`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Name != "Alice" {
+		t.Errorf("expected Name=Alice, got %s", v.V.Name)
+	}
+}
+
+func TestScan_TrailingWhitespace(t *testing.T) {
+	var v Value[testProfile]
+
+	if err := v.Scan("{\"name\":\"Alice\"} \n\t"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+}
+
+func TestScan_InvalidJSON_NotTrailingData(t *testing.T) {
+	var v Value[testProfile]
+
+	err := v.Scan(`{"name":}`)
+	if err == nil || errors.Is(err, ErrTrailingData) {
+		t.Errorf("expected syntax error, got %v", err)
+	}
+}
+
+func TestEmptyInput_Defaults(t *testing.T) {
+	var v Value[testProfile]
+	if err := v.Scan(""); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput for Value, got %v", err)
+	}
+
+	n := NullableFrom(testProfile{Name: "Previous"})
+	if err := n.Scan("  "); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.Valid {
+		t.Error("expected Valid=false for Nullable")
+	}
+}
+
+func TestEmptyInput_Policies(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       EmptyPolicy
+		valueErr     error
+		nullableErr  error
+		nullableNull bool
+	}{
+		{"ErrorOnEmpty", ErrorOnEmpty, ErrEmptyInput, ErrEmptyInput, false},
+		{"NullOnEmpty", NullOnEmpty, ErrNullNotAllowed, nil, true},
+		{"ZeroOnEmpty", ZeroOnEmpty, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetOptions[testProfile](t)
+			Configure[testProfile](EmptyInput(tt.policy))
+
+			v := NewValue(testProfile{Name: "Previous"})
+			err := v.Scan([]byte{})
+			if !errors.Is(err, tt.valueErr) {
+				t.Errorf("Value: expected %v, got %v", tt.valueErr, err)
+			}
+			if tt.policy == ZeroOnEmpty && v.V.Name != "" {
+				t.Errorf("Value: expected zero value, got %+v", v.V)
+			}
+
+			n := NullableFrom(testProfile{Name: "Previous"})
+			err = n.Scan("")
+			if !errors.Is(err, tt.nullableErr) {
+				t.Errorf("Nullable: expected %v, got %v", tt.nullableErr, err)
+			}
+			if err == nil && n.Valid == tt.nullableNull {
+				t.Errorf("Nullable: unexpected Valid=%v", n.Valid)
+			}
+			if err == nil && n.V.Name != "" {
+				t.Errorf("Nullable: expected zero value, got %+v", n.V)
+			}
+		})
+	}
+}
+
+func TestEmptyDocumentAsNull(t *testing.T) {
+	type doc map[string]any
+	type list []int
+	resetOptions[doc](t)
+	resetOptions[list](t)
+	Configure[doc](EmptyObjectAsNull(true), NullAsEmptyObject(true))
+	Configure[list](EmptyArrayAsNull(true))
+
+	for _, src := range []any{"{}", []byte(" { } ")} {
+		n := NullableFrom(doc{"stale": true})
+		if err := n.Scan(src); err != nil {
+			t.Fatalf("Scan(%q) failed: %v", src, err)
+		}
+		if n.Valid || n.V != nil {
+			t.Errorf("Scan(%q): expected NULL, got %+v", src, n)
+		}
+	}
+	var v Value[doc]
+	if err := v.Scan("{}"); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+
+	result, err := Null[doc]().Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != "{}" {
+		t.Errorf("expected {}, got %v", result)
+	}
+
+	var l Nullable[list]
+	if err := l.Scan("[ ]"); err != nil || l.Valid {
+		t.Errorf("expected NULL for [], got %+v, %v", l, err)
+	}
+	if err := l.Scan("[1]"); err != nil || !l.Valid {
+		t.Errorf("expected valid for [1], got %+v, %v", l, err)
+	}
+	// Only the configured kind of empty document is treated as NULL.
+	var d Nullable[doc]
+	if err := d.Scan(`{"a":1}`); err != nil || !d.Valid {
+		t.Errorf("expected valid document, got %+v, %v", d, err)
+	}
+	var nl Nullable[list]
+	resetOptions[list](t)
+	Configure[list](EmptyObjectAsNull(true))
+	if err := nl.Scan("[]"); err != nil || !nl.Valid {
+		t.Errorf("expected valid empty array, got %+v, %v", nl, err)
+	}
+}
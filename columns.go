@@ -0,0 +1,183 @@
+package jsonsql
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromColumns converts flat, a struct scanned from the columns of a legacy table, to the
+// document type T, for incremental migrations where the columns and a JSON column coexist.
+// Each field of flat is stored at the document path given by its `jsonsql` tag, or under
+// its JSON name:
+//
+//	type userColumns struct {
+//		ID   int64          `jsonsql:"id"`
+//		City sql.NullString `jsonsql:"address.city"`
+//		Tags string         `jsonsql:"-"`
+//	}
+//
+//	profile, err := jsonsql.FromColumns[Profile](cols)
+//
+// Paths use the syntax of Document.Get and refer to the stored document, so options registered
+// for T such as KeyCase apply when it is decoded. Fields implementing driver.Valuer, such as
+// sql.NullString, are stored as their driver value, and SQL NULL leaves the path unset.
+func FromColumns[T, F any](flat F) (T, error) {
+	var v T
+	fields, err := columnFields(reflect.TypeFor[F]())
+	if err != nil {
+		return v, fmt.Errorf("jsonsql.FromColumns: %w", err)
+	}
+	doc := Document{V: map[string]any{}}
+	rv := reflect.ValueOf(&flat).Elem()
+	for _, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index, false)
+		if !ok {
+			continue
+		}
+		value, err := columnValue(fv)
+		if err != nil {
+			return v, fmt.Errorf("jsonsql.FromColumns: field %s: %w", f.goName, err)
+		}
+		if value == nil {
+			continue
+		}
+		if err := doc.SetPath(f.path, value); err != nil {
+			return v, fmt.Errorf("jsonsql.FromColumns: field %s: %w", f.goName, err)
+		}
+	}
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return v, fmt.Errorf("jsonsql.FromColumns: %w", err)
+	}
+	if _, err := decodeDocument(data, &v, configFor[T](), ErrorOnEmpty); err != nil {
+		return v, fmt.Errorf("jsonsql.FromColumns: %w", err)
+	}
+	return v, nil
+}
+
+// ToColumns is the inverse of FromColumns: it encodes v with the options registered for T and
+// sets each field of F from the value at its path, so legacy columns can be kept up to date
+// while the JSON column is authoritative. Fields whose path does not exist keep their zero value.
+// Fields implementing sql.Scanner, such as sql.NullString, are scanned from the value.
+func ToColumns[F, T any](v T) (F, error) {
+	var flat F
+	fields, err := columnFields(reflect.TypeFor[F]())
+	if err != nil {
+		return flat, fmt.Errorf("jsonsql.ToColumns: %w", err)
+	}
+	data, err := encodeValue(v, configFor[T]())
+	if err != nil {
+		return flat, fmt.Errorf("jsonsql.ToColumns: %w", err)
+	}
+	var doc Document
+	if err := doc.UnmarshalJSON(data); err != nil {
+		return flat, fmt.Errorf("jsonsql.ToColumns: %w", err)
+	}
+	rv := reflect.ValueOf(&flat).Elem()
+	for _, f := range fields {
+		value, err := doc.get(f.path)
+		if errors.Is(err, ErrPathNotFound) {
+			continue
+		}
+		if err != nil {
+			return flat, fmt.Errorf("jsonsql.ToColumns: field %s: %w", f.goName, err)
+		}
+		fv, _ := fieldByIndex(rv, f.index, true)
+		if err := setColumn(fv, value); err != nil {
+			return flat, fmt.Errorf("jsonsql.ToColumns: field %s: %w", f.goName, err)
+		}
+	}
+	return flat, nil
+}
+
+// columnField is a field of a flat column struct and its document path.
+type columnField struct {
+	goName string
+	path   string
+	index  []int
+}
+
+// columnFields returns the mapped fields of the flat struct type t.
+func columnFields(t reflect.Type) ([]columnField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("column type %s is not a struct", t)
+	}
+	var fields []columnField
+	for _, f := range typeFields(t) {
+		path, ok := f.tag.Lookup("jsonsql")
+		if path == "-" {
+			continue
+		}
+		if !ok {
+			path = "$['" + strings.ReplaceAll(f.name, "'", `\'`) + "']"
+		}
+		if _, err := parsePath(path); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+		goName := t.FieldByIndex(f.index).Name
+		fields = append(fields, columnField{goName: goName, path: path, index: f.index})
+	}
+	return fields, nil
+}
+
+var (
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+	scannerType = reflect.TypeFor[sql.Scanner]()
+)
+
+// columnValue returns the document value of the column field fv, or nil for SQL NULL.
+func columnValue(fv reflect.Value) (any, error) {
+	t := fv.Type()
+	if t.Implements(valuerType) && !t.Implements(jsonMarshalerType) {
+		if t.Kind() == reflect.Pointer && fv.IsNil() {
+			return nil, nil
+		}
+		v, err := fv.Interface().(driver.Valuer).Value()
+		if b, ok := v.([]byte); ok {
+			return string(b), err
+		}
+		return v, err
+	}
+	if t.Kind() == reflect.Pointer && fv.IsNil() {
+		return nil, nil
+	}
+	return fv.Interface(), nil
+}
+
+// setColumn sets the column field fv from the document value v.
+func setColumn(fv reflect.Value, v any) error {
+	pt := fv.Addr().Type()
+	if pt.Implements(scannerType) && !pt.Implements(jsonUnmarshalerType) {
+		src, err := scannerSource(v)
+		if err != nil {
+			return err
+		}
+		return fv.Addr().Interface().(sql.Scanner).Scan(src)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	return dec.Decode(fv.Addr().Interface())
+}
+
+// scannerSource converts a document value to the driver value an sql.Scanner expects.
+func scannerSource(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, string, bool:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	}
+	return json.Marshal(v)
+}
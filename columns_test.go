@@ -0,0 +1,81 @@
+package jsonsql
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCustomer struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	} `json:"address"`
+	Since time.Time `json:"since"`
+	Score float64   `json:"score"`
+	Tags  []string  `json:"tags"`
+}
+
+type testCustomerColumns struct {
+	ID    int64           `json:"id"`
+	Name  string          `jsonsql:"name"`
+	City  sql.NullString  `jsonsql:"address.city"`
+	Zip   sql.NullString  `jsonsql:"address.zip"`
+	Since time.Time       `jsonsql:"since"`
+	Score sql.NullFloat64 `jsonsql:"score"`
+	Notes string          `jsonsql:"-"`
+}
+
+func TestFromColumns(t *testing.T) {
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	cols := testCustomerColumns{
+		ID:    7,
+		Name:  "Alice",
+		City:  sql.NullString{String: "Oslo", Valid: true},
+		Since: since,
+		Score: sql.NullFloat64{Float64: 4.5, Valid: true},
+		Notes: "ignored",
+	}
+	c, err := FromColumns[testCustomer](cols)
+	if err != nil {
+		t.Fatalf("FromColumns failed: %v", err)
+	}
+	if c.ID != 7 || c.Name != "Alice" || c.Address.City != "Oslo" || c.Address.Zip != "" ||
+		!c.Since.Equal(since) || c.Score != 4.5 || c.Tags != nil {
+		t.Errorf("unexpected document %+v", c)
+	}
+
+	back, err := ToColumns[testCustomerColumns](c)
+	if err != nil {
+		t.Fatalf("ToColumns failed: %v", err)
+	}
+	cols.Notes = ""
+	if !reflect.DeepEqual(back, cols) {
+		t.Errorf("expected %+v, got %+v", cols, back)
+	}
+}
+
+func TestFromColumns_Errors(t *testing.T) {
+	type badPath struct {
+		A string `jsonsql:"a[x"`
+	}
+	if _, err := FromColumns[testCustomer](badPath{}); err == nil || !strings.Contains(err.Error(), "jsonsql.FromColumns") {
+		t.Errorf("expected path error, got %v", err)
+	}
+	type wrongType struct {
+		Name int `jsonsql:"name"`
+	}
+	if _, err := FromColumns[testCustomer](wrongType{Name: 1}); err == nil {
+		t.Error("expected type error")
+	}
+	if _, err := ToColumns[wrongType](testCustomer{Name: "x"}); err == nil || !strings.Contains(err.Error(), "field Name") {
+		t.Errorf("expected field error, got %v", err)
+	}
+	if _, err := ToColumns[int](testCustomer{}); err == nil {
+		t.Error("expected error for non-struct column type")
+	}
+}
@@ -0,0 +1,53 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// ContextScanner is implemented by wrappers that can scan with a context, so decoding can
+// honor deadlines and pass request-scoped metadata to the observability hooks.
+type ContextScanner interface {
+	ScanContext(ctx context.Context, src any) error
+}
+
+// WithContext adapts dest to sql.Scanner for rows.Scan, calling dest.ScanContext with ctx:
+//
+//	err := rows.Scan(&id, jsonsql.WithContext(ctx, &settings))
+func WithContext(ctx context.Context, dest ContextScanner) sql.Scanner {
+	return contextScanner{ctx: ctx, dest: dest}
+}
+
+type contextScanner struct {
+	ctx  context.Context
+	dest ContextScanner
+}
+
+// Scan implements sql.Scanner interface.
+func (s contextScanner) Scan(src any) error {
+	return s.dest.ScanContext(s.ctx, src)
+}
+
+// ContextValuer is implemented by wrappers that can encode with a context, so key selection
+// and other request-scoped behavior can use it.
+type ContextValuer interface {
+	ValueContext(ctx context.Context) (driver.Value, error)
+}
+
+// ValuerWithContext adapts v to driver.Valuer for query arguments, calling v.ValueContext with ctx:
+//
+//	_, err := db.ExecContext(ctx, query, jsonsql.ValuerWithContext(ctx, signed))
+func ValuerWithContext(ctx context.Context, v ContextValuer) driver.Valuer {
+	return contextValuer{ctx: ctx, v: v}
+}
+
+type contextValuer struct {
+	ctx context.Context
+	v   ContextValuer
+}
+
+// Value implements driver.Valuer interface.
+func (v contextValuer) Value() (driver.Value, error) {
+	return v.v.ValueContext(v.ctx)
+}
@@ -0,0 +1,64 @@
+package jsonsql
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testTenantKey struct{}
+
+func TestWithContext(t *testing.T) {
+	resetOptions[testProfile](t)
+	var tenant any
+	Configure[testProfile](Observe(Hooks{
+		OnScan: func(e HookEvent) { tenant = e.Context.Value(testTenantKey{}) },
+	}))
+
+	ctx := context.WithValue(context.Background(), testTenantKey{}, "acme")
+	var v Value[testProfile]
+	if err := WithContext(ctx, &v).Scan(`{"name":"Ann"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Name != "Ann" || tenant != "acme" {
+		t.Errorf("unexpected result: %+v, tenant %v", v.V, tenant)
+	}
+}
+
+func TestScanContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var n Nullable[testProfile]
+	if err := n.ScanContext(ctx, `{"name":"Ann"}`); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n.Valid {
+		t.Errorf("expected Valid=false, got %+v", n)
+	}
+}
+
+func TestScanContext_CanceledWrappers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scanners := map[string]ContextScanner{
+		"ArrayOf":         new(ArrayOf[testProfile]),
+		"NullableArrayOf": new(NullableArrayOf[testProfile]),
+		"NDJSON":          new(NDJSON[testProfile]),
+		"ReadOnly":        new(ReadOnly[testProfile]),
+		"Immutable":       new(Immutable[testProfile]),
+		"Lazy":            new(Lazy[testProfile]),
+		"CheckedRaw":      new(CheckedRaw),
+	}
+	for name, s := range scanners {
+		if err := s.ScanContext(ctx, `{"name":"Ann"}`); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+
+	var a ArrayOf[testProfile]
+	if err := a.ScanContext(context.Background(), `{"{\"name\":\"Ann\"}"}`); err != nil || len(a.V) != 1 || a.V[0].Name != "Ann" {
+		t.Errorf("unexpected result %+v, %v", a.V, err)
+	}
+}
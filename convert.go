@@ -0,0 +1,86 @@
+package jsonsql
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// NullableFromSQL creates a Nullable[T] from a sql.Null[T].
+func NullableFromSQL[T any](n sql.Null[T]) Nullable[T] {
+	return NewNullable(n.V, n.Valid)
+}
+
+// ToSQL returns the value as a sql.Null[T].
+func (n Nullable[T]) ToSQL() sql.Null[T] {
+	return sql.Null[T]{V: n.V, Valid: n.Valid}
+}
+
+// NullableFromNullString creates a Nullable[T] by decoding the raw JSON held by a sql.NullString.
+// A NULL string, an empty string and JSON literal "null" all result in Null[T]().
+func NullableFromNullString[T any](s sql.NullString) (Nullable[T], error) {
+	var n Nullable[T]
+	if !s.Valid {
+		return n, nil
+	}
+	if err := n.Scan(s.String); err != nil {
+		return Null[T](), err
+	}
+	return n, nil
+}
+
+// ToNullString returns the value encoded like Nullable[T].Value as a sql.NullString.
+// Returns a NULL string when Valid is false.
+func (n Nullable[T]) ToNullString() (sql.NullString, error) {
+	if !n.Valid {
+		return sql.NullString{}, nil
+	}
+	data, err := encodeValue(n.V, configFor[T]())
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("jsonsql.Nullable.ToNullString: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// ValueFromRaw creates a Value[T] by decoding raw JSON held in a byte slice or string type
+// from another library, such as sqlx types.JSONText or json.RawMessage:
+//
+//	profile, err := jsonsql.ValueFromRaw[Profile](row.Profile) // row.Profile is types.JSONText
+func ValueFromRaw[T any, R ~[]byte | ~string](raw R) (Value[T], error) {
+	var v Value[T]
+	err := v.Scan([]byte(raw))
+	return v, err
+}
+
+// NullableFromRaw creates a Nullable[T] by decoding raw JSON held in a byte slice or string type
+// from another library. Empty input and JSON literal "null" result in Null[T]().
+func NullableFromRaw[T any, R ~[]byte | ~string](raw R) (Nullable[T], error) {
+	var n Nullable[T]
+	if err := n.Scan([]byte(raw)); err != nil {
+		return Null[T](), err
+	}
+	return n, nil
+}
+
+// ToRaw returns v encoded like Value[T].Value as a byte slice type from another library:
+//
+//	text, err := jsonsql.ToRaw[types.JSONText](v)
+func ToRaw[R ~[]byte, T any](v Value[T]) (R, error) {
+	data, err := encodeValue(v.V, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.ToRaw: %w", err)
+	}
+	return R(data), nil
+}
+
+// NullableToRaw returns n encoded like Nullable[T].Value as a byte slice type from another library.
+// Returns nil when Valid is false.
+func NullableToRaw[R ~[]byte, T any](n Nullable[T]) (R, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	data, err := encodeValue(n.V, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.NullableToRaw: %w", err)
+	}
+	return R(data), nil
+}
@@ -0,0 +1,213 @@
+package jsonsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNullableFromSQL(t *testing.T) {
+	n := NullableFromSQL(sql.Null[testProfile]{V: testProfile{Name: "Alice"}, Valid: true})
+	if !n.Valid || n.V.Name != "Alice" {
+		t.Errorf("unexpected result: %+v", n)
+	}
+
+	null := NullableFromSQL(sql.Null[testProfile]{V: testProfile{Name: "Alice"}})
+	if null.Valid || null.V.Name != "" {
+		t.Errorf("expected Null with zero value, got %+v", null)
+	}
+}
+
+func TestNullable_ToSQL(t *testing.T) {
+	s := NullableFrom(testProfile{Name: "Alice"}).ToSQL()
+	if !s.Valid || s.V.Name != "Alice" {
+		t.Errorf("unexpected result: %+v", s)
+	}
+
+	null := Null[testProfile]().ToSQL()
+	if null.Valid {
+		t.Errorf("expected Valid=false, got %+v", null)
+	}
+}
+
+func TestNullableFromNullString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input sql.NullString
+		valid bool
+	}{
+		{"json", sql.NullString{String: `{"name":"Alice"}`, Valid: true}, true},
+		{"NULL", sql.NullString{}, false},
+		{"empty", sql.NullString{Valid: true}, false},
+		{"json null", sql.NullString{String: "null", Valid: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NullableFromNullString[testProfile](tt.input)
+			if err != nil {
+				t.Fatalf("NullableFromNullString failed: %v", err)
+			}
+			if n.Valid != tt.valid {
+				t.Errorf("expected Valid=%v, got %+v", tt.valid, n)
+			}
+			if tt.valid && n.V.Name != "Alice" {
+				t.Errorf("expected Name=Alice, got %s", n.V.Name)
+			}
+		})
+	}
+}
+
+func TestNullableFromNullString_InvalidJSON(t *testing.T) {
+	n, err := NullableFromNullString[testProfile](sql.NullString{String: "{invalid}", Valid: true})
+	if err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	if n.Valid {
+		t.Error("expected Valid=false on error")
+	}
+}
+
+func TestNullable_ToNullString(t *testing.T) {
+	s, err := NullableFrom(testProfile{Name: "Alice"}).ToNullString()
+	if err != nil {
+		t.Fatalf("ToNullString failed: %v", err)
+	}
+	if !s.Valid || s.String != `{"name":"Alice","email":""}` {
+		t.Errorf("unexpected result: %+v", s)
+	}
+
+	null, err := Null[testProfile]().ToNullString()
+	if err != nil {
+		t.Fatalf("ToNullString failed: %v", err)
+	}
+	if null.Valid {
+		t.Errorf("expected Valid=false, got %+v", null)
+	}
+}
+
+func TestNullable_ToNullString_Validates(t *testing.T) {
+	resetOptions[testValidated](t)
+	Configure[testValidated](ValidateValues(true))
+
+	if _, err := NullableFrom(testValidated{Qty: -1}).ToNullString(); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation, got %v", err)
+	}
+}
+
+// testJSONText mirrors sqlx types.JSONText.
+type testJSONText json.RawMessage
+
+func (j testJSONText) Value() (driver.Value, error) {
+	return []byte(j), nil
+}
+
+// testNullJSONText mirrors sqlx types.NullJSONText.
+type testNullJSONText struct {
+	testJSONText
+	Valid bool
+}
+
+func (n testNullJSONText) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.testJSONText.Value()
+}
+
+// testRawJSON is a named byte slice without a Valuer.
+type testRawJSON []byte
+
+func TestScan_EcosystemRawTypes(t *testing.T) {
+	input := `{"name":"Alice"}`
+
+	tests := []struct {
+		name  string
+		input any
+		valid bool
+	}{
+		{"JSONText", testJSONText(input), true},
+		{"NullJSONText", testNullJSONText{testJSONText: testJSONText(input), Valid: true}, true},
+		{"NullJSONText null", testNullJSONText{}, false},
+		{"named byte slice", testRawJSON(input), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n Nullable[testProfile]
+			if err := n.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if n.Valid != tt.valid {
+				t.Fatalf("expected Valid=%v, got %+v", tt.valid, n)
+			}
+			if tt.valid && n.V.Name != "Alice" {
+				t.Errorf("expected Name=Alice, got %s", n.V.Name)
+			}
+		})
+	}
+}
+
+func TestValueFromRaw(t *testing.T) {
+	v, err := ValueFromRaw[testProfile](testJSONText(`{"name":"Alice"}`))
+	if err != nil {
+		t.Fatalf("ValueFromRaw failed: %v", err)
+	}
+	if v.V.Name != "Alice" {
+		t.Errorf("expected Name=Alice, got %s", v.V.Name)
+	}
+
+	if _, err := ValueFromRaw[testProfile]("null"); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestNullableFromRaw(t *testing.T) {
+	n, err := NullableFromRaw[testProfile](json.RawMessage(`{"name":"Alice"}`))
+	if err != nil {
+		t.Fatalf("NullableFromRaw failed: %v", err)
+	}
+	if !n.Valid || n.V.Name != "Alice" {
+		t.Errorf("unexpected result: %+v", n)
+	}
+
+	null, err := NullableFromRaw[testProfile](testJSONText(nil))
+	if err != nil {
+		t.Fatalf("NullableFromRaw failed: %v", err)
+	}
+	if null.Valid {
+		t.Error("expected Valid=false")
+	}
+}
+
+func TestToRaw(t *testing.T) {
+	text, err := ToRaw[testJSONText](NewValue(testProfile{Name: "Alice"}))
+	if err != nil {
+		t.Fatalf("ToRaw failed: %v", err)
+	}
+	if string(text) != `{"name":"Alice","email":""}` {
+		t.Errorf("unexpected result: %s", text)
+	}
+
+	raw, err := NullableToRaw[json.RawMessage](Null[testProfile]())
+	if err != nil {
+		t.Fatalf("NullableToRaw failed: %v", err)
+	}
+	if raw != nil {
+		t.Errorf("expected nil, got %s", raw)
+	}
+}
+
+func TestToRaw_Validates(t *testing.T) {
+	resetOptions[testValidated](t)
+	Configure[testValidated](ValidateValues(true))
+
+	if _, err := ToRaw[json.RawMessage](NewValue(testValidated{Qty: -1})); !errors.Is(err, ErrValidation) {
+		t.Errorf("ToRaw: expected ErrValidation, got %v", err)
+	}
+	if _, err := NullableToRaw[json.RawMessage](NullableFrom(testValidated{Qty: -1})); !errors.Is(err, ErrValidation) {
+		t.Errorf("NullableToRaw: expected ErrValidation, got %v", err)
+	}
+}
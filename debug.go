@@ -0,0 +1,120 @@
+package jsonsql
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"unicode/utf8"
+)
+
+// defaultDebugMaxBytes is the default DebugOptions.MaxBytes.
+const defaultDebugMaxBytes = 512
+
+// DebugOptions configures the payload dump logged by DebugLog.
+type DebugOptions struct {
+	// MaxBytes limits the logged payload; longer payloads are truncated. Defaults to 512.
+	MaxBytes int
+	// Redact replaces the contents of JSON string values with a placeholder, keeping keys,
+	// numbers and the document structure so syntax errors remain diagnosable.
+	Redact bool
+	// Level is the log level of the message. Defaults to slog.LevelWarn.
+	Level slog.Leveler
+}
+
+// DebugLog makes Scan log failed decodes to logger, with the target type, the error and a
+// truncated and optionally redacted dump of the raw payload, so errors such as
+// "invalid character" can be diagnosed without re-querying the database:
+//
+//	jsonsql.Configure[Settings](jsonsql.DebugLog(logger, jsonsql.DebugOptions{Redact: true}))
+//
+// The payload may contain personal data; enable Redact or restrict the logger accordingly.
+// Sensitive[T] scans never log the payload, whatever the options.
+func DebugLog(logger *slog.Logger, opts DebugOptions) Option {
+	return func(c *config) {
+		c.debugLogger = logger
+		c.debug = opts
+	}
+}
+
+// logDecodeFailure logs a failed decode of src into a value of type t.
+// The payload is only dumped if logPayload is true.
+func logDecodeFailure(ctx context.Context, cfg *config, t reflect.Type, src any, err error, logPayload bool) {
+	level := slog.LevelWarn
+	if cfg.debug.Level != nil {
+		level = cfg.debug.Level.Level()
+	}
+	if !cfg.debugLogger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("type", t.String()),
+		slog.Any("error", err),
+		slog.Int("size", payloadSize(src)),
+	}
+	if !logPayload {
+		attrs = append(attrs, slog.String("payload", sensitivePlaceholder))
+	} else if data, serr := sourceBytes(src); serr == nil {
+		if cfg.debug.Redact {
+			data = redactStrings(data)
+		}
+		max := cfg.debug.MaxBytes
+		if max <= 0 {
+			max = defaultDebugMaxBytes
+		}
+		truncated := len(data) > max
+		if truncated {
+			data = data[:max]
+			// Do not cut a multi-byte character in half.
+			for range utf8.UTFMax - 1 {
+				if r, size := utf8.DecodeLastRune(data); r != utf8.RuneError || size != 1 {
+					break
+				}
+				data = data[:len(data)-1]
+			}
+		}
+		attrs = append(attrs, slog.String("payload", string(data)), slog.Bool("truncated", truncated))
+	} else {
+		attrs = append(attrs, slog.String("source", typeName(src)))
+	}
+	cfg.debugLogger.LogAttrs(ctx, level, "jsonsql: scan failed", attrs...)
+}
+
+// redactPlaceholder replaces the contents of redacted string values.
+const redactPlaceholder = "***"
+
+// redactStrings replaces the contents of the JSON string values in data with a placeholder.
+// Object keys are kept. data need not be valid JSON; an unterminated string is redacted to the end.
+func redactStrings(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		if data[i] != '"' {
+			out = append(out, data[i])
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(data) && data[end] != '"' {
+			if data[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		end = min(end+1, len(data))
+		str := data[i:end]
+		i = end
+
+		j := i
+		for j < len(data) && isJSONSpace(data[j]) {
+			j++
+		}
+		if j < len(data) && data[j] == ':' {
+			out = append(out, str...)
+			continue
+		}
+		out = append(out, '"')
+		out = append(out, redactPlaceholder...)
+		out = append(out, '"')
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDebugLog(t *testing.T) {
+	resetOptions[testProfile](t)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	Configure[testProfile](DebugLog(logger, DebugOptions{MaxBytes: 30, Redact: true}))
+
+	var v Value[testProfile]
+	if err := v.Scan(`{"name":"Ann","email":"ann@example.com"`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log output %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "WARN" || entry["type"] != "jsonsql.testProfile" || entry["size"] != float64(39) {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+	if entry["payload"] != `{"name":"***","email":"***"` || entry["truncated"] != false {
+		t.Errorf("unexpected payload: %v", entry["payload"])
+	}
+	if !strings.Contains(entry["error"].(string), "unexpected end of JSON input") {
+		t.Errorf("unexpected error: %v", entry["error"])
+	}
+}
+
+func TestDebugLog_Truncate(t *testing.T) {
+	resetOptions[testProfile](t)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	Configure[testProfile](DebugLog(logger, DebugOptions{MaxBytes: 11}))
+
+	var v Value[testProfile]
+	if err := v.Scan(`{"name":"Zoë","x"}`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log output %q: %v", buf.String(), err)
+	}
+	if entry["payload"] != `{"name":"Zo` || entry["truncated"] != true {
+		t.Errorf("unexpected payload: %v", entry)
+	}
+}
+
+func TestDebugLog_SuccessNotLogged(t *testing.T) {
+	resetOptions[testProfile](t)
+	var buf bytes.Buffer
+	Configure[testProfile](DebugLog(slog.New(slog.NewJSONHandler(&buf, nil)), DebugOptions{}))
+
+	var v Value[testProfile]
+	if err := v.Scan(`{"name":"Ann"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no log output, got %s", buf.String())
+	}
+}
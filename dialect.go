@@ -0,0 +1,28 @@
+package jsonsql
+
+import "fmt"
+
+// Dialect selects the SQL syntax produced by the query helpers, such as NotSoftDeleted.
+type Dialect int
+
+const (
+	// Postgres produces PostgreSQL syntax for json and jsonb columns (default).
+	Postgres Dialect = iota
+	// MySQL produces MySQL syntax for JSON columns.
+	MySQL
+	// SQLite produces SQLite syntax for the JSON functions of the json1 extension.
+	SQLite
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case Postgres:
+		return "Postgres"
+	case MySQL:
+		return "MySQL"
+	case SQLite:
+		return "SQLite"
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
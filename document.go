@@ -0,0 +1,257 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner      = (*Document)(nil)
+	_ ContextScanner   = (*Document)(nil)
+	_ driver.Valuer    = Document{}
+	_ json.Marshaler   = Document{}
+	_ json.Unmarshaler = (*Document)(nil)
+)
+
+// Document is a type for NOT NULL JSON columns with schemaless documents, such as settings or
+// metadata columns, whose values are read by path instead of through a struct:
+//
+//	var doc jsonsql.Document
+//	err := row.Scan(&doc)
+//	city, err := doc.GetString("$.address.city")
+//
+// V holds the decoded document: map[string]any for objects, []any for arrays, json.Number
+// for numbers, so integers keep their precision, and string, bool or nil for the other values.
+// Paths use the JSONPath subset described at Get.
+// Documents are decoded and encoded with the options registered for Document.
+type Document struct {
+	V any
+}
+
+// NewDocument creates a Document from v, which may be any value encodable as JSON,
+// by encoding and decoding it.
+func NewDocument(v any) (Document, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Document{}, fmt.Errorf("jsonsql.NewDocument: %w", err)
+	}
+	var d Document
+	if err := d.UnmarshalJSON(data); err != nil {
+		return Document{}, fmt.Errorf("jsonsql.NewDocument: %w", err)
+	}
+	return d, nil
+}
+
+// Get returns the value at path. Supported paths are:
+//
+//	$                   the whole document ("$" may be omitted in front of a member name)
+//	$.name  or  name    an object member
+//	$['a.b'] $["a b"]   an object member with arbitrary characters
+//	$.items[2]          an array element; negative indexes count from the end
+//	$.items[*].sku      every member or element, also written as .*
+//
+// Paths containing a wildcard return a []any of every match, in key order for objects.
+// Other paths fail with ErrPathNotFound if the value does not exist, and every path
+// fails with ErrInvalidPath if it is malformed.
+func (d Document) Get(path string) (any, error) {
+	v, err := d.get(path)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Document.Get: %w", err)
+	}
+	return v, nil
+}
+
+// get implements Get.
+func (d Document) get(path string) (any, error) {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	matches := lookupPath(d.V, tokens, nil)
+	if hasWildcard(tokens) {
+		if matches == nil {
+			matches = []any{}
+		}
+		return matches, nil
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+	return matches[0], nil
+}
+
+// GetString returns the string at path.
+func (d Document) GetString(path string) (string, error) {
+	v, err := d.get(path)
+	if err != nil {
+		return "", fmt.Errorf("jsonsql.Document.GetString: %w", err)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("jsonsql.Document.GetString: %s is %s, not a string", path, valueKind(v))
+	}
+	return s, nil
+}
+
+// GetInt returns the integer at path. Numbers with a fraction or out of the int64 range fail.
+func (d Document) GetInt(path string) (int64, error) {
+	v, err := d.get(path)
+	if err != nil {
+		return 0, fmt.Errorf("jsonsql.Document.GetInt: %w", err)
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("jsonsql.Document.GetInt: %s is %s, not a number", path, valueKind(v))
+	}
+	i, err := n.Int64()
+	if err != nil {
+		// Accept integral numbers written with an exponent or fraction, such as 1e3 or 2.0.
+		f, ferr := n.Float64()
+		if ferr != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("jsonsql.Document.GetInt: %s is %s, not an int64", path, n)
+		}
+		i = int64(f)
+	}
+	return i, nil
+}
+
+// GetFloat returns the number at path.
+func (d Document) GetFloat(path string) (float64, error) {
+	v, err := d.get(path)
+	if err != nil {
+		return 0, fmt.Errorf("jsonsql.Document.GetFloat: %w", err)
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("jsonsql.Document.GetFloat: %s is %s, not a number", path, valueKind(v))
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("jsonsql.Document.GetFloat: %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// GetBool returns the boolean at path.
+func (d Document) GetBool(path string) (bool, error) {
+	v, err := d.get(path)
+	if err != nil {
+		return false, fmt.Errorf("jsonsql.Document.GetBool: %w", err)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("jsonsql.Document.GetBool: %s is %s, not a boolean", path, valueKind(v))
+	}
+	return b, nil
+}
+
+// SetPath sets the value at path to value, which may be any value encodable as JSON; it is
+// stored as encoded and decoded again, like NewDocument, so the document stays valid JSON.
+// Missing objects along the path are created, an array index equal to the length of the array
+// appends an element, and a wildcard sets every existing match:
+//
+//	err := doc.SetPath("$.address.city", "Bergen")
+//	err = doc.SetPath("$.items[*].discount", 0)
+//
+// It fails with ErrPathNotFound when the path runs into an array with a member name, into an
+// object with an index or into a scalar, and with ErrNullNotAllowed when setting the root to null.
+// On failure the document is left unchanged. The maps and slices of V are modified in place,
+// so copies of d may observe the change.
+func (d *Document) SetPath(path string, value any) error {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Document.SetPath: %w", err)
+	}
+	v, err := NewDocument(value)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Document.SetPath: %w", err)
+	}
+	if len(tokens) == 0 && v.V == nil {
+		return fmt.Errorf("jsonsql.Document.SetPath: %w", ErrNullNotAllowed)
+	}
+	root := d.V
+	if hasWildcard(tokens) {
+		root = cloneValue(root)
+	}
+	root, err = setPath(root, tokens, v.V)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Document.SetPath: %s: %w", path, err)
+	}
+	d.V = root
+	return nil
+}
+
+// DeletePath removes the value at path: an object member, or an array element, shifting the
+// following elements. A wildcard removes every match. Paths without a wildcard fail with
+// ErrPathNotFound if the value does not exist; the root cannot be deleted.
+// The maps and slices of V are modified in place, so copies of d may observe the change.
+func (d *Document) DeletePath(path string) error {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Document.DeletePath: %w", err)
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("jsonsql.Document.DeletePath: %w %q: cannot delete the root", ErrInvalidPath, path)
+	}
+	root, removed := deletePath(d.V, tokens)
+	if removed == 0 && !hasWildcard(tokens) {
+		return fmt.Errorf("jsonsql.Document.DeletePath: %w: %s", ErrPathNotFound, path)
+	}
+	d.V = root
+	return nil
+}
+
+// Scan implements sql.Scanner interface.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation),
+// and ErrEmptyInput for empty input unless another EmptyPolicy is configured.
+func (d *Document) Scan(src any) error {
+	return d.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (d *Document) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeSourceContext(ctx, src, d, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Document.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+func (d Document) Value() (driver.Value, error) {
+	cfg := configFor[Document]()
+	data, err := encodeValue(d, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Document.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// It honors EscapeHTML registered with SetDefaults or Configure[Document].
+func (d Document) MarshalJSON() ([]byte, error) {
+	return encodeJSON(d.V, configFor[Document]())
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// Numbers are decoded as json.Number.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	d.V = v
+	return nil
+}
@@ -0,0 +1,282 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDocumentJSON = `{
+	"id": 9007199254740993,
+	"name": "Ann",
+	"active": true,
+	"score": 4.5,
+	"count": 1e3,
+	"address": {"city": "Oslo", "zip code": "0150"},
+	"items": [{"sku": "a", "qty": 1}, {"sku": "b", "qty": 2}],
+	"empty": []
+}`
+
+func scanTestDocument(t *testing.T) Document {
+	t.Helper()
+	var d Document
+	if err := d.Scan(testDocumentJSON); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	return d
+}
+
+func TestDocument_Get(t *testing.T) {
+	d := scanTestDocument(t)
+
+	tests := []struct {
+		path     string
+		expected any
+	}{
+		{"$.name", "Ann"},
+		{"address.city", "Oslo"},
+		{"$.address['zip code']", "0150"},
+		{"$.items[1].sku", "b"},
+		{"$.items[-1].qty", json.Number("2")},
+		{"$.items[*].sku", []any{"a", "b"}},
+		{"$.address.*", []any{"Oslo", "0150"}},
+		{"$.empty[*]", []any{}},
+		{"$.missing[*]", []any{}},
+	}
+	for _, tt := range tests {
+		got, err := d.Get(tt.path)
+		if err != nil {
+			t.Errorf("Get(%q) failed: %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Get(%q) = %#v, want %#v", tt.path, got, tt.expected)
+		}
+	}
+
+	for _, path := range []string{"$.missing", "$.items[2]", "$.name.first", "$.items.sku", "$.address[0]"} {
+		if _, err := d.Get(path); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("Get(%q): expected ErrPathNotFound, got %v", path, err)
+		}
+	}
+	if _, err := d.Get("$["); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected ErrInvalidPath, got %v", err)
+	}
+}
+
+func TestDocument_TypedGetters(t *testing.T) {
+	d := scanTestDocument(t)
+
+	if s, err := d.GetString("$.name"); err != nil || s != "Ann" {
+		t.Errorf("GetString = %q, %v", s, err)
+	}
+	if i, err := d.GetInt("$.id"); err != nil || i != 9007199254740993 {
+		t.Errorf("GetInt = %d, %v", i, err)
+	}
+	if i, err := d.GetInt("$.count"); err != nil || i != 1000 {
+		t.Errorf("GetInt(1e3) = %d, %v", i, err)
+	}
+	if f, err := d.GetFloat("$.score"); err != nil || f != 4.5 {
+		t.Errorf("GetFloat = %v, %v", f, err)
+	}
+	if b, err := d.GetBool("$.active"); err != nil || !b {
+		t.Errorf("GetBool = %v, %v", b, err)
+	}
+
+	if _, err := d.GetInt("$.score"); err == nil || !strings.Contains(err.Error(), "not an int64") {
+		t.Errorf("expected fraction error, got %v", err)
+	}
+	if _, err := d.GetString("$.items"); err == nil || !strings.Contains(err.Error(), "$.items is array, not a string") {
+		t.Errorf("expected type error, got %v", err)
+	}
+	if _, err := d.GetBool("$.missing"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected ErrPathNotFound, got %v", err)
+	}
+}
+
+func TestDocument_ScanValue(t *testing.T) {
+	var d Document
+	if err := d.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if err := d.Scan(`{"big":12345678901234567890,"a":[1,"x"]}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	result, err := d.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"a":[1,"x"],"big":12345678901234567890}` {
+		t.Errorf("unexpected result %s", result)
+	}
+
+	built, err := NewDocument(map[string]any{"n": 1, "tags": []string{"x"}})
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+	if n, err := built.GetInt("n"); err != nil || n != 1 {
+		t.Errorf("GetInt = %d, %v", n, err)
+	}
+	if s, err := built.GetString("tags[0]"); err != nil || s != "x" {
+		t.Errorf("GetString = %q, %v", s, err)
+	}
+}
+
+func TestDocument_SetPath(t *testing.T) {
+	d := scanTestDocument(t)
+
+	steps := []struct {
+		path  string
+		value any
+	}{
+		{"$.name", "Bob"},
+		{"$.address.country.code", "NO"},
+		{"$.items[0].qty", 5},
+		{"$.items[2]", map[string]any{"sku": "c"}},
+		{"$.items[-1].qty", 3},
+		{"$.items[*].tags", []string{"new"}},
+		{"$['a.b']", nil},
+	}
+	for _, s := range steps {
+		if err := d.SetPath(s.path, s.value); err != nil {
+			t.Fatalf("SetPath(%q) failed: %v", s.path, err)
+		}
+	}
+
+	if s, _ := d.GetString("$.name"); s != "Bob" {
+		t.Errorf("expected Bob, got %q", s)
+	}
+	if s, _ := d.GetString("$.address.country.code"); s != "NO" {
+		t.Errorf("expected created object, got %q", s)
+	}
+	if n, _ := d.GetInt("$.items[2].qty"); n != 3 {
+		t.Errorf("expected appended element with qty 3, got %d", n)
+	}
+	if v, err := d.Get("$['a.b']"); err != nil || v != nil {
+		t.Errorf("expected null member, got %v, %v", v, err)
+	}
+
+	// Wildcard matches must not share the stored value.
+	if err := d.SetPath("$.items[0].tags[0]", "changed"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	if s, _ := d.GetString("$.items[1].tags[0]"); s != "new" {
+		t.Errorf("expected independent copies, got %q", s)
+	}
+
+	for _, path := range []string{"$.name.first", "$.items.sku", "$.items[9]", "$.address[0]"} {
+		if err := d.SetPath(path, 1); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("SetPath(%q): expected ErrPathNotFound, got %v", path, err)
+		}
+	}
+	if err := d.SetPath("$", nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if err := d.SetPath("$.x", make(chan int)); err == nil {
+		t.Error("expected error for unencodable value")
+	}
+
+	var empty Document
+	if err := empty.SetPath("$.a[0]", 1); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected ErrPathNotFound when creating arrays, got %v", err)
+	}
+	if err := empty.SetPath("$.a.b", true); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	result, err := empty.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"a":{"b":true}}` {
+		t.Errorf("unexpected result %s", result)
+	}
+}
+
+func TestDocument_SetPath_FailureLeavesDocumentUnchanged(t *testing.T) {
+	tests := []struct {
+		doc  string
+		path string
+	}{
+		{`{"x":1}`, "$.a.b[5]"},
+		{`{"a":{"b":[1]}}`, "$.a.b[0].q"},
+		{`{"a":{"b":[1]}}`, "$.a.b[5]"},
+		{`{"items":[{"q":1},2,{"q":3}]}`, "$.items[*].q"},
+		{`{"m":{"a":{},"b":1}}`, "$.m.*.q"},
+	}
+	for _, tt := range tests {
+		var d Document
+		if err := d.Scan(tt.doc); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if err := d.SetPath(tt.path, 9); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("SetPath(%q): expected ErrPathNotFound, got %v", tt.path, err)
+		}
+		result, err := d.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if string(result.([]byte)) != tt.doc {
+			t.Errorf("SetPath(%q) modified the document: %s", tt.path, result)
+		}
+	}
+}
+
+func TestDocument_DeletePath(t *testing.T) {
+	d := scanTestDocument(t)
+
+	for _, path := range []string{"$.address['zip code']", "$.items[0]", "$.items[*].qty", "$.empty[*]"} {
+		if err := d.DeletePath(path); err != nil {
+			t.Fatalf("DeletePath(%q) failed: %v", path, err)
+		}
+	}
+	got, err := d.Get("$.items")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []any{map[string]any{"sku": "b"}}) {
+		t.Errorf("unexpected items %#v", got)
+	}
+	if _, err := d.Get("$.address['zip code']"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected member to be deleted, got %v", err)
+	}
+
+	for _, path := range []string{"$.missing", "$.items[5]", "$.name.first"} {
+		if err := d.DeletePath(path); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("DeletePath(%q): expected ErrPathNotFound, got %v", path, err)
+		}
+	}
+	if err := d.DeletePath("$"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected ErrInvalidPath for root, got %v", err)
+	}
+
+	result, err := d.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !json.Valid(result.([]byte)) {
+		t.Errorf("invalid JSON %s", result)
+	}
+}
+
+func TestDocument_EscapeHTML(t *testing.T) {
+	resetOptions[Document](t)
+	Configure[Document](EscapeHTML(false))
+
+	d := Document{V: map[string]any{"html": "<b>&</b>"}}
+	data, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if string(data) != `{"html":"<b>&</b>"}` {
+		t.Errorf("unexpected MarshalJSON result %s", data)
+	}
+	result, err := d.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"html":"<b>&</b>"}` {
+		t.Errorf("unexpected Value result %s", result)
+	}
+}
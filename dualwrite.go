@@ -0,0 +1,55 @@
+package jsonsql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// DualWrite returns the driver values of the same document in its current and a new storage
+// format, for migrations that shadow-write the new format into a second column and compare
+// before cutting over, such as JSON to CBOR:
+//
+//	doc, shadow, err := jsonsql.DualWrite(jsonsql.NewValue(s), jsonsql.NewCbor(s))
+//	_, err = db.ExecContext(ctx, `UPDATE users SET settings = $1, settings_cbor = $2 WHERE id = $3`,
+//		doc, shadow, id)
+//
+// Reading both columns back into their wrappers and passing the values to ShadowEqual checks
+// that the new format preserves the documents.
+func DualWrite(current, shadow driver.Valuer) (driver.Value, driver.Value, error) {
+	cur, err := current.Value()
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsonsql.DualWrite: current: %w", err)
+	}
+	sh, err := shadow.Value()
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsonsql.DualWrite: shadow: %w", err)
+	}
+	return cur, sh, nil
+}
+
+// ShadowEqual reports whether the values read back from the current and the shadow column
+// encode to the same document with the options registered for T, ignoring formatting and key order.
+func ShadowEqual[T any](current, shadow T) (bool, error) {
+	cfg := configFor[T]()
+	a, err := encodeValue(current, cfg)
+	if err != nil {
+		return false, fmt.Errorf("jsonsql.ShadowEqual: current: %w", err)
+	}
+	b, err := encodeValue(shadow, cfg)
+	if err != nil {
+		return false, fmt.Errorf("jsonsql.ShadowEqual: shadow: %w", err)
+	}
+	if bytes.Equal(a, b) || cfg.documentCodec != nil {
+		return bytes.Equal(a, b), nil
+	}
+	var da, db Document
+	if err := da.UnmarshalJSON(a); err != nil {
+		return false, fmt.Errorf("jsonsql.ShadowEqual: current: %w", err)
+	}
+	if err := db.UnmarshalJSON(b); err != nil {
+		return false, fmt.Errorf("jsonsql.ShadowEqual: shadow: %w", err)
+	}
+	return reflect.DeepEqual(da.V, db.V), nil
+}
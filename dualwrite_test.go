@@ -0,0 +1,55 @@
+package jsonsql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDualWrite(t *testing.T) {
+	p := testProfile{Name: "Alice", Email: "alice@example.com"}
+	doc, shadow, err := DualWrite(NewValue(p), NewCbor(p))
+	if err != nil {
+		t.Fatalf("DualWrite failed: %v", err)
+	}
+	if string(doc.([]byte)) != `{"name":"Alice","email":"alice@example.com"}` {
+		t.Errorf("unexpected current value %s", doc)
+	}
+
+	var current Value[testProfile]
+	var next Cbor[testProfile]
+	if err := current.Scan(doc); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if err := next.Scan(shadow); err != nil {
+		t.Fatalf("Cbor.Scan failed: %v", err)
+	}
+	equal, err := ShadowEqual(current.V, next.V)
+	if err != nil || !equal {
+		t.Errorf("expected equal documents, got %v, %v", equal, err)
+	}
+
+	next.V.Email = ""
+	if equal, err := ShadowEqual(current.V, next.V); err != nil || equal {
+		t.Errorf("expected different documents, got %v, %v", equal, err)
+	}
+
+	if _, _, err := DualWrite(NewValue(p), failingValuer{}); err == nil || !strings.Contains(err.Error(), "shadow:") {
+		t.Errorf("expected shadow error, got %v", err)
+	}
+}
+
+func TestShadowEqual_Formatting(t *testing.T) {
+	a := map[string]any{"a": 1, "b": []any{"x"}}
+	b := map[string]any{"b": []any{"x"}, "a": 1.0}
+	if equal, err := ShadowEqual(a, b); err != nil || !equal {
+		t.Errorf("expected equal documents, got %v, %v", equal, err)
+	}
+}
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("boom")
+}
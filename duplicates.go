@@ -0,0 +1,134 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// DuplicateKeyError is returned by Scan with RejectDuplicateKeys for a document containing
+// an object with the same key more than once. It matches ErrInvalidJSON with errors.Is.
+type DuplicateKeyError struct {
+	// Path is the JSON Pointer (RFC 6901) of the object, "" for the root.
+	Path string
+	// Key is the duplicated key.
+	Key string
+}
+
+// Error implements the error interface.
+func (e *DuplicateKeyError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("jsonsql: %s: duplicate key %q", path, e.Key)
+}
+
+// Is reports whether target is ErrInvalidJSON.
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrInvalidJSON
+}
+
+// RejectDuplicateKeys makes Scan fail with a *DuplicateKeyError for documents containing an
+// object with a duplicate key, which encoding/json silently resolves by keeping the last value.
+// Duplicates are a common sign of corrupted or injected documents, where different readers
+// may disagree on which value counts. Keys are compared exactly, after any KeyStyle
+// transformation configured with LocalKeys.
+//
+// The check tokenizes the whole document before decoding, so it costs roughly a second decode.
+func RejectDuplicateKeys(enabled bool) Option {
+	return func(c *config) {
+		c.rejectDuplicates = enabled
+	}
+}
+
+// checkDuplicateKeys reports the first duplicate object key in the JSON document data.
+// Malformed documents are not reported, leaving the error to the decoder.
+func checkDuplicateKeys(data []byte) error {
+	c := &duplicateChecker{dec: json.NewDecoder(bytes.NewReader(data))}
+	c.dec.UseNumber()
+	tok, err := c.dec.Token()
+	if err != nil {
+		return nil
+	}
+	if err := c.value(tok); err != nil && err != errMalformed {
+		return err
+	}
+	return nil
+}
+
+// errMalformed stops duplicateChecker at malformed input.
+var errMalformed = errors.New("malformed JSON")
+
+// duplicateChecker walks the tokens of a document, tracking the path of the current value.
+type duplicateChecker struct {
+	dec  *json.Decoder
+	path []string
+}
+
+// token returns the next token, or errMalformed.
+func (c *duplicateChecker) token() (json.Token, error) {
+	tok, err := c.dec.Token()
+	if err != nil {
+		return nil, errMalformed
+	}
+	return tok, nil
+}
+
+// value checks the value starting with tok.
+func (c *duplicateChecker) value(tok json.Token) error {
+	switch tok {
+	case json.Delim('{'):
+		return c.object()
+	case json.Delim('['):
+		return c.array()
+	}
+	return nil
+}
+
+func (c *duplicateChecker) object() error {
+	keys := map[string]bool{}
+	for c.dec.More() {
+		tok, err := c.token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return errMalformed
+		}
+		if keys[key] {
+			return &DuplicateKeyError{Path: jsonPointer(c.path), Key: key}
+		}
+		keys[key] = true
+		if err := c.member(key); err != nil {
+			return err
+		}
+	}
+	_, err := c.token()
+	return err
+}
+
+func (c *duplicateChecker) array() error {
+	for i := 0; c.dec.More(); i++ {
+		if err := c.member(strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+	_, err := c.token()
+	return err
+}
+
+// member checks the next value with token appended to the path.
+func (c *duplicateChecker) member(token string) error {
+	tok, err := c.token()
+	if err != nil {
+		return err
+	}
+	c.path = append(c.path, token)
+	err = c.value(tok)
+	c.path = c.path[:len(c.path)-1]
+	return err
+}
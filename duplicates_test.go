@@ -0,0 +1,54 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRejectDuplicateKeys(t *testing.T) {
+	resetOptions[map[string]any](t)
+	resetOptions[json.RawMessage](t)
+
+	var v Value[map[string]any]
+	if err := v.Scan(`{"role":"user","role":"admin"}`); err != nil {
+		t.Fatalf("duplicates must be accepted by default: %v", err)
+	}
+
+	SetDefaults(RejectDuplicateKeys(true))
+	tests := []struct {
+		doc  string
+		path string
+		key  string
+	}{
+		{`{"role":"user","role":"admin"}`, "", "role"},
+		{`{"items":[{"id":1},{"id":2,"price":1,"price":2}]}`, "/items/1", "price"},
+		{`{"a/b":{"x~":1,"x~":2}}`, "/a~1b", "x~"},
+	}
+	for _, tt := range tests {
+		err := v.Scan(tt.doc)
+		var dupErr *DuplicateKeyError
+		if !errors.As(err, &dupErr) {
+			t.Errorf("Scan(%s): expected DuplicateKeyError, got %v", tt.doc, err)
+			continue
+		}
+		if dupErr.Path != tt.path || dupErr.Key != tt.key {
+			t.Errorf("Scan(%s): expected %q at %q, got %q at %q", tt.doc, tt.key, tt.path, dupErr.Key, dupErr.Path)
+		}
+		if !errors.Is(err, ErrInvalidJSON) {
+			t.Errorf("Scan(%s): expected ErrInvalidJSON, got %v", tt.doc, err)
+		}
+	}
+
+	if err := v.Scan(`{"a":{"id":1},"b":{"id":1},"c":[1,1]}`); err != nil {
+		t.Errorf("Scan failed: %v", err)
+	}
+	if err := v.Scan(`{"a":`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON for malformed document, got %v", err)
+	}
+
+	var raw Value[json.RawMessage]
+	if err := raw.Scan(`{"a":1,"a":1}`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected duplicates in json.RawMessage documents to be rejected, got %v", err)
+	}
+}
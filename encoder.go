@@ -0,0 +1,139 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// CopyFormat selects the escaping an Encoder applies to the documents it writes.
+type CopyFormat int
+
+const (
+	// CopyText writes PostgreSQL COPY text format, which is also the default format of MySQL
+	// LOAD DATA: backslash, newline, carriage return and tab are backslash-escaped and NULL is \N.
+	CopyText CopyFormat = iota
+	// CopyCSV writes CSV: every document is quoted with embedded quotes doubled,
+	// and NULL is an unquoted empty field.
+	CopyCSV
+)
+
+// Encoder writes JSON documents to an io.Writer as rows of a single-column COPY or LOAD DATA
+// input, so bulk loads can stream documents instead of building them in memory.
+// Documents are encoded with the options registered for T, one row per line.
+// An Encoder is not safe for concurrent use.
+type Encoder[T any] struct {
+	w      io.Writer
+	format CopyFormat
+	cfg    *config
+	buf    bytes.Buffer
+	enc    *json.Encoder
+	line   []byte
+	err    error
+}
+
+// NewEncoder creates a new Encoder writing rows in format to w.
+func NewEncoder[T any](w io.Writer, format CopyFormat) *Encoder[T] {
+	e := &Encoder[T]{w: w, format: format, cfg: configFor[T]()}
+	if e.cfg.plainJSON() && reflect.TypeFor[T]() != rawMessageType {
+		e.enc = json.NewEncoder(&e.buf)
+		e.enc.SetEscapeHTML(!e.cfg.noEscapeHTML)
+	}
+	return e
+}
+
+// Encode writes v as one row.
+// After a write error, Encode and EncodeNull return the same error without writing.
+func (e *Encoder[T]) Encode(v T) error {
+	if e.err != nil {
+		return e.err
+	}
+	data, err := e.marshal(v)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Encoder.Encode: %w", err)
+	}
+	e.line = appendCopyField(e.line[:0], data, e.format)
+	return e.writeLine()
+}
+
+// EncodeNull writes a NULL row.
+func (e *Encoder[T]) EncodeNull() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.line = e.line[:0]
+	if e.format == CopyText {
+		e.line = append(e.line, `\N`...)
+	}
+	return e.writeLine()
+}
+
+// EncodeNullable writes n as one row. When n is not valid it writes a NULL row,
+// or the configured document when NullAsJSON or NullAsEmptyObject is enabled for T.
+func (e *Encoder[T]) EncodeNullable(n Nullable[T]) error {
+	if !n.Valid {
+		if e.cfg.nullDocument != nil {
+			if e.err != nil {
+				return e.err
+			}
+			e.line = appendCopyField(e.line[:0], e.cfg.nullDocument, e.format)
+			return e.writeLine()
+		}
+		return e.EncodeNull()
+	}
+	return e.Encode(n.V)
+}
+
+// marshal encodes v, reusing the buffer of e for plain JSON configurations.
+func (e *Encoder[T]) marshal(v T) ([]byte, error) {
+	if e.enc == nil {
+		return encodeValue(v, e.cfg)
+	}
+	e.buf.Reset()
+	if err := e.enc.Encode(v); err != nil {
+		return nil, err
+	}
+	// Encoder.Encode terminates each value with a newline.
+	return e.buf.Bytes()[:e.buf.Len()-1], nil
+}
+
+// writeLine terminates the current line and writes it to the underlying writer.
+func (e *Encoder[T]) writeLine() error {
+	e.line = append(e.line, '\n')
+	if _, err := e.w.Write(e.line); err != nil {
+		e.err = fmt.Errorf("jsonsql.Encoder: %w", err)
+		return e.err
+	}
+	return nil
+}
+
+// appendCopyField appends data escaped as a field in format to dst.
+func appendCopyField(dst, data []byte, format CopyFormat) []byte {
+	if format == CopyCSV {
+		dst = append(dst, '"')
+		for _, c := range data {
+			if c == '"' {
+				dst = append(dst, '"')
+			}
+			dst = append(dst, c)
+		}
+		return append(dst, '"')
+	}
+	for _, c := range data {
+		switch c {
+		case '\\':
+			dst = append(dst, `\\`...)
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		case '\t':
+			dst = append(dst, `\t`...)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,123 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testCopyDoc struct {
+	Name string `json:"name"`
+}
+
+func TestEncoder_Text(t *testing.T) {
+	resetOptions[testCopyDoc](t)
+
+	var buf bytes.Buffer
+	enc := NewEncoder[testCopyDoc](&buf, CopyText)
+	if err := enc.Encode(testCopyDoc{Name: "a\\b \"c\"\ttab"}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if err := enc.EncodeNullable(Null[testCopyDoc]()); err != nil {
+		t.Fatalf("EncodeNullable failed: %v", err)
+	}
+	if err := enc.EncodeNullable(NullableFrom(testCopyDoc{Name: "<x>"})); err != nil {
+		t.Fatalf("EncodeNullable failed: %v", err)
+	}
+
+	expected := `{"name":"a\\\\b \\"c\\"\\ttab"}` + "\n" +
+		`\N` + "\n" +
+		`{"name":"\\u003cx\\u003e"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestEncoder_TextIndent(t *testing.T) {
+	resetOptions[testCopyDoc](t)
+	Configure[testCopyDoc](Indent("\t"))
+
+	var buf bytes.Buffer
+	if err := NewEncoder[testCopyDoc](&buf, CopyText).Encode(testCopyDoc{Name: "a"}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if expected := `{\n\t"name": "a"\n}` + "\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestEncoder_CSV(t *testing.T) {
+	resetOptions[testCopyDoc](t)
+
+	var buf bytes.Buffer
+	enc := NewEncoder[testCopyDoc](&buf, CopyCSV)
+	docs := []testCopyDoc{{Name: `say "hi", bye`}, {Name: "line\nbreak"}}
+	for _, d := range docs {
+		if err := enc.Encode(d); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+	if err := enc.EncodeNull(); err != nil {
+		t.Fatalf("EncodeNull failed: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if lines[2] != "" {
+		t.Errorf("expected empty NULL field, got %q", lines[2])
+	}
+	records, err := csv.NewReader(strings.NewReader(lines[0] + "\n" + lines[1] + "\n")).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	for i, d := range docs {
+		got, err := ScanJSON[testCopyDoc](records[i][0])
+		if err != nil {
+			t.Fatalf("ScanJSON failed: %v", err)
+		}
+		if got != d {
+			t.Errorf("row %d: expected %+v, got %+v", i, d, got)
+		}
+	}
+}
+
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestEncoder_WriteError(t *testing.T) {
+	resetOptions[testCopyDoc](t)
+
+	enc := NewEncoder[testCopyDoc](&failingWriter{n: 1}, CopyText)
+	if err := enc.Encode(testCopyDoc{}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	err := enc.Encode(testCopyDoc{})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err2 := enc.EncodeNull(); err2 != err {
+		t.Errorf("expected sticky error, got %v", err2)
+	}
+}
+
+func TestEncoder_EncodeError(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder[any](&buf, CopyText)
+	if err := enc.Encode(make(chan int)); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := enc.Encode(1); err != nil {
+		t.Fatalf("encode errors must not be sticky: %v", err)
+	}
+	if buf.String() != "1\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,81 @@
+package jsonsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InvalidValueError is returned by Scan and Value when a document contains a value
+// outside the set registered with AllowedValues.
+type InvalidValueError struct {
+	// Type is the Go type of the value.
+	Type string
+	// Value is the offending value.
+	Value any
+	// Allowed lists the allowed values in registration order.
+	Allowed []any
+}
+
+func (e *InvalidValueError) Error() string {
+	allowed := make([]string, len(e.Allowed))
+	for i, v := range e.Allowed {
+		allowed[i] = fmt.Sprintf("%#v", v)
+	}
+	return fmt.Sprintf("jsonsql: %#v is not an allowed %s value (allowed: %s)",
+		e.Value, e.Type, strings.Join(allowed, ", "))
+}
+
+// Is reports whether target is ErrValidation.
+func (e *InvalidValueError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// AllowedValues restricts values of type E, such as status or category string types,
+// to the given set wherever they appear inside a document. Scan and Value fail with an
+// *InvalidValueError for any other value; JSON null is not checked.
+//
+// It composes with other options registered earlier for E, such as StringNumber.
+func AllowedValues[E comparable](values ...E) Option {
+	t := reflect.TypeFor[E]()
+	set := make(map[E]bool, len(values))
+	allowed := make([]any, len(values))
+	for i, v := range values {
+		set[v] = true
+		allowed[i] = v
+	}
+	check := func(rv reflect.Value) error {
+		v := rv.Interface().(E)
+		if set[v] {
+			return nil
+		}
+		return &InvalidValueError{Type: t.String(), Value: v, Allowed: allowed}
+	}
+
+	return func(c *config) {
+		prev, hasPrev := c.hooks[t]
+		c.setHook(t, typeHook{
+			encode: func(rv reflect.Value) ([]byte, error) {
+				if err := check(rv); err != nil {
+					return nil, err
+				}
+				if hasPrev {
+					return prev.encode(rv)
+				}
+				return encodeJSON(rv.Interface(), c)
+			},
+			decode: func(data []byte, rv reflect.Value) error {
+				var err error
+				if hasPrev {
+					err = prev.decode(data, rv)
+				} else {
+					err = decodeLeaf(data, rv, c)
+				}
+				if err != nil {
+					return err
+				}
+				return check(rv)
+			},
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+type testStatus string
+
+type testTicket struct {
+	Status  testStatus   `json:"status"`
+	History []testStatus `json:"history"`
+}
+
+func TestAllowedValues_Scan(t *testing.T) {
+	resetOptions[testTicket](t)
+	Configure[testTicket](AllowedValues[testStatus]("open", "closed"))
+
+	var v Value[testTicket]
+	if err := v.Scan(`{"status":"open","history":["closed","open"]}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Status != "open" || len(v.V.History) != 2 {
+		t.Errorf("unexpected result: %+v", v.V)
+	}
+
+	err := v.Scan(`{"status":"open","history":["archived"]}`)
+	var invalid *InvalidValueError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidValueError, got %v", err)
+	}
+	if invalid.Value != testStatus("archived") || len(invalid.Allowed) != 2 {
+		t.Errorf("unexpected error contents: %+v", invalid)
+	}
+	expected := `jsonsql: "archived" is not an allowed jsonsql.testStatus value (allowed: "open", "closed")`
+	if invalid.Error() != expected {
+		t.Errorf("expected %s, got %s", expected, invalid.Error())
+	}
+}
+
+func TestAllowedValues_Value(t *testing.T) {
+	resetOptions[testStatus](t)
+	Configure[testStatus](AllowedValues[testStatus]("open"))
+
+	result, err := NewValue[testStatus]("open").Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `"open"` {
+		t.Errorf("unexpected result: %s", result)
+	}
+
+	var invalid *InvalidValueError
+	if _, err := NewValue[testStatus]("draft").Value(); !errors.As(err, &invalid) {
+		t.Errorf("expected InvalidValueError, got %v", err)
+	}
+}
+
+func TestAllowedValues_ComposesWithHooks(t *testing.T) {
+	resetOptions[map[string]testCents](t)
+	Configure[map[string]testCents](StringNumber[testCents](), AllowedValues[testCents](1999, 1))
+
+	var v Value[map[string]testCents]
+	if err := v.Scan(`{"a":"19.99","b":"0.01"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V["a"] != 1999 || v.V["b"] != 1 {
+		t.Errorf("unexpected result: %v", v.V)
+	}
+
+	var invalid *InvalidValueError
+	if err := v.Scan(`{"a":"0.03"}`); !errors.As(err, &invalid) {
+		t.Errorf("expected InvalidValueError, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package jsonsql
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner      = (*Envelope[struct{}])(nil)
+	_ ContextScanner   = (*Envelope[struct{}])(nil)
+	_ driver.Valuer    = Envelope[struct{}]{}
+	_ json.Marshaler   = Envelope[struct{}]{}
+	_ json.Unmarshaler = (*Envelope[struct{}])(nil)
+)
+
+// Envelope[T] is an event with standard metadata around a payload, stored as one JSON document,
+// for transactional outbox tables and event logs:
+//
+//	{"id":"…","type":"order.created","occurred_at":"2024-05-01T12:00:00Z","trace_id":"…","payload":{…}}
+//
+// The payload is encoded and decoded with the options registered for T. Envelope[T] is used
+// as a NOT NULL column type like Value[T].
+type Envelope[T any] struct {
+	// ID identifies the event, a random UUID when created with NewEnvelope.
+	ID string
+	// Type is the event type, such as "order.created".
+	Type string
+	// OccurredAt is the time the event occurred.
+	OccurredAt time.Time
+	// TraceID correlates the event with the request that produced it. It is omitted when empty.
+	TraceID string
+	// Payload is the event data.
+	Payload T
+}
+
+// envelopeDocument is the stored form of an Envelope[T].
+type envelopeDocument struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	TraceID    string          `json:"trace_id,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope creates an Envelope[T] for an event of the given type that occurred now,
+// with a random UUID as its ID.
+func NewEnvelope[T any](eventType string, payload T) Envelope[T] {
+	return Envelope[T]{
+		ID:         newEventID(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Payload:    payload,
+	}
+}
+
+// WithTraceID returns a copy of e with the given trace ID.
+func (e Envelope[T]) WithTraceID(traceID string) Envelope[T] {
+	e.TraceID = traceID
+	return e
+}
+
+// newEventID returns a random version 4 UUID.
+func newEventID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// MarshalJSON implements json.Marshaler interface, encoding the payload with the options registered for T.
+func (e Envelope[T]) MarshalJSON() ([]byte, error) {
+	cfg := configFor[T]()
+	if cfg.documentCodec != nil {
+		return nil, errors.New("jsonsql: Envelope does not support document codecs")
+	}
+	payload, err := encodeValue(e.Payload, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(envelopeDocument{
+		ID:         e.ID,
+		Type:       e.Type,
+		OccurredAt: e.OccurredAt,
+		TraceID:    e.TraceID,
+		Payload:    payload,
+	}, cfg)
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals the stored envelope, decoding the payload with the options registered for T.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (e *Envelope[T]) Scan(src any) error {
+	return e.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (e *Envelope[T]) ScanContext(ctx context.Context, src any) error {
+	var doc envelopeDocument
+	null, err := decodeSourceContext(ctx, src, &doc, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Envelope.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	if err := e.fromDocument(doc); err != nil {
+		return fmt.Errorf("jsonsql.Envelope.Scan: %w", err)
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface, decoding the payload with the options registered for T.
+func (e *Envelope[T]) UnmarshalJSON(data []byte) error {
+	var doc envelopeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return e.fromDocument(doc)
+}
+
+// fromDocument sets e from its stored form. A missing or null payload decodes as the zero value.
+func (e *Envelope[T]) fromDocument(doc envelopeDocument) error {
+	var payload T
+	if len(doc.Payload) > 0 {
+		if _, err := decodeDocument(doc.Payload, &payload, configFor[T](), ErrorOnEmpty); err != nil {
+			return fmt.Errorf("payload: %w", err)
+		}
+	}
+	*e = Envelope[T]{
+		ID:         doc.ID,
+		Type:       doc.Type,
+		OccurredAt: doc.OccurredAt,
+		TraceID:    doc.TraceID,
+		Payload:    payload,
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals the envelope to JSON bytes for database storage.
+func (e Envelope[T]) Value() (driver.Value, error) {
+	data, err := e.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Envelope.Value: %w", err)
+	}
+	return configFor[T]().driverValue(data), nil
+}
@@ -0,0 +1,78 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvelope(t *testing.T) {
+	e := NewEnvelope("profile.updated", testProfile{Name: "Alice"}).WithTraceID("4bf92f3577b34da6")
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(e.ID) {
+		t.Errorf("expected a random UUID, got %q", e.ID)
+	}
+	if NewEnvelope("x", 1).ID == e.ID {
+		t.Error("expected distinct IDs")
+	}
+	if time.Since(e.OccurredAt) > time.Minute || e.OccurredAt.Location() != time.UTC {
+		t.Errorf("unexpected OccurredAt %v", e.OccurredAt)
+	}
+
+	e.OccurredAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	result, err := e.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"id":"` + e.ID + `","type":"profile.updated","occurred_at":"2024-05-01T12:00:00Z",` +
+		`"trace_id":"4bf92f3577b34da6","payload":{"name":"Alice","email":""}}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var scanned Envelope[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned != e {
+		t.Errorf("expected %+v, got %+v", e, scanned)
+	}
+
+	if err := scanned.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if err := scanned.Scan(`{"id":"1","payload":{"name":1}}`); err == nil || !strings.Contains(err.Error(), "payload:") {
+		t.Errorf("expected payload error, got %v", err)
+	}
+	if err := scanned.Scan(`{"id":"1","type":"deleted"}`); err != nil || scanned.Payload != (testProfile{}) {
+		t.Errorf("expected missing payload to decode as zero value, got %+v, %v", scanned, err)
+	}
+}
+
+func TestEnvelope_PayloadOptions(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](EscapeHTML(false))
+
+	e := Envelope[testProfile]{ID: "1", Type: "t", Payload: testProfile{Name: "<Bob>"}}
+	result, err := e.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !strings.Contains(string(result.([]byte)), `"payload":{"name":"<Bob>","email":""}`) {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	type outbox struct {
+		Event Envelope[testProfile] `json:"event"`
+	}
+	data, err := json.Marshal(outbox{Event: e})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded outbox
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Event != e {
+		t.Errorf("expected round trip, got %+v, %v", decoded, err)
+	}
+}
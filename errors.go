@@ -0,0 +1,116 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Error classes. Scan and Value errors wrap one of these sentinels in addition to the underlying
+// error (for example a *json.SyntaxError), so callers can branch on the class with errors.Is
+// and still inspect the details with errors.As. See also ErrNullNotAllowed, ErrEmptyInput,
+// ErrTrailingData and ErrInvalidUTF8.
+var (
+	// ErrUnsupportedSrcType is returned by Scan for source values it cannot decode,
+	// such as an int scanned into a JSON wrapper.
+	ErrUnsupportedSrcType = permanent("jsonsql: unsupported source type")
+	// ErrInvalidJSON is returned by Scan for malformed JSON.
+	ErrInvalidJSON = permanent("jsonsql: invalid JSON")
+	// ErrTooLarge is returned by Scan and Value for documents exceeding MaxDocumentSize.
+	ErrTooLarge = permanent("jsonsql: document too large")
+	// ErrValidation is returned by Scan and Value when a Validator rejects the value
+	// (see ValidateValues) and for values rejected by AllowedValues (*InvalidValueError).
+	ErrValidation = permanent("jsonsql: validation failed")
+)
+
+// FieldError describes a decode failure at a location inside a document.
+type FieldError struct {
+	// Path is the JSON Pointer (RFC 6901) of the failing value, e.g. /items/3/price.
+	Path string
+	// Err is the underlying decode error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("jsonsql: %s: %v", path, e.Err)
+}
+
+// Unwrap returns the underlying decode error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// unsupportedSrcType returns the error for a source value of unsupported type.
+func unsupportedSrcType(src any) error {
+	return fmt.Errorf("%w %T", ErrUnsupportedSrcType, src)
+}
+
+// invalidJSON wraps err with ErrInvalidJSON if it reports malformed JSON.
+func invalidJSON(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+	return err
+}
+
+// MaxDocumentSize makes Scan and Value fail with ErrTooLarge for documents larger than n bytes,
+// protecting services from unexpectedly huge rows. n <= 0 disables the limit, which is the default.
+// Scan checks the size of the raw source value before decoding it.
+func MaxDocumentSize(n int) Option {
+	return func(c *config) {
+		c.maxSize = n
+	}
+}
+
+// checkSize fails with ErrTooLarge if size exceeds the limit configured in cfg.
+func checkSize(size int, cfg *config) error {
+	if cfg.maxSize > 0 && size > cfg.maxSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d", ErrTooLarge, size, cfg.maxSize)
+	}
+	return nil
+}
+
+// Validator is implemented by types that check their own invariants.
+// With ValidateValues enabled, Scan calls Validate after decoding and Value before encoding.
+type Validator interface {
+	Validate() error
+}
+
+// ValidateValues makes Scan and Value call Validate on values implementing Validator,
+// with either a value or a pointer receiver, and fail with an error wrapping ErrValidation
+// and the error returned by Validate. A failed Scan does not change Valid of a Nullable[T],
+// while V already holds the rejected value, so check the error rather than Valid.
+func ValidateValues(enabled bool) Option {
+	return func(c *config) {
+		c.validate = enabled
+	}
+}
+
+// validate calls Validate on v if it implements Validator. If only *T implements it for a
+// non-pointer v of type T, Validate is called on a copy of v.
+func validate(v any) error {
+	val, ok := v.(Validator)
+	if !ok {
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() || rv.Kind() == reflect.Pointer {
+			return nil
+		}
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		if val, ok = p.Interface().(Validator); !ok {
+			return nil
+		}
+	}
+	if err := val.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return nil
+}
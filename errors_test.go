@@ -0,0 +1,199 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testOrder struct {
+	ID    int             `json:"id"`
+	Name  string          `json:"name"`
+	Items []testOrderItem `json:"items"`
+	Tags  map[string]int  `json:"tags"`
+}
+
+type testOrderItem struct {
+	SKU   string `json:"sku"`
+	Price int8   `json:"price"`
+}
+
+func fieldErrorPaths(t *testing.T, err error) []string {
+	t.Helper()
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined error, got %T: %v", err, err)
+	}
+	var paths []string
+	for _, e := range joined.Unwrap() {
+		var fe *FieldError
+		if !errors.As(e, &fe) {
+			t.Fatalf("expected *FieldError, got %T: %v", e, e)
+		}
+		paths = append(paths, fe.Path)
+	}
+	return paths
+}
+
+func TestCollectErrors(t *testing.T) {
+	resetOptions[testOrder](t)
+	Configure[testOrder](CollectErrors(true))
+
+	input := `{
+		"id": "not-a-number",
+		"name": "order",
+		"items": [{"sku": "a", "price": 1}, {"sku": 2, "price": 1000}, {"sku": "c", "price": 3}],
+		"tags": {"x": 1, "y": "two"}
+	}`
+
+	var v Value[testOrder]
+	err := v.Scan(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := []string{"/id", "/items/1/sku", "/items/1/price", "/tags/y"}
+	paths := fieldErrorPaths(t, errors.Unwrap(err))
+	if len(paths) != len(expected) {
+		t.Fatalf("expected paths %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("expected paths %v, got %v", expected, paths)
+			break
+		}
+	}
+
+	if v.V.Name != "order" || len(v.V.Items) != 3 || v.V.Items[2].Price != 3 || v.V.Tags["x"] != 1 {
+		t.Errorf("expected valid fields to be populated, got %+v", v.V)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("expected *json.UnmarshalTypeError in chain, got %v", err)
+	}
+}
+
+func TestCollectErrors_NoErrors(t *testing.T) {
+	resetOptions[testOrder](t)
+	Configure[testOrder](CollectErrors(true))
+
+	var n Nullable[testOrder]
+	if err := n.Scan(`{"id":1,"items":[{"sku":"a","price":1}]}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V.ID != 1 || n.V.Items[0].SKU != "a" {
+		t.Errorf("unexpected result: %+v", n)
+	}
+}
+
+func TestCollectErrors_Disabled_FirstErrorOnly(t *testing.T) {
+	var v Value[testOrder]
+
+	err := v.Scan(`{"id":"x","name":1}`)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := errors.Unwrap(err).(interface{ Unwrap() []error }); ok {
+		t.Errorf("expected a single error, got %v", err)
+	}
+}
+
+func TestFieldError_Error(t *testing.T) {
+	err := &FieldError{Path: "/items/3/price", Err: errors.New("boom")}
+	if err.Error() != "jsonsql: /items/3/price: boom" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+
+	root := &FieldError{Err: errors.New("boom")}
+	if root.Error() != "jsonsql: (root): boom" {
+		t.Errorf("unexpected message: %s", root.Error())
+	}
+}
+
+type testValidated struct {
+	Qty int `json:"qty"`
+}
+
+func (v *testValidated) Validate() error {
+	if v.Qty < 0 {
+		return errors.New("qty must not be negative")
+	}
+	return nil
+}
+
+func TestErrorClasses(t *testing.T) {
+	resetOptions[testValidated](t)
+	Configure[testValidated](ValidateValues(true), MaxDocumentSize(32))
+
+	tests := []struct {
+		name   string
+		src    any
+		target error
+	}{
+		{"unsupported", 42, ErrUnsupportedSrcType},
+		{"syntax", `{"qty":`, ErrInvalidJSON},
+		{"syntax bytes", []byte(`{qty:1}`), ErrInvalidJSON},
+		{"too large", `{"qty":1,"padding":"` + strings.Repeat("x", 32) + `"}`, ErrTooLarge},
+		{"validation", `{"qty":-1}`, ErrValidation},
+		{"null", nil, ErrNullNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testValidated]
+			err := v.Scan(tt.src)
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+
+	var n Nullable[testValidated]
+	if err := n.Scan(`{"qty":-1}`); !errors.Is(err, ErrValidation) || n.Valid {
+		t.Errorf("expected invalid Nullable and ErrValidation, got %+v, %v", n, err)
+	}
+	if _, err := NewValue(testValidated{Qty: -1}).Value(); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation from Value, got %v", err)
+	}
+	if _, err := NewValue(testValidated{Qty: 1}).Value(); err != nil {
+		t.Errorf("Value failed: %v", err)
+	}
+}
+
+func TestErrorClasses_KeepUnderlyingError(t *testing.T) {
+	var v Value[map[string]any]
+	err := v.Scan(`{"a":}`)
+	var syntaxErr *json.SyntaxError
+	if !errors.Is(err, ErrInvalidJSON) || !errors.As(err, &syntaxErr) {
+		t.Errorf("expected ErrInvalidJSON wrapping *json.SyntaxError, got %v", err)
+	}
+
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](UseNumber(true))
+	if err := v.Scan(`{"a":1`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON with UseNumber, got %v", err)
+	}
+}
+
+func TestMaxDocumentSize_Value(t *testing.T) {
+	resetOptions[[]int](t)
+	Configure[[]int](MaxDocumentSize(5))
+
+	if _, err := NewValue([]int{1, 2}).Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if _, err := NewValue([]int{1, 2, 3}).Value(); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if _, err := BatchJSON([][]int{{1, 2, 3}}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge from BatchJSON, got %v", err)
+	}
+}
+
+func TestInvalidValueError_IsValidation(t *testing.T) {
+	err := error(&InvalidValueError{Type: "Status", Value: "x"})
+	if !errors.Is(err, ErrValidation) {
+		t.Error("expected InvalidValueError to match ErrValidation")
+	}
+}
@@ -0,0 +1,119 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// errorOffset returns the byte offset encoding/json reports for err, which is the number of
+// bytes read when the error occurred.
+func errorOffset(err error) (int64, bool) {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr.Offset, true
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Offset, true
+	}
+	return 0, false
+}
+
+// locateError wraps a decode error of the JSON document data in a *FieldError with the JSON
+// Pointer of the value it occurred in, so failures inside wide documents can be found without
+// counting bytes. Errors at the root or without an offset are returned unchanged.
+func locateError(data []byte, err error) error {
+	offset, ok := errorOffset(err)
+	if !ok {
+		return err
+	}
+	path := tokensAt(data, int(offset)-1)
+	if len(path) == 0 {
+		return err
+	}
+	return &FieldError{Path: jsonPointer(path), Err: err}
+}
+
+// tokensAt returns the JSON Pointer reference tokens of the innermost value of the JSON
+// document data containing the byte at pos. Object keys and the punctuation between members
+// belong to the enclosing object or array. Data after pos need not be well-formed.
+func tokensAt(data []byte, pos int) []string {
+	type frame struct {
+		object bool
+		// token is the reference token of the current member.
+		token string
+		// inValue reports whether the scanner is inside the value of the current member.
+		inValue bool
+	}
+	var stack []frame
+	path := func() []string {
+		tokens := make([]string, 0, len(stack))
+		for _, f := range stack {
+			if !f.inValue {
+				break
+			}
+			tokens = append(tokens, f.token)
+		}
+		return tokens
+	}
+	if pos >= len(data) {
+		pos = len(data) - 1
+	}
+
+	for i := 0; i <= pos; i++ {
+		switch c := data[i]; c {
+		case ' ', '\t', '\r', '\n':
+		case '{', '[':
+			if i == pos {
+				return path()
+			}
+			stack = append(stack, frame{object: c == '{', token: "0", inValue: c == '['})
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if n := len(stack); n > 0 {
+				top := &stack[n-1]
+				if top.object || i == pos {
+					top.inValue = false
+				} else if index, err := strconv.Atoi(top.token); err == nil {
+					top.token = strconv.Itoa(index + 1)
+				}
+			}
+		case ':':
+			if n := len(stack); n > 0 && i < pos {
+				stack[n-1].inValue = true
+			}
+		case '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			n := len(stack)
+			if n > 0 && stack[n-1].object && !stack[n-1].inValue {
+				var key string
+				if i < len(data) && json.Unmarshal(data[start:i+1], &key) == nil {
+					stack[n-1].token = key
+				}
+			}
+		default:
+			for i+1 <= pos && !isDelimiter(data[i+1]) {
+				i++
+			}
+		}
+	}
+	return path()
+}
+
+// isDelimiter reports whether c ends a JSON literal.
+func isDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', ',', ':', '{', '}', '[', ']', '"':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package jsonsql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCart struct {
+	Items []struct {
+		Name  string `json:"name"`
+		Price int    `json:"price"`
+	} `json:"items"`
+	Meta map[string]int `json:"meta"`
+}
+
+func TestDecodeError_Path(t *testing.T) {
+	tests := []struct {
+		doc  string
+		path string
+	}{
+		{`{"items":[{"price":1},{"price":2},{"price":3},{"name":"x","price":"9.99"}]}`, "/items/3/price"},
+		{`{"items":[{"name":"a"}],"meta":{"a/b":"x"}}`, "/meta/a~1b"},
+		{`{"items":{}}`, "/items"},
+		{`{"items":[{"name":"a", "price": 1x}]}`, "/items/0/price"},
+	}
+	for _, tt := range tests {
+		var v Value[testCart]
+		err := v.Scan(tt.doc)
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Errorf("Scan(%s): expected FieldError, got %v", tt.doc, err)
+			continue
+		}
+		if fieldErr.Path != tt.path {
+			t.Errorf("Scan(%s): expected path %s, got %s", tt.doc, tt.path, fieldErr.Path)
+		}
+		if !strings.Contains(err.Error(), tt.path) {
+			t.Errorf("Scan(%s): expected path in message, got %v", tt.doc, err)
+		}
+	}
+
+	// Errors at the root are not wrapped, and the error classes are kept.
+	var v Value[testCart]
+	err := v.Scan(`[1]`)
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		t.Errorf("expected no FieldError at the root, got %v", err)
+	}
+	if err := v.Scan(`{"items":[{"price":1x}]}`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+}
+
+func TestDecodeError_PathWalked(t *testing.T) {
+	resetOptions[testCart](t)
+	Configure[testCart](KeyAlias[testCart]("meta", "metadata"))
+
+	var v Value[testCart]
+	err := v.Scan(`{"items":[{"price":1},{"price":true}],"metadata":{}}`)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != "/items/1/price" {
+		t.Errorf("expected FieldError at /items/1/price, got %v", err)
+	}
+}
+
+func TestTokensAt(t *testing.T) {
+	data := []byte(`{"a": [1, {"b": "x,y"}], "c": 2}`)
+	tests := []struct {
+		pos  int
+		want []string
+	}{
+		{0, []string{}},
+		{2, []string{}},
+		{6, []string{"a"}},
+		{7, []string{"a", "0"}},
+		{8, []string{"a"}},
+		{11, []string{"a", "1"}},
+		{18, []string{"a", "1", "b"}},
+		{30, []string{"c"}},
+		{31, []string{}},
+	}
+	for _, tt := range tests {
+		if got := tokensAt(data, tt.pos); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokensAt(%d) at %q: expected %v, got %v", tt.pos, data[tt.pos], tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Expiring[struct{}])(nil)
+	_ ContextScanner = (*Expiring[struct{}])(nil)
+	_ driver.Valuer  = Expiring[struct{}]{}
+)
+
+// expiryLayout is the format of expiration times stored by Expiring[T]. It is RFC 3339 in UTC
+// with a fixed number of fractional digits, so stored times compare correctly as strings.
+const expiryLayout = "2006-01-02T15:04:05.000000Z"
+
+// Expiring[T] is a generic type for NOT NULL JSON columns of cache-like tables whose rows
+// expire. Value stores V with its expiration time:
+//
+//	{"expires_at":"2024-05-01T12:00:00.000000Z","payload":{…}}
+//
+// The payload is encoded and decoded with the options registered for T. A zero ExpiresAt
+// never expires and is omitted. Scan does not reject expired documents; check Expired after
+// scanning, and purge rows with ExpiredBefore.
+type Expiring[T any] struct {
+	V T
+	// ExpiresAt is the expiration time, or the zero time for documents that never expire.
+	ExpiresAt time.Time
+}
+
+// expiringDocument is the stored form of an Expiring[T].
+type expiringDocument struct {
+	ExpiresAt string          `json:"expires_at,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// NewExpiring creates a new Expiring[T] with the given value expiring ttl from now.
+func NewExpiring[T any](v T, ttl time.Duration) Expiring[T] {
+	return Expiring[T]{V: v, ExpiresAt: time.Now().Add(ttl).UTC()}
+}
+
+// Get returns the value.
+func (e Expiring[T]) Get() T {
+	return e.V
+}
+
+// Expired reports whether the document has expired at now.
+func (e Expiring[T]) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals the stored document, decoding the payload into V.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (e *Expiring[T]) Scan(src any) error {
+	return e.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (e *Expiring[T]) ScanContext(ctx context.Context, src any) error {
+	var doc expiringDocument
+	null, err := decodeSourceContext(ctx, src, &doc, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Expiring.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	var expiresAt time.Time
+	if doc.ExpiresAt != "" {
+		if expiresAt, err = time.Parse(time.RFC3339Nano, doc.ExpiresAt); err != nil {
+			return fmt.Errorf("jsonsql.Expiring.Scan: expires_at: %w", err)
+		}
+	}
+	var v T
+	if len(doc.Payload) > 0 {
+		if _, err := decodeDocument(doc.Payload, &v, configFor[T](), ErrorOnEmpty); err != nil {
+			return fmt.Errorf("jsonsql.Expiring.Scan: payload: %w", err)
+		}
+	}
+	e.V, e.ExpiresAt = v, expiresAt.UTC()
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V with its expiration time to JSON bytes for database storage.
+func (e Expiring[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	if cfg.documentCodec != nil {
+		return nil, fmt.Errorf("jsonsql.Expiring.Value: %w", errors.New("jsonsql: Expiring does not support document codecs"))
+	}
+	payload, err := encodeValue(e.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Expiring.Value: %w", err)
+	}
+	doc := expiringDocument{Payload: payload}
+	if !e.ExpiresAt.IsZero() {
+		doc.ExpiresAt = ExpiryArg(e.ExpiresAt)
+	}
+	data, err := encodeJSON(doc, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Expiring.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+// ExpiryArg formats t like the expiration times stored by Expiring[T], as the query argument
+// of ExpiredBefore.
+func ExpiryArg(t time.Time) string {
+	return t.UTC().Format(expiryLayout)
+}
+
+// ExpiredBefore returns an SQL condition matching rows whose JSON column stores an Expiring
+// document that has expired at the time given by the query parameter param, like Expired.
+// The parameter must be bound to ExpiryArg of that time:
+//
+//	query := `DELETE FROM cache WHERE ` + jsonsql.ExpiredBefore(jsonsql.Postgres, "doc", "$1")
+//	_, err := db.ExecContext(ctx, query, jsonsql.ExpiryArg(time.Now()))
+//
+// Documents that never expire are not matched. column and param are inserted as is and must
+// be trusted.
+func ExpiredBefore(d Dialect, column, param string) string {
+	path := "'$.expires_at'"
+	switch d {
+	case MySQL:
+		return "JSON_UNQUOTE(JSON_EXTRACT(" + column + ", " + path + ")) <= " + param
+	case SQLite:
+		return "json_extract(" + column + ", " + path + ") <= " + param
+	default:
+		return "(" + column + " ->> 'expires_at') <= " + param
+	}
+}
@@ -0,0 +1,84 @@
+package jsonsql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpiring(t *testing.T) {
+	now := time.Now()
+	e := NewExpiring(testProfile{Name: "Alice"}, time.Hour)
+	if e.Expired(now) || !e.Expired(now.Add(2*time.Hour)) {
+		t.Errorf("unexpected Expired for %v", e.ExpiresAt)
+	}
+	if (Expiring[int]{}).Expired(now.Add(1000 * time.Hour)) {
+		t.Error("expected zero ExpiresAt never to expire")
+	}
+
+	e.ExpiresAt = time.Date(2024, 5, 1, 14, 0, 0, 5000, time.FixedZone("CEST", 2*3600))
+	result, err := e.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"expires_at":"2024-05-01T12:00:00.000005Z","payload":{"name":"Alice","email":""}}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var scanned Expiring[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V.Name != "Alice" || !scanned.ExpiresAt.Equal(e.ExpiresAt) || scanned.ExpiresAt.Location() != time.UTC {
+		t.Errorf("unexpected value %+v", scanned)
+	}
+	if !scanned.Expired(e.ExpiresAt) {
+		t.Error("expected document to be expired at its expiration time")
+	}
+
+	never, err := Expiring[int]{V: 1}.Value()
+	if err != nil || string(never.([]byte)) != `{"payload":1}` {
+		t.Errorf("unexpected document %s, %v", never, err)
+	}
+	var n Expiring[int]
+	if err := n.Scan(never); err != nil || n.V != 1 || !n.ExpiresAt.IsZero() {
+		t.Errorf("unexpected value %+v, %v", n, err)
+	}
+
+	// Times written by other clients are accepted.
+	if err := n.Scan(`{"expires_at":"2024-05-01T14:00:00+02:00","payload":2}`); err != nil || n.ExpiresAt.Hour() != 12 {
+		t.Errorf("unexpected value %+v, %v", n, err)
+	}
+	if err := n.Scan(`{"expires_at":"tomorrow","payload":2}`); err == nil || !strings.Contains(err.Error(), "expires_at") {
+		t.Errorf("expected expires_at error, got %v", err)
+	}
+	if err := n.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestExpiredBefore(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		param    string
+		expected string
+	}{
+		{Postgres, "$1", `(doc ->> 'expires_at') <= $1`},
+		{MySQL, "?", `JSON_UNQUOTE(JSON_EXTRACT(doc, '$.expires_at')) <= ?`},
+		{SQLite, "?", `json_extract(doc, '$.expires_at') <= ?`},
+	}
+	for _, tt := range tests {
+		if got := ExpiredBefore(tt.dialect, "doc", tt.param); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.dialect, tt.expected, got)
+		}
+	}
+
+	// Stored times compare as strings in time order.
+	early := ExpiryArg(time.Date(2024, 5, 1, 9, 59, 59, 999999000, time.UTC))
+	late := ExpiryArg(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	if early >= late || late != "2024-05-01T10:00:00.000000Z" {
+		t.Errorf("unexpected order of %s and %s", early, late)
+	}
+}
@@ -0,0 +1,167 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldHook is a custom JSON encoder/decoder for struct fields of one Go type, selected by tag.
+type fieldHook struct {
+	// typ is the field type the hook applies to; fields of type *typ are accepted as well.
+	typ  reflect.Type
+	hook typeHook
+}
+
+// FieldHook registers the field hook name, which stores struct fields of type F (or *F)
+// tagged `jsonsql:"hook=name"` as their representation S. Unlike a type hook it applies
+// only where a field asks for it, so a domain type can be stored differently per field
+// without a MarshalJSON method:
+//
+//	type Order struct {
+//		Total    Decimal `json:"total" jsonsql:"hook=cents"`
+//		Discount *Decimal `json:"discount,omitempty" jsonsql:"hook=cents"`
+//	}
+//
+//	jsonsql.SetDefaults(jsonsql.FieldHook("cents",
+//		func(d Decimal) (int64, error) { return d.Cents() },
+//		func(cents int64) (Decimal, error) { return DecimalFromCents(cents), nil },
+//	))
+//
+// The hook name may be combined with other jsonsql tag options, as in
+// `jsonsql:"required,hook=cents"`. A JSON null sets pointer fields to nil and leaves other
+// fields unchanged. Scan and Value fail with a *TypeParamError for types with fields tagged
+// with a hook that is not registered or registered for another type.
+func FieldHook[F, S any](name string, toStored func(F) (S, error), fromStored func(S) (F, error)) Option {
+	t := reflect.TypeFor[F]()
+	h := fieldHook{typ: t, hook: typeHook{
+		encode: func(rv reflect.Value) ([]byte, error) {
+			s, err := toStored(rv.Interface().(F))
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(s)
+		},
+		decode: func(data []byte, rv reflect.Value) error {
+			var s S
+			if err := json.Unmarshal(data, &s); err != nil {
+				return err
+			}
+			v, err := fromStored(s)
+			if err != nil {
+				return err
+			}
+			rv.Set(reflect.ValueOf(&v).Elem())
+			return nil
+		},
+	}}
+	return func(c *config) {
+		if c.fieldHooks == nil {
+			c.fieldHooks = map[string]fieldHook{}
+		}
+		c.fieldHooks[name] = h
+	}
+}
+
+// fieldHookName returns the hook name of the `jsonsql:"hook=name"` tag option of f.
+func fieldHookName(f field) (string, bool) {
+	opts := f.tag.Get("jsonsql")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if name, ok := strings.CutPrefix(opt, "hook="); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// fieldHookFor returns the field hook registered on c that applies to f.
+func (c *config) fieldHookFor(f field) (fieldHook, bool) {
+	if len(c.fieldHooks) == 0 {
+		return fieldHook{}, false
+	}
+	name, ok := fieldHookName(f)
+	if !ok {
+		return fieldHook{}, false
+	}
+	h, ok := c.fieldHooks[name]
+	if !ok || !h.applies(f.typ) {
+		return fieldHook{}, false
+	}
+	return h, true
+}
+
+// applies reports whether h can encode and decode fields of type t.
+func (h fieldHook) applies(t reflect.Type) bool {
+	return t == h.typ || t.Kind() == reflect.Pointer && t.Elem() == h.typ
+}
+
+// encodeField appends the stored representation of the field value fv to buf.
+func (h fieldHook) encodeField(buf *bytes.Buffer, fv reflect.Value) error {
+	if fv.Type() != h.typ {
+		if fv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	data, err := h.hook.encode(fv)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// decodeField decodes the stored representation data into the field value fv.
+func (h fieldHook) decodeField(data []byte, fv reflect.Value) error {
+	if fv.Type() != h.typ {
+		if isJSONNull(data) {
+			fv.SetZero()
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(h.typ))
+		}
+		fv = fv.Elem()
+	}
+	if isJSONNull(data) {
+		return nil
+	}
+	return h.hook.decode(data, fv)
+}
+
+// badFieldHook finds a struct field inside t tagged with a field hook that is not registered
+// on cfg or does not apply to the field type, and describes the problem.
+func badFieldHook(t reflect.Type, cfg *config, seen map[reflect.Type]bool) (string, bool) {
+	if _, ok := cfg.hooks[t]; ok || implementsJSON(t) || seen[t] {
+		return "", false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return badFieldHook(t.Elem(), cfg, seen)
+	case reflect.Struct:
+		for _, f := range typeFields(t) {
+			name, ok := fieldHookName(f)
+			if !ok {
+				if reason, ok := badFieldHook(f.typ, cfg, seen); ok {
+					return reason, true
+				}
+				continue
+			}
+			h, ok := cfg.fieldHooks[name]
+			if !ok {
+				return fmt.Sprintf("field hook %q of %v.%s is not registered", name, t, f.name), true
+			}
+			if !h.applies(f.typ) {
+				return fmt.Sprintf("field hook %q applies to %v, not to %v.%s of type %v", name, h.typ, t, f.name, f.typ), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package jsonsql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testDecimal is a fixed-point amount with two decimal places.
+type testDecimal struct {
+	Units int64
+}
+
+func (d testDecimal) String() string {
+	return fmt.Sprintf("%d.%02d", d.Units/100, d.Units%100)
+}
+
+type testLineItem struct {
+	Name     string       `json:"name"`
+	Price    testDecimal  `json:"price" jsonsql:"hook=cents"`
+	Discount *testDecimal `json:"discount,omitempty" jsonsql:"required,hook=cents"`
+	Raw      testDecimal  `json:"raw"`
+}
+
+func centsHook() Option {
+	return FieldHook("cents",
+		func(d testDecimal) (int64, error) { return d.Units, nil },
+		func(cents int64) (testDecimal, error) {
+			if cents < 0 {
+				return testDecimal{}, errors.New("negative amount")
+			}
+			return testDecimal{Units: cents}, nil
+		},
+	)
+}
+
+func TestFieldHook(t *testing.T) {
+	resetOptions[testLineItem](t)
+	Configure[testLineItem](centsHook())
+
+	discount := testDecimal{Units: 150}
+	item := testLineItem{Name: "pen", Price: testDecimal{Units: 1999}, Discount: &discount, Raw: testDecimal{Units: 5}}
+	result, err := NewValue(item).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"name":"pen","price":1999,"discount":150,"raw":{"Units":5}}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var v Value[testLineItem]
+	if err := v.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Price.String() != "19.99" || v.V.Discount == nil || v.V.Discount.String() != "1.50" || v.V.Raw.Units != 5 {
+		t.Errorf("unexpected value %+v", v.V)
+	}
+
+	if err := v.Scan(`{"name":"pen","price":100,"discount":null}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Discount != nil || v.V.Price.Units != 100 {
+		t.Errorf("unexpected value %+v", v.V)
+	}
+
+	if err := v.Scan(`{"price":-1,"discount":null}`); err == nil || !strings.Contains(err.Error(), "negative amount") {
+		t.Errorf("expected hook error, got %v", err)
+	}
+	if err := v.Scan(`{"price":100}`); !errors.Is(err, ErrMissingField) {
+		t.Errorf("expected ErrMissingField, got %v", err)
+	}
+}
+
+func TestFieldHook_NotRegistered(t *testing.T) {
+	resetOptions[testLineItem](t)
+
+	var typeErr *TypeParamError
+	var v Value[testLineItem]
+	err := v.Scan(`{"price":100}`)
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected TypeParamError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `field hook "cents"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	Configure[testLineItem](FieldHook("cents",
+		func(s string) (string, error) { return s, nil },
+		func(s string) (string, error) { return s, nil },
+	))
+	if _, err := NewValue(testLineItem{}).Value(); !errors.As(err, &typeErr) {
+		t.Errorf("expected TypeParamError for mismatched type, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package jsonsql
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns the leaf values of d keyed by their dotted paths, for feeding CSV or
+// warehouse exports from JSON columns without defining a struct per report:
+//
+//	{"id":1,"address":{"city":"Oslo"},"tags":["a","b"]}
+//
+// flattens to
+//
+//	{"id":1, "address.city":"Oslo", "tags.0":"a", "tags.1":"b"}
+//
+// Array elements are keyed by their index. Empty objects and arrays are kept as leaves so that
+// Unflatten restores them, and a scalar document is returned under the empty key. A NULL
+// document flattens to an empty map.
+//
+// Keys containing dots cannot be told apart from nested keys, and an empty root key cannot
+// be told apart from a scalar document, so Flatten fails for documents with dotted or empty
+// keys instead of silently merging their values.
+func (d Document) Flatten() (map[string]any, error) {
+	out := map[string]any{}
+	if d.V == nil {
+		return out, nil
+	}
+	if err := flatten(out, "", d.V); err != nil {
+		return nil, fmt.Errorf("jsonsql.Document.Flatten: %w", err)
+	}
+	return out, nil
+}
+
+// flatten adds the leaves of v to out, prefixing their keys with prefix.
+func flatten(out map[string]any, prefix string, v any) error {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			out[prefix] = map[string]any{}
+			return nil
+		}
+		for k, child := range v {
+			if k == "" || strings.Contains(k, ".") {
+				problem := "contains a dot"
+				if k == "" {
+					problem = "is empty"
+				}
+				if prefix == "" {
+					return fmt.Errorf("key %q %s", k, problem)
+				}
+				return fmt.Errorf("%s: key %q %s", prefix, k, problem)
+			}
+			if err := flatten(out, join(k), child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if len(v) == 0 {
+			out[prefix] = []any{}
+			return nil
+		}
+		for i, child := range v {
+			if err := flatten(out, join(strconv.Itoa(i)), child); err != nil {
+				return err
+			}
+		}
+	default:
+		out[prefix] = v
+	}
+	return nil
+}
+
+// Unflatten is the inverse of Flatten: it builds a Document from values keyed by dotted paths.
+// Objects whose keys are exactly 0, 1, ..., n-1 become arrays. Values may be any value
+// encodable as JSON and are stored like NewDocument. It fails if a key is both a leaf and
+// the prefix of another key, such as "a" and "a.b".
+func Unflatten(flat map[string]any) (Document, error) {
+	if v, ok := flat[""]; ok {
+		if len(flat) > 1 {
+			return Document{}, fmt.Errorf("jsonsql.Unflatten: the empty key cannot be combined with other keys")
+		}
+		return NewDocument(v)
+	}
+
+	root := map[string]any{}
+	for _, key := range slices.Sorted(maps.Keys(flat)) {
+		value, err := NewDocument(flat[key])
+		if err != nil {
+			return Document{}, fmt.Errorf("jsonsql.Unflatten: %s: %w", key, err)
+		}
+		node := root
+		parts := strings.Split(key, ".")
+		for i, part := range parts[:len(parts)-1] {
+			child, ok := node[part]
+			if !ok {
+				child = map[string]any{}
+				node[part] = child
+			}
+			next, ok := child.(map[string]any)
+			if !ok {
+				return Document{}, fmt.Errorf("jsonsql.Unflatten: %s: %s is a leaf", key, strings.Join(parts[:i+1], "."))
+			}
+			node = next
+		}
+		last := parts[len(parts)-1]
+		if _, ok := node[last]; ok {
+			return Document{}, fmt.Errorf("jsonsql.Unflatten: %s: key has nested keys", key)
+		}
+		node[last] = value.V
+	}
+	return Document{V: arraysFromIndexes(root)}, nil
+}
+
+// arraysFromIndexes converts the objects built by Unflatten whose keys are 0..n-1 into arrays.
+func arraysFromIndexes(v any) any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, child := range obj {
+		obj[k] = arraysFromIndexes(child)
+	}
+	if len(obj) == 0 {
+		return obj
+	}
+	arr := make([]any, len(obj))
+	for k, child := range obj {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(arr) || strconv.Itoa(i) != k {
+			return obj
+		}
+		arr[i] = child
+	}
+	return arr
+}
@@ -0,0 +1,130 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Flatten(t *testing.T) {
+	var d Document
+	if err := d.Scan(`{"id":1,"address":{"city":"Oslo","geo":{"lat":59.9}},"tags":["a","b"],"meta":{},"list":[],"none":null}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := map[string]any{
+		"id":              json.Number("1"),
+		"address.city":    "Oslo",
+		"address.geo.lat": json.Number("59.9"),
+		"tags.0":          "a",
+		"tags.1":          "b",
+		"meta":            map[string]any{},
+		"list":            []any{},
+		"none":            nil,
+	}
+	flat, err := d.Flatten()
+	if err != nil {
+		t.Fatalf("Flatten failed: %v", err)
+	}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Fatalf("Flatten() = %#v, want %#v", flat, expected)
+	}
+
+	back, err := Unflatten(flat)
+	if err != nil {
+		t.Fatalf("Unflatten failed: %v", err)
+	}
+	if !reflect.DeepEqual(back.V, d.V) {
+		t.Errorf("Unflatten(Flatten()) = %#v, want %#v", back.V, d.V)
+	}
+}
+
+func TestDocument_FlattenScalar(t *testing.T) {
+	d := Document{V: "x"}
+	flat, err := d.Flatten()
+	if err != nil || !reflect.DeepEqual(flat, map[string]any{"": "x"}) {
+		t.Fatalf("unexpected %#v, %v", flat, err)
+	}
+	back, err := Unflatten(flat)
+	if err != nil || back.V != "x" {
+		t.Errorf("Unflatten = %#v, %v", back.V, err)
+	}
+}
+
+func TestDocument_FlattenNull(t *testing.T) {
+	var d Document
+	flat, err := d.Flatten()
+	if err != nil || flat == nil || len(flat) != 0 {
+		t.Errorf("expected empty map, got %#v, %v", flat, err)
+	}
+}
+
+func TestDocument_FlattenDottedKey(t *testing.T) {
+	var d Document
+	if err := d.Scan(`{"a.b":1,"a":{"b":2}}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if flat, err := d.Flatten(); err == nil || !strings.Contains(err.Error(), `"a.b"`) {
+		t.Errorf("expected error for dotted key, got %#v, %v", flat, err)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	d, err := Unflatten(map[string]any{
+		"user.name":  "Ann",
+		"user.age":   30,
+		"rows.0.v":   true,
+		"rows.1.v":   false,
+		"sparse.0":   "a",
+		"sparse.2":   "c",
+		"leading.01": "x",
+	})
+	if err != nil {
+		t.Fatalf("Unflatten failed: %v", err)
+	}
+	result, err := d.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"leading":{"01":"x"},"rows":[{"v":true},{"v":false}],"sparse":{"0":"a","2":"c"},"user":{"age":30,"name":"Ann"}}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	for _, flat := range []map[string]any{
+		{"a": 1, "a.b": 2},
+		{"": 1, "a": 2},
+		{"a": make(chan int)},
+	} {
+		if _, err := Unflatten(flat); err == nil || !strings.HasPrefix(err.Error(), "jsonsql.Unflatten:") {
+			t.Errorf("Unflatten(%v): expected error, got %v", flat, err)
+		}
+	}
+}
+
+func TestDocument_FlattenEmptyKey(t *testing.T) {
+	for _, doc := range []string{`{"":1}`, `{"a":{"":1}}`} {
+		var d Document
+		if err := d.Scan(doc); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if flat, err := d.Flatten(); err == nil || !strings.Contains(err.Error(), "empty") {
+			t.Errorf("%s: expected error for empty key, got %#v, %v", doc, flat, err)
+		}
+	}
+
+	// Documents without empty or dotted keys round-trip.
+	var d Document
+	if err := d.Scan(`{"a":{"b":[1,{"c":""}]},"d":""}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	flat, err := d.Flatten()
+	if err != nil {
+		t.Fatalf("Flatten failed: %v", err)
+	}
+	back, err := Unflatten(flat)
+	if err != nil || !reflect.DeepEqual(back.V, d.V) {
+		t.Errorf("Unflatten(Flatten()) = %#v, %v, want %#v", back.V, err, d.V)
+	}
+}
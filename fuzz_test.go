@@ -0,0 +1,155 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var fuzzSeeds = []string{
+	`{"name":"Ann","email":"ann@example.com"}`,
+	`[1,2.5,-3e10,"x",true,false,null,{}]`,
+	`{"a":{"b":[{"c":"é😀"}]}}`,
+	`"plain string"`,
+	`12345678901234567890`,
+	`null`,
+	` `,
+	`{"a":1} trailing`,
+}
+
+func FuzzValue_RoundTrip(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v Nullable[any]
+		if err := v.Scan(data); err != nil || !v.Valid {
+			return
+		}
+		result, err := v.Value()
+		if err != nil {
+			t.Fatalf("Value failed after successful Scan of %q: %v", data, err)
+		}
+		var again Nullable[any]
+		if err := again.Scan(result); err != nil {
+			t.Fatalf("Scan failed on %q written by Value: %v", result, err)
+		}
+		if !reflect.DeepEqual(v.V, again.V) {
+			t.Fatalf("round trip changed %#v to %#v", v.V, again.V)
+		}
+	})
+}
+
+func FuzzTransformKeys(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Add([]byte(`{"user_id":1,"HTTPServer":{"a-b":"c:d"}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, style := range []KeyStyle{SnakeCase, CamelCase} {
+			out := transformKeys(data, style)
+			if json.Valid(data) && !json.Valid(out) {
+				t.Fatalf("transformKeys(%q, %d) produced invalid JSON %q", data, style, out)
+			}
+		}
+	})
+}
+
+func FuzzStandardize(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Add([]byte("{/* comment */ \"a\": [1, 2,], // line\n}"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		out, err := standardize(data)
+		if err == nil && json.Valid(data) && !json.Valid(out) {
+			t.Fatalf("standardize(%q) broke valid JSON: %q", data, out)
+		}
+	})
+}
+
+func FuzzParseArrayLiteral(f *testing.F) {
+	f.Add([]byte(`{"{\"a\":1}",NULL,"[1,2]"}`))
+	f.Add([]byte(`[0:1]={1,2}`))
+	f.Add([]byte(`{}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parseArrayLiteral(data)
+	})
+}
+
+func FuzzBinaryFormats(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, format := range []*binaryFormat{cborFormat, msgpackFormat} {
+			// Arbitrary input must not panic.
+			_, _ = format.toJSON(data)
+
+			if !json.Valid(data) {
+				continue
+			}
+			encoded, err := format.fromJSON(data)
+			if err != nil {
+				continue
+			}
+			decoded, err := format.toJSON(encoded)
+			if err != nil {
+				t.Fatalf("%s: decoding %q encoded from %q failed: %v", format.name, encoded, data, err)
+			}
+			if format == msgpackFormat && hasWideInteger(data) {
+				// MessagePack stores integers beyond 64 bits as strings.
+				continue
+			}
+			var want, got any
+			_ = json.Unmarshal(data, &want)
+			if err := json.Unmarshal(decoded, &got); err != nil {
+				t.Fatalf("%s: invalid JSON %q: %v", format.name, decoded, err)
+			}
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("%s: round trip changed %s to %s", format.name, data, decoded)
+			}
+		}
+	})
+}
+
+// hasWideInteger reports whether the JSON document data contains an integer that fits
+// neither int64 nor uint64.
+func hasWideInteger(data []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return false
+	}
+	var wide func(v any) bool
+	wide = func(v any) bool {
+		switch v := v.(type) {
+		case json.Number:
+			s := string(v)
+			if strings.ContainsAny(s, ".eE") {
+				return false
+			}
+			_, ierr := strconv.ParseInt(s, 10, 64)
+			_, uerr := strconv.ParseUint(s, 10, 64)
+			return ierr != nil && uerr != nil
+		case []any:
+			for _, elem := range v {
+				if wide(elem) {
+					return true
+				}
+			}
+		case map[string]any:
+			for _, elem := range v {
+				if wide(elem) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return wide(v)
+}
@@ -1,3 +1,3 @@
 module github.com/jinford/jsonsql
 
-go 1.24.4
+go 1.25.0
@@ -0,0 +1,79 @@
+package jsonsql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeParamError is returned by Scan and Value when the type parameter of a wrapper cannot work,
+// so the problem is reported on first use with the offending type instead of deep inside
+// encoding/json or as confusing behavior later.
+type TypeParamError struct {
+	// Type is the type parameter.
+	Type reflect.Type
+	// Reason describes the problem.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *TypeParamError) Error() string {
+	return fmt.Sprintf("jsonsql: invalid type parameter %v: %s", e.Type, e.Reason)
+}
+
+// checkTypeParam reports type parameters that cannot be encoded or decoded: types that contain
+// channels, functions, complex numbers or unsafe pointers in a JSON-visible position without
+// a hook or JSON methods of their own, and struct fields tagged with a field hook that is not
+// registered or does not apply to their type. It runs once per type when cfg is resolved.
+func checkTypeParam(t reflect.Type, cfg *config) error {
+	if path, kind, ok := unsupportedKind(t, cfg, map[reflect.Type]bool{}); ok {
+		reason := fmt.Sprintf("%v values cannot be encoded as JSON", kind)
+		if path != "" {
+			reason = fmt.Sprintf("%s at %s", reason, path)
+		}
+		return &TypeParamError{Type: t, Reason: reason}
+	}
+	if reason, ok := badFieldHook(t, cfg, map[reflect.Type]bool{}); ok {
+		return &TypeParamError{Type: t, Reason: reason}
+	}
+	return nil
+}
+
+// unsupportedKind finds a value of unsupported kind inside t and returns its location as a path
+// of JSON field names, with [] for slice elements and [key] for map values, such as
+// ".items[].on_done" for a func field tagged on_done inside the elements of a field tagged items.
+func unsupportedKind(t reflect.Type, cfg *config, seen map[reflect.Type]bool) (string, reflect.Kind, bool) {
+	if _, ok := cfg.hooks[t]; ok || implementsJSON(t) || seen[t] {
+		return "", 0, false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return "", t.Kind(), true
+	case reflect.Pointer:
+		return unsupportedKind(t.Elem(), cfg, seen)
+	case reflect.Slice, reflect.Array:
+		path, kind, ok := unsupportedKind(t.Elem(), cfg, seen)
+		return "[]" + path, kind, ok
+	case reflect.Map:
+		path, kind, ok := unsupportedKind(t.Elem(), cfg, seen)
+		return "[key]" + path, kind, ok
+	case reflect.Struct:
+		for _, f := range typeFields(t) {
+			if path, kind, ok := unsupportedKind(f.typ, cfg, seen); ok {
+				return "." + f.name + path, kind, ok
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// checkNullableParam rejects pointer type parameters for Nullable[T]: a nil pointer and
+// Valid=false would both mean NULL, and Valid=true with a nil pointer writes JSON null.
+func checkNullableParam[T any]() error {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		return &TypeParamError{Type: t, Reason: fmt.Sprintf("pointer types are nullable twice; use Nullable[%v]", t.Elem())}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testCallbacks struct {
+	Name  string `json:"name"`
+	Items []struct {
+		OnDone func() `json:"on_done"`
+	} `json:"items"`
+	Ignored chan int `json:"-"`
+}
+
+func TestTypeParam_Unsupported(t *testing.T) {
+	var typeErr *TypeParamError
+
+	var v Value[testCallbacks]
+	err := v.Scan(`{"name":"a"}`)
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected TypeParamError, got %v", err)
+	}
+	if !strings.HasSuffix(err.Error(), "func values cannot be encoded as JSON at .items[].on_done") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := NewValue(testCallbacks{}).Value(); !errors.As(err, &typeErr) {
+		t.Errorf("expected TypeParamError, got %v", err)
+	}
+
+	// Hidden fields do not matter.
+	var ok Value[struct {
+		Name string   `json:"name"`
+		Ch   chan int `json:"-"`
+	}]
+	if err := ok.Scan(`{"name":"a"}`); err != nil {
+		t.Errorf("Scan failed: %v", err)
+	}
+}
+
+func TestTypeParam_NullablePointer(t *testing.T) {
+	var typeErr *TypeParamError
+
+	var n Nullable[*testProfile]
+	if err := n.Scan(`{"name":"a"}`); !errors.As(err, &typeErr) {
+		t.Errorf("expected TypeParamError, got %v", err)
+	}
+	if _, err := (Nullable[*testProfile]{}).Value(); !errors.As(err, &typeErr) {
+		t.Errorf("expected TypeParamError, got %v", err)
+	}
+}
+
+func TestNullable_RawMessageCopied(t *testing.T) {
+	src := []byte(`{"a":1}`)
+	var n Nullable[json.RawMessage]
+	if err := n.Scan(src); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	// Drivers reuse their buffers after Scan returns.
+	copy(src, `{"b":2}`)
+	if string(n.V) != `{"a":1}` {
+		t.Errorf("expected scanned bytes to be copied, got %s", n.V)
+	}
+}
@@ -0,0 +1,41 @@
+package jsonsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// ScanJSON decodes a database source value into a T using the same rules and options as Value[T].Scan.
+// It is intended for custom sql.Scanner implementations that need the wrapper behavior ad hoc.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null".
+func ScanJSON[T any](src any) (T, error) {
+	var v T
+	null, err := decodeSource(src, &v, ErrorOnEmpty)
+	if err != nil {
+		return v, fmt.Errorf("jsonsql.ScanJSON: %w", err)
+	}
+	if null {
+		return v, ErrNullNotAllowed
+	}
+	return v, nil
+}
+
+// ValueJSON encodes v for database storage using the same rules and options as Value[T].Value.
+func ValueJSON[T any](v T) (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := encodeValue(v, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.ValueJSON: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+// EncodeJSON is like ValueJSON but returns the document as []byte regardless of
+// ValueAsRawMessage, for adapters handing documents to APIs other than database/sql.
+func EncodeJSON[T any](v T) ([]byte, error) {
+	data, err := encodeValue(v, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.EncodeJSON: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,90 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestScanJSON(t *testing.T) {
+	profile, err := ScanJSON[testProfile]([]byte(`{"name":"Alice","email":"alice@example.com"}`))
+	if err != nil {
+		t.Fatalf("ScanJSON failed: %v", err)
+	}
+
+	if profile.Name != "Alice" || profile.Email != "alice@example.com" {
+		t.Errorf("unexpected result: %+v", profile)
+	}
+}
+
+func TestScanJSON_Null(t *testing.T) {
+	for _, input := range []any{nil, "null", []byte(" null ")} {
+		if _, err := ScanJSON[testProfile](input); !errors.Is(err, ErrNullNotAllowed) {
+			t.Errorf("ScanJSON(%v): expected ErrNullNotAllowed, got %v", input, err)
+		}
+	}
+}
+
+func TestScanJSON_Errors(t *testing.T) {
+	if _, err := ScanJSON[testProfile](123); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+	if _, err := ScanJSON[testProfile](`{invalid}`); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := ScanJSON[testProfile](""); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+}
+
+func TestScanJSON_UsesOptions(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](EmptyInput(ZeroOnEmpty))
+
+	profile, err := ScanJSON[testProfile]("")
+	if err != nil {
+		t.Fatalf("ScanJSON failed: %v", err)
+	}
+	if profile != (testProfile{}) {
+		t.Errorf("expected zero value, got %+v", profile)
+	}
+}
+
+func TestValueJSON(t *testing.T) {
+	result, err := ValueJSON(testProfile{Name: "Bob", Email: "bob@example.com"})
+	if err != nil {
+		t.Fatalf("ValueJSON failed: %v", err)
+	}
+
+	expected, _ := NewValue(testProfile{Name: "Bob", Email: "bob@example.com"}).Value()
+	if string(result.([]byte)) != string(expected.([]byte)) {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestValueJSON_MarshalError(t *testing.T) {
+	if _, err := ValueJSON(unmarshalableType{Ch: make(chan int)}); err == nil {
+		t.Fatal("expected error for unmarshalable type")
+	}
+}
+
+func TestEncodeJSON_RawMessage(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](ValueAsRawMessage(true))
+
+	v := testProfile{Name: "Bob", Email: "bob@example.com"}
+	result, err := ValueJSON(v)
+	if err != nil {
+		t.Fatalf("ValueJSON failed: %v", err)
+	}
+	if _, ok := result.(json.RawMessage); !ok {
+		t.Fatalf("expected json.RawMessage from ValueJSON, got %T", result)
+	}
+	data, err := EncodeJSON(v)
+	if err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+	if string(data) != string(result.(json.RawMessage)) {
+		t.Errorf("expected %s, got %s", result, data)
+	}
+}
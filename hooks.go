@@ -0,0 +1,86 @@
+package jsonsql
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Operations reported in HookEvent.Op.
+const (
+	OpScan  = "scan"
+	OpValue = "value"
+)
+
+// HookEvent describes one Scan or Value of a wrapped value.
+type HookEvent struct {
+	// Context is the context passed to ScanContext or ValueContext, or context.Background().
+	Context context.Context
+	// Op is OpScan or OpValue.
+	Op string
+	// Type is the type parameter of the wrapper, e.g. "main.Settings", in both directions.
+	Type string
+	// Size is the payload size in bytes: the scanned data or the encoded document.
+	// It is 0 for SQL NULL and for sources that are not raw bytes or strings.
+	Size int
+	// Duration is the time spent decoding or encoding.
+	Duration time.Duration
+	// Err is the error returned, if any.
+	Err error
+}
+
+// Hooks are observability callbacks invoked for every Scan and Value of the wrappers,
+// for example to update Prometheus counters or log decode failures:
+//
+//	jsonsql.SetDefaults(jsonsql.Observe(jsonsql.Hooks{
+//		OnError: func(e jsonsql.HookEvent) { slog.Warn("jsonsql", "op", e.Op, "type", e.Type, "err", e.Err) },
+//	}))
+//
+// Nil callbacks are skipped. Callbacks run synchronously and must be safe for concurrent use.
+type Hooks struct {
+	// OnScan is called after every Scan, including failed ones.
+	OnScan func(HookEvent)
+	// OnValue is called after every Value, including failed ones.
+	OnValue func(HookEvent)
+	// OnError is called after every failed Scan or Value, in addition to OnScan or OnValue.
+	OnError func(HookEvent)
+}
+
+// Observe sets the observability hooks. Like every option it can be set globally with SetDefaults
+// or per type with Configure; per-type hooks replace the global ones.
+func Observe(h Hooks) Option {
+	return func(c *config) {
+		c.observer = h
+	}
+}
+
+// active reports whether any callback is set.
+func (h Hooks) active() bool {
+	return h.OnScan != nil || h.OnValue != nil || h.OnError != nil
+}
+
+// report calls the callbacks for e.
+func (h Hooks) report(e HookEvent) {
+	switch {
+	case e.Op == OpScan && h.OnScan != nil:
+		h.OnScan(e)
+	case e.Op == OpValue && h.OnValue != nil:
+		h.OnValue(e)
+	}
+	if e.Err != nil && h.OnError != nil {
+		h.OnError(e)
+	}
+}
+
+// payloadSize returns the size of a resolved source value holding raw data.
+func payloadSize(src any) int {
+	switch s := src.(type) {
+	case []byte:
+		return len(s)
+	case string:
+		return len(s)
+	case json.RawMessage:
+		return len(s)
+	}
+	return 0
+}
@@ -0,0 +1,152 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestObserve(t *testing.T) {
+	resetOptions[testProfile](t)
+
+	var (
+		mu     sync.Mutex
+		events []HookEvent
+		errs   []HookEvent
+	)
+	record := func(e HookEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}
+	Configure[testProfile](Observe(Hooks{
+		OnScan:  record,
+		OnValue: record,
+		OnError: func(e HookEvent) { errs = append(errs, e) },
+	}))
+
+	var v Value[testProfile]
+	if err := v.Scan(`{"name":"Ann"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, err := v.Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if err := v.Scan(`{"name":`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if e := events[0]; e.Op != OpScan || e.Type != "jsonsql.testProfile" || e.Size != 14 || e.Err != nil {
+		t.Errorf("unexpected scan event: %+v", e)
+	}
+	if e := events[1]; e.Op != OpValue || e.Type != "jsonsql.testProfile" || e.Size != 25 || e.Err != nil {
+		t.Errorf("unexpected value event: %+v", e)
+	}
+	if len(errs) != 1 || errs[0].Op != OpScan || errs[0].Err == nil {
+		t.Errorf("unexpected error events: %+v", errs)
+	}
+}
+
+func TestObserve_NullValue(t *testing.T) {
+	resetOptions[any](t)
+
+	var events []HookEvent
+	record := func(e HookEvent) { events = append(events, e) }
+	Configure[any](Observe(Hooks{OnScan: record, OnValue: record}))
+
+	var n Nullable[any]
+	if err := n.Scan(nil); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, err := n.Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if _, err := NullableFrom[any](testProfile{Name: "Ann"}).Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+	if e := events[1]; e.Op != OpValue || e.Size != 0 || e.Err != nil {
+		t.Errorf("unexpected NULL value event: %+v", e)
+	}
+	for _, e := range events {
+		if e.Type != "interface {}" {
+			t.Errorf("expected the type parameter in every event, got %+v", e)
+		}
+	}
+}
+
+type testFailingValuer struct{}
+
+func (testFailingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("valuer failed")
+}
+
+func TestObserve_EarlyErrors(t *testing.T) {
+	resetOptions[testProfile](t)
+	resetOptions[chan int](t)
+
+	var errs []error
+	hooks := Observe(Hooks{OnError: func(e HookEvent) { errs = append(errs, e.Err) }})
+	Configure[testProfile](hooks)
+	Configure[chan int](hooks)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var v Value[testProfile]
+	if err := v.ScanContext(ctx, `{}`); err == nil {
+		t.Fatal("expected error for canceled context")
+	}
+	if err := v.Scan(testFailingValuer{}); err == nil {
+		t.Fatal("expected error for failing driver.Valuer")
+	}
+	var c Value[chan int]
+	if err := c.Scan(`{}`); err == nil {
+		t.Fatal("expected error for unsupported type parameter")
+	}
+	if _, err := c.Value(); err == nil {
+		t.Fatal("expected error for unsupported type parameter")
+	}
+
+	if len(errs) != 4 || !errors.Is(errs[0], context.Canceled) {
+		t.Fatalf("expected 4 reported errors starting with context.Canceled, got %v", errs)
+	}
+	var typeErr *TypeParamError
+	if !errors.As(errs[2], &typeErr) || !errors.As(errs[3], &typeErr) {
+		t.Errorf("expected TypeParamError reports, got %v", errs[2:])
+	}
+}
+
+func TestObserve_ValueContext(t *testing.T) {
+	resetOptions[testProfile](t)
+
+	var tenants []any
+	var errs []error
+	Configure[testProfile](SignWith(HMACSigner([]byte("secret"))), Observe(Hooks{
+		OnValue: func(e HookEvent) { tenants = append(tenants, e.Context.Value(testTenantKey{})) },
+		OnError: func(e HookEvent) { errs = append(errs, e.Err) },
+	}))
+
+	ctx := context.WithValue(context.Background(), testTenantKey{}, "acme")
+	if _, err := ValuerWithContext(ctx, NewSigned(testProfile{Name: "Ann"})).Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := ValuerWithContext(canceled, NewSigned(testProfile{Name: "Ann"})).Value(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "acme" {
+		t.Errorf("expected the ValueContext context to be reported, got %v", tenants)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected the context error to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,98 @@
+package jsonsql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Immutable[struct{}])(nil)
+	_ ContextScanner = (*Immutable[struct{}])(nil)
+	_ driver.Valuer  = Immutable[struct{}]{}
+)
+
+// ErrImmutableModified is returned by Immutable.Value when V differs from the scanned document.
+var ErrImmutableModified = permanent("jsonsql: immutable document was modified")
+
+// Immutable[T] is a generic type for NOT NULL JSON columns holding write-once documents,
+// such as signed webhook payloads or issued invoices. Scan records a SHA-256 hash of the
+// scanned contents, and Value fails with ErrImmutableModified if V no longer encodes to
+// the same document, so accidental mutations are caught before they reach the database.
+//
+// A value that was never scanned, such as one created with NewImmutable, is written normally.
+// The hash is taken over the re-encoded V rather than the raw column bytes, so formatting
+// applied by the database (such as jsonb key ordering) does not count as a modification.
+type Immutable[T any] struct {
+	V T
+
+	hash    [sha256.Size]byte
+	scanned bool
+}
+
+// NewImmutable creates a new Immutable[T] with the given value for its first write.
+func NewImmutable[T any](v T) Immutable[T] {
+	return Immutable[T]{V: v}
+}
+
+// Get returns the value.
+func (m Immutable[T]) Get() T {
+	return m.V
+}
+
+// Modified reports whether V differs from the scanned document.
+// It returns false for values that were never scanned.
+func (m Immutable[T]) Modified() (bool, error) {
+	if !m.scanned {
+		return false, nil
+	}
+	data, err := encodeDocument(m.V, configFor[T]())
+	if err != nil {
+		return false, err
+	}
+	return sha256.Sum256(data) != m.hash, nil
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals JSON data from the database into V and records its hash.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (m *Immutable[T]) Scan(src any) error {
+	return m.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (m *Immutable[T]) ScanContext(ctx context.Context, src any) error {
+	m.scanned = false
+	null, err := decodeSourceContext(ctx, src, &m.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Immutable.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	data, err := encodeDocument(m.V, configFor[T]())
+	if err != nil {
+		return fmt.Errorf("jsonsql.Immutable.Scan: %w", err)
+	}
+	m.hash, m.scanned = sha256.Sum256(data), true
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V to JSON bytes for database storage.
+// Returns ErrImmutableModified if V was scanned and has changed since.
+func (m Immutable[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := encodeValue(m.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Immutable.Value: %w", err)
+	}
+	if m.scanned && sha256.Sum256(data) != m.hash {
+		return nil, fmt.Errorf("jsonsql.Immutable.Value: %w", ErrImmutableModified)
+	}
+	return cfg.driverValue(data), nil
+}
@@ -0,0 +1,49 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestImmutable_Unchanged(t *testing.T) {
+	var m Immutable[testProfile]
+	if err := m.Scan(`{"email": "ann@example.com", "name": "Ann"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	result, err := m.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"name":"Ann","email":"ann@example.com"}` {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestImmutable_Modified(t *testing.T) {
+	var m Immutable[testProfile]
+	if err := m.Scan(`{"name":"Ann","email":"ann@example.com"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	m.V.Email = "other@example.com"
+
+	if modified, err := m.Modified(); err != nil || !modified {
+		t.Errorf("expected modified, got %v, %v", modified, err)
+	}
+	if _, err := m.Value(); !errors.Is(err, ErrImmutableModified) {
+		t.Errorf("expected ErrImmutableModified, got %v", err)
+	}
+}
+
+func TestImmutable_FirstWrite(t *testing.T) {
+	m := NewImmutable(testProfile{Name: "Ann"})
+	if _, err := m.Value(); err != nil {
+		t.Errorf("Value failed: %v", err)
+	}
+}
+
+func TestImmutable_Null(t *testing.T) {
+	var m Immutable[testProfile]
+	if err := m.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
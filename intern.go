@@ -0,0 +1,253 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// InternKeys makes Scan share the strings of object keys decoded into untyped values
+// (map[string]any, []any and any), wherever they appear inside a document. Result sets of
+// thousands of similar documents then hold one copy of each key instead of one per row,
+// and decoding allocates less:
+//
+//	jsonsql.Configure[map[string]any](jsonsql.InternKeys(10000))
+//
+// At most maxKeys distinct keys are interned; further keys are allocated as usual, so
+// documents with unbounded key sets cannot grow the table without limit. The table is
+// shared by all types configured with the same option value and is safe for concurrent use.
+// maxKeys <= 0 disables interning. Untyped values are decoded by the reflective walker
+// instead of encoding/json, with the same results, including UseNumber.
+func InternKeys(maxKeys int) Option {
+	if maxKeys <= 0 {
+		return func(*config) {}
+	}
+	table := &internTable{max: maxKeys, keys: map[string]string{}}
+	return func(c *config) {
+		for _, t := range []reflect.Type{
+			reflect.TypeFor[any](),
+			reflect.TypeFor[map[string]any](),
+			reflect.TypeFor[[]any](),
+		} {
+			c.setHook(t, typeHook{
+				encode: func(rv reflect.Value) ([]byte, error) {
+					return encodeJSON(rv.Interface(), c)
+				},
+				decode: func(data []byte, rv reflect.Value) error {
+					return decodeInterned(data, rv, table, c.useNumber)
+				},
+			})
+		}
+	}
+}
+
+// internTable is a bounded set of shared key strings.
+type internTable struct {
+	mu   sync.RWMutex
+	max  int
+	keys map[string]string
+}
+
+// intern returns a string equal to b, shared with earlier calls while the table has room.
+func (t *internTable) intern(b []byte) string {
+	// Map lookups with string(b) do not allocate.
+	t.mu.RLock()
+	s, ok := t.keys[string(b)]
+	t.mu.RUnlock()
+	if ok {
+		return s
+	}
+	s = string(b)
+	t.mu.Lock()
+	if len(t.keys) < t.max {
+		t.keys[s] = s
+	}
+	t.mu.Unlock()
+	return s
+}
+
+// decodeInterned decodes the JSON value data into rv, an untyped value, interning object keys.
+func decodeInterned(data []byte, rv reflect.Value, table *internTable, useNumber bool) error {
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) {
+		// Let encoding/json report the syntax error.
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+	d := &internDecoder{data: data, table: table, useNumber: useNumber}
+	v, err := d.value()
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		rv.SetZero()
+		return nil
+	}
+	val := reflect.ValueOf(v)
+	if !val.Type().AssignableTo(rv.Type()) {
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: rv.Type()}
+	}
+	rv.Set(val)
+	return nil
+}
+
+// internDecoder decodes well-formed JSON into untyped values like encoding/json.
+type internDecoder struct {
+	data      []byte
+	pos       int
+	table     *internTable
+	useNumber bool
+}
+
+var errInternSyntax = errors.New("jsonsql: unexpected JSON syntax")
+
+func (d *internDecoder) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\r', '\n':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+// value decodes the value at the current position.
+func (d *internDecoder) value() (any, error) {
+	d.skipSpace()
+	if d.pos >= len(d.data) {
+		return nil, errInternSyntax
+	}
+	switch c := d.data[d.pos]; {
+	case c == '{':
+		return d.object()
+	case c == '[':
+		return d.array()
+	case c == '"':
+		raw, err := d.stringBytes()
+		if err != nil {
+			return nil, err
+		}
+		return d.str(raw, false)
+	case c == 't':
+		d.pos += len("true")
+		return true, nil
+	case c == 'f':
+		d.pos += len("false")
+		return false, nil
+	case c == 'n':
+		d.pos += len("null")
+		return nil, nil
+	default:
+		return d.number()
+	}
+}
+
+func (d *internDecoder) object() (any, error) {
+	d.pos++ // {
+	m := map[string]any{}
+	for {
+		d.skipSpace()
+		if d.pos < len(d.data) && d.data[d.pos] == '}' {
+			d.pos++
+			return m, nil
+		}
+		raw, err := d.stringBytes()
+		if err != nil {
+			return nil, err
+		}
+		key, err := d.str(raw, true)
+		if err != nil {
+			return nil, err
+		}
+		d.skipSpace()
+		d.pos++ // :
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key.(string)] = v
+		d.skipSpace()
+		if d.pos < len(d.data) && d.data[d.pos] == ',' {
+			d.pos++
+		}
+	}
+}
+
+func (d *internDecoder) array() (any, error) {
+	d.pos++ // [
+	a := []any{}
+	for {
+		d.skipSpace()
+		if d.pos < len(d.data) && d.data[d.pos] == ']' {
+			d.pos++
+			return a, nil
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+		d.skipSpace()
+		if d.pos < len(d.data) && d.data[d.pos] == ',' {
+			d.pos++
+		}
+	}
+}
+
+// stringBytes returns the quoted string at the current position, quotes included.
+func (d *internDecoder) stringBytes() ([]byte, error) {
+	start := d.pos
+	if start >= len(d.data) || d.data[start] != '"' {
+		return nil, errInternSyntax
+	}
+	for d.pos++; d.pos < len(d.data); d.pos++ {
+		switch d.data[d.pos] {
+		case '\\':
+			d.pos++
+		case '"':
+			d.pos++
+			return d.data[start:d.pos], nil
+		}
+	}
+	return nil, errInternSyntax
+}
+
+// str converts the quoted string raw, interning it if key is set.
+func (d *internDecoder) str(raw []byte, key bool) (any, error) {
+	content := raw[1 : len(raw)-1]
+	if bytes.IndexByte(content, '\\') < 0 && utf8.Valid(content) {
+		if key {
+			return d.table.intern(content), nil
+		}
+		return string(content), nil
+	}
+	// Escapes and invalid UTF-8 are rare; leave them to encoding/json.
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	if key {
+		return d.table.intern([]byte(s)), nil
+	}
+	return s, nil
+}
+
+func (d *internDecoder) number() (any, error) {
+	start := d.pos
+	for d.pos < len(d.data) && !isDelimiter(d.data[d.pos]) {
+		d.pos++
+	}
+	text := string(d.data[start:d.pos])
+	if d.useNumber {
+		return json.Number(text), nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, &json.UnmarshalTypeError{Value: "number " + text, Type: reflect.TypeFor[float64](), Offset: int64(d.pos)}
+	}
+	return f, nil
+}
@@ -0,0 +1,115 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+type testRecord struct {
+	ID    int            `json:"id"`
+	Attrs map[string]any `json:"attrs"`
+	Extra any            `json:"extra"`
+}
+
+func TestInternKeys(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](InternKeys(100))
+
+	const doc = `{"name":"Alice","tags":["a",{"kéy":1.5}],"nested":{"name":null,"ok":true,"n":-2e3},"esc":"a\"b"}`
+	var expected map[string]any
+	if err := json.Unmarshal([]byte(doc), &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	var a, b Value[map[string]any]
+	if err := a.Scan(doc); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if err := b.Scan([]byte(doc)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !reflect.DeepEqual(a.V, expected) {
+		t.Errorf("expected %v, got %v", expected, a.V)
+	}
+
+	keyOf := func(m map[string]any, name string) string {
+		for k := range m {
+			if k == name {
+				return k
+			}
+		}
+		t.Fatalf("key %q not found", name)
+		return ""
+	}
+	ka, kb := keyOf(a.V, "name"), keyOf(b.V, "name")
+	if unsafe.StringData(ka) != unsafe.StringData(kb) {
+		t.Error("expected keys of different rows to share memory")
+	}
+
+	if err := a.Scan(`{"a":`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if err := a.Scan(`[1]`); !errors.As(err, &typeErr) {
+		t.Errorf("expected UnmarshalTypeError, got %v", err)
+	}
+
+	result, err := NewValue(map[string]any{"b": 1, "a": "<x>"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"a":"\u003cx\u003e","b":1}` {
+		t.Errorf("unexpected document %s", result)
+	}
+}
+
+func TestInternKeys_Nested(t *testing.T) {
+	resetOptions[testRecord](t)
+	Configure[testRecord](InternKeys(100), UseNumber(true))
+
+	var v Value[testRecord]
+	if err := v.Scan(`{"id":1,"attrs":{"big":9007199254740993},"extra":[{"x":1}]}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.ID != 1 || v.V.Attrs["big"] != json.Number("9007199254740993") {
+		t.Errorf("unexpected value %+v", v.V)
+	}
+	if extra, ok := v.V.Extra.([]any); !ok || !reflect.DeepEqual(extra[0], map[string]any{"x": json.Number("1")}) {
+		t.Errorf("unexpected extra %#v", v.V.Extra)
+	}
+}
+
+func TestInternTable_Bounded(t *testing.T) {
+	table := &internTable{max: 1, keys: map[string]string{}}
+	table.intern([]byte("a"))
+	table.intern([]byte("b"))
+	if len(table.keys) != 1 {
+		t.Errorf("expected 1 interned key, got %d", len(table.keys))
+	}
+}
+
+func BenchmarkInternKeys(b *testing.B) {
+	src := []byte(`{"id":1,"name":"Alice","email":"alice@example.com","tags":["a","b"],"address":{"city":"Tokyo","zip":"100"}}`)
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Default", nil},
+		{"Interned", []Option{InternKeys(1000)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			Configure[map[string]any](bm.opts...)
+			b.Cleanup(func() { Configure[map[string]any]() })
+			var v Value[map[string]any]
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := v.Scan(src); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ json.Marshaler   = Nullable[struct{}]{}
+	_ json.Unmarshaler = (*Nullable[struct{}])(nil)
+)
+
+// MarshalJSON implements json.Marshaler interface.
+// It returns JSON literal null when Valid is false and the JSON encoding of V otherwise,
+// so documents containing Nullable[T], such as []Nullable[T] for arrays whose elements
+// may be null, keep the distinction between null and the zero value.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	data, err := marshal(n.V, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Nullable.MarshalJSON: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// JSON literal null sets Valid=false; any other value is decoded into V with the options
+// registered for T and sets Valid=true.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	null, err := decodeSource(data, &n.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Nullable.UnmarshalJSON: %w", err)
+	}
+	if null {
+		n.setNull()
+		return nil
+	}
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,71 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullable_JSONArrayElements(t *testing.T) {
+	var v Value[[]Nullable[int]]
+	if err := v.Scan(`[1,null,0]`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := []Nullable[int]{NullableFrom(1), Null[int](), NullableFrom(0)}
+	if len(v.V) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, v.V)
+	}
+	for i := range expected {
+		if v.V[i] != expected[i] {
+			t.Errorf("element %d: expected %+v, got %+v", i, expected[i], v.V[i])
+		}
+	}
+
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `[1,null,0]` {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestNullable_MarshalJSON(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](Indent("  "))
+
+	data, err := json.Marshal(map[string]Nullable[testProfile]{
+		"a": NullableFrom(testProfile{Name: "Alice"}),
+		"b": Null[testProfile](),
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := `{"a":{"name":"Alice","email":""},"b":null}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestNullable_UnmarshalJSON(t *testing.T) {
+	var doc struct {
+		A Nullable[testProfile] `json:"a"`
+		B Nullable[testProfile] `json:"b"`
+	}
+	doc.B = NullableFrom(testProfile{Name: "stale"})
+
+	if err := json.Unmarshal([]byte(`{"a":{"name":"Alice"},"b":null}`), &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !doc.A.Valid || doc.A.V.Name != "Alice" {
+		t.Errorf("unexpected a: %+v", doc.A)
+	}
+	if doc.B.Valid || doc.B.V.Name != "" {
+		t.Errorf("expected b to be NULL, got %+v", doc.B)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":{"name":1}}`), &doc); err == nil {
+		t.Error("expected error for invalid element")
+	}
+}
@@ -0,0 +1,87 @@
+// Package jsonsqlaudit produces audit records for documents stored with jsonsql: who changed
+// a document, when, and the RFC 6902 JSON Patch from the old to the new version, ready to be
+// inserted into an audit table next to the write:
+//
+//	rec, err := jsonsqlaudit.New(actor, old.V, updated.V)
+//	if err != nil {
+//		return err
+//	}
+//	if rec.Changed() {
+//		_, err = tx.ExecContext(ctx,
+//			`INSERT INTO settings_audit (user_id, actor, at, patch) VALUES ($1, $2, $3, $4)`,
+//			id, rec.Actor, rec.At, rec.Patch)
+//	}
+package jsonsqlaudit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jinford/jsonsql"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ driver.Valuer = Record{}
+	_ driver.Valuer = Patch(nil)
+)
+
+// Patch is an RFC 6902 JSON Patch. Its Value method stores it as a JSON array.
+type Patch []Operation
+
+// Value implements driver.Valuer interface.
+func (p Patch) Value() (driver.Value, error) {
+	if p == nil {
+		p = Patch{}
+	}
+	return json.Marshal([]Operation(p))
+}
+
+// Record is an audit record of a document change.
+type Record struct {
+	// Actor identifies who made the change.
+	Actor string `json:"actor"`
+	// At is the time of the change.
+	At time.Time `json:"at"`
+	// Patch transforms the old document into the new one.
+	Patch Patch `json:"patch"`
+}
+
+// New returns an audit record of actor changing a document from old to updated at the current
+// time. Both versions are encoded with the options registered for T, so the patch refers to
+// the stored documents rather than to the Go values.
+func New[T any](actor string, old, updated T) (Record, error) {
+	from, err := encode(old)
+	if err != nil {
+		return Record{}, fmt.Errorf("jsonsqlaudit.New: old: %w", err)
+	}
+	to, err := encode(updated)
+	if err != nil {
+		return Record{}, fmt.Errorf("jsonsqlaudit.New: updated: %w", err)
+	}
+	patch, err := Diff(from, to)
+	if err != nil {
+		return Record{}, fmt.Errorf("jsonsqlaudit.New: %w", err)
+	}
+	return Record{Actor: actor, At: time.Now().UTC(), Patch: patch}, nil
+}
+
+func encode[T any](v T) ([]byte, error) {
+	return jsonsql.EncodeJSON(v)
+}
+
+// Changed reports whether the record holds any change.
+func (r Record) Changed() bool {
+	return len(r.Patch) > 0
+}
+
+// Value implements driver.Valuer interface.
+// It stores the whole record as one JSON document, for audit tables with a single JSON column.
+func (r Record) Value() (driver.Value, error) {
+	if r.Patch == nil {
+		r.Patch = Patch{}
+	}
+	return json.Marshal(r)
+}
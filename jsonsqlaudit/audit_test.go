@@ -0,0 +1,75 @@
+package jsonsqlaudit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinford/jsonsql"
+)
+
+type testSettings struct {
+	Theme  string   `json:"theme"`
+	Tags   []string `json:"tags"`
+	Secret string   `json:"-"`
+}
+
+func TestNew(t *testing.T) {
+	old := testSettings{Theme: "light", Tags: []string{"a"}}
+	updated := testSettings{Theme: "dark", Tags: []string{"a", "b"}, Secret: "x"}
+
+	rec, err := New("alice", old, updated)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if rec.Actor != "alice" || time.Since(rec.At) > time.Minute || !rec.Changed() {
+		t.Errorf("unexpected record %+v", rec)
+	}
+
+	patch, err := rec.Patch.Value()
+	if err != nil {
+		t.Fatalf("Patch.Value failed: %v", err)
+	}
+	expected := `[{"op":"add","path":"/tags/-","value":"b"},{"op":"replace","path":"/theme","value":"dark"}]`
+	if string(patch.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, patch)
+	}
+
+	rec.At = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	doc, err := rec.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !strings.HasPrefix(string(doc.([]byte)), `{"actor":"alice","at":"2024-05-01T12:00:00Z","patch":[{"op":"add"`) {
+		t.Errorf("unexpected record document %s", doc)
+	}
+}
+
+func TestNew_Unchanged(t *testing.T) {
+	rec, err := New("bob", testSettings{Theme: "dark"}, testSettings{Theme: "dark", Secret: "ignored"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if rec.Changed() {
+		t.Errorf("expected no change, got %+v", rec.Patch)
+	}
+	patch, _ := rec.Patch.Value()
+	if string(patch.([]byte)) != `[]` {
+		t.Errorf("expected empty patch, got %s", patch)
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	type stored struct {
+		UserName string
+	}
+	jsonsql.Configure[stored](jsonsql.KeyCase(jsonsql.SnakeCase, jsonsql.KeysAsIs))
+
+	rec, err := New("carol", stored{UserName: "a"}, stored{UserName: "b"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if len(rec.Patch) != 1 || rec.Patch[0].Path != "/user_name" {
+		t.Errorf("expected patch on the stored key, got %+v", rec.Patch)
+	}
+}
@@ -0,0 +1,106 @@
+package jsonsqlaudit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Operation is an RFC 6902 JSON Patch operation. Diff produces only "add", "remove" and "replace".
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Diff returns the JSON Patch transforming the JSON document from into to. Objects are compared
+// key by key in sorted order, and arrays element by element, with elements added or removed at
+// the end. It returns an empty patch when the documents are equal, ignoring formatting and key order.
+func Diff(from, to []byte) ([]Operation, error) {
+	a, err := decode(from)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlaudit.Diff: from: %w", err)
+	}
+	b, err := decode(to)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlaudit.Diff: to: %w", err)
+	}
+	patch := []Operation{}
+	diff(&patch, "", a, b)
+	return patch, nil
+}
+
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func diff(patch *[]Operation, path string, a, b any) {
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			for _, k := range slices.Sorted(maps.Keys(a)) {
+				p := path + "/" + escapeToken(k)
+				if bv, ok := b[k]; ok {
+					diff(patch, p, a[k], bv)
+				} else {
+					*patch = append(*patch, Operation{Op: "remove", Path: p})
+				}
+			}
+			for _, k := range slices.Sorted(maps.Keys(b)) {
+				if _, ok := a[k]; !ok {
+					*patch = append(*patch, operation("add", path+"/"+escapeToken(k), b[k]))
+				}
+			}
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok {
+			n := min(len(a), len(b))
+			for i := range n {
+				diff(patch, path+"/"+strconv.Itoa(i), a[i], b[i])
+			}
+			// Remove from the end so that the indexes of the remaining elements stay valid.
+			for i := len(a) - 1; i >= n; i-- {
+				*patch = append(*patch, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+			}
+			for i := n; i < len(b); i++ {
+				*patch = append(*patch, operation("add", path+"/-", b[i]))
+			}
+			return
+		}
+	default:
+		if equalScalar(a, b) {
+			return
+		}
+	}
+	*patch = append(*patch, operation("replace", path, b))
+}
+
+// equalScalar reports whether the decoded JSON scalars a and b are equal.
+func equalScalar(a, b any) bool {
+	switch b.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return a == b
+}
+
+func operation(op, path string, v any) Operation {
+	data, _ := json.Marshal(v)
+	return Operation{Op: op, Path: path, Value: data}
+}
+
+// escapeToken escapes a JSON Pointer reference token as specified by RFC 6901.
+func escapeToken(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
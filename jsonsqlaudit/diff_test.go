@@ -0,0 +1,47 @@
+package jsonsqlaudit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{"equal", `{"a":1,"b":[1,2]}`, `{"b": [1, 2], "a": 1}`, `[]`},
+		{"replace scalar", `{"a":1}`, `{"a":"1"}`, `[{"op":"replace","path":"/a","value":"1"}]`},
+		{"add and remove", `{"a":1,"c":2}`, `{"b":null,"c":2}`,
+			`[{"op":"remove","path":"/a"},{"op":"add","path":"/b","value":null}]`},
+		{"nested", `{"a":{"b":{"c":true}}}`, `{"a":{"b":{"c":false}}}`,
+			`[{"op":"replace","path":"/a/b/c","value":false}]`},
+		{"escaped keys", `{"a/b":1,"m~n":1}`, `{"a/b":2,"m~n":2}`,
+			`[{"op":"replace","path":"/a~1b","value":2},{"op":"replace","path":"/m~0n","value":2}]`},
+		{"array grows", `[1,2]`, `[1,3,4,5]`,
+			`[{"op":"replace","path":"/1","value":3},{"op":"add","path":"/-","value":4},{"op":"add","path":"/-","value":5}]`},
+		{"array shrinks", `{"x":[1,2,3]}`, `{"x":[1]}`,
+			`[{"op":"remove","path":"/x/2"},{"op":"remove","path":"/x/1"}]`},
+		{"type change", `{"a":[1]}`, `{"a":{"0":1}}`, `[{"op":"replace","path":"/a","value":{"0":1}}]`},
+		{"root", `1`, `[1]`, `[{"op":"replace","path":"","value":[1]}]`},
+		{"large numbers", `{"n":12345678901234567890}`, `{"n":12345678901234567891}`,
+			`[{"op":"replace","path":"/n","value":12345678901234567891}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := Diff([]byte(tt.from), []byte(tt.to))
+			if err != nil {
+				t.Fatalf("Diff failed: %v", err)
+			}
+			data, _ := json.Marshal(patch)
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+
+	if _, err := Diff([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
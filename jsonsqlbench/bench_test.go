@@ -0,0 +1,132 @@
+package jsonsqlbench
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+// column is a wrapper type under benchmark.
+type column struct {
+	name  string
+	value func(o Order) driver.Valuer
+	scan  func() sql.Scanner
+}
+
+var columns = []column{
+	{
+		name:  "json",
+		value: func(o Order) driver.Valuer { return jsonsql.NewValue(o) },
+		scan:  func() sql.Scanner { return &jsonsql.Value[Order]{} },
+	},
+	{
+		name:  "json-nullable",
+		value: func(o Order) driver.Valuer { return jsonsql.NullableFrom(o) },
+		scan:  func() sql.Scanner { return &jsonsql.Nullable[Order]{} },
+	},
+	{
+		name:  "json-sensitive",
+		value: func(o Order) driver.Valuer { return jsonsql.NewSensitive(o) },
+		scan:  func() sql.Scanner { return &jsonsql.Sensitive[Order]{} },
+	},
+	{
+		name:  "cbor",
+		value: func(o Order) driver.Valuer { return jsonsql.NewCbor(o) },
+		scan:  func() sql.Scanner { return &jsonsql.Cbor[Order]{} },
+	},
+	{
+		name:  "msgpack",
+		value: func(o Order) driver.Valuer { return jsonsql.NewMsgpack(o) },
+		scan:  func() sql.Scanner { return &jsonsql.Msgpack[Order]{} },
+	},
+}
+
+func BenchmarkValue(b *testing.B) {
+	for _, c := range columns {
+		for _, size := range Sizes {
+			v := c.value(NewOrder(size.Items))
+			b.Run(c.name+"/"+size.Name, func(b *testing.B) {
+				data, err := v.Value()
+				if err != nil {
+					b.Fatalf("Value failed: %v", err)
+				}
+				b.SetBytes(int64(len(data.([]byte))))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := v.Value(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	for _, c := range columns {
+		for _, size := range Sizes {
+			data, err := c.value(NewOrder(size.Items)).Value()
+			if err != nil {
+				b.Fatalf("Value failed: %v", err)
+			}
+			b.Run(c.name+"/"+size.Name, func(b *testing.B) {
+				b.SetBytes(int64(len(data.([]byte))))
+				b.ReportAllocs()
+				for b.Loop() {
+					if err := c.scan().Scan(data); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkScanOptions measures the cost of options that switch decoding to slower paths.
+func BenchmarkScanOptions(b *testing.B) {
+	data, err := jsonsql.NewValue(NewOrder(100)).Value()
+	if err != nil {
+		b.Fatalf("Value failed: %v", err)
+	}
+	options := []struct {
+		name string
+		opt  jsonsql.Option
+	}{
+		{"default", nil},
+		{"use-number", jsonsql.UseNumber(true)},
+		{"collect-errors", jsonsql.CollectErrors(true)},
+		{"key-case", jsonsql.KeyCase(jsonsql.SnakeCase, jsonsql.SnakeCase)},
+	}
+	for _, o := range options {
+		b.Run(o.name, func(b *testing.B) {
+			if o.opt != nil {
+				jsonsql.Configure[Order](o.opt)
+				b.Cleanup(func() { jsonsql.Configure[Order]() })
+			}
+			b.SetBytes(int64(len(data.([]byte))))
+			b.ReportAllocs()
+			for b.Loop() {
+				var v jsonsql.Value[Order]
+				if err := v.Scan(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestRoundTrip makes sure every benchmarked column decodes what it encodes,
+// so the benchmarks do not measure error paths.
+func TestRoundTrip(t *testing.T) {
+	for _, c := range columns {
+		data, err := c.value(NewOrder(3)).Value()
+		if err != nil {
+			t.Fatalf("%s: Value failed: %v", c.name, err)
+		}
+		if err := c.scan().Scan(data); err != nil {
+			t.Errorf("%s: Scan failed: %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package jsonsqlbench benchmarks Scan and Value of the jsonsql wrappers across payload sizes,
+// codecs and wrapper types, reporting throughput and allocations, so performance regressions
+// between releases are measurable and codec choices can be based on data:
+//
+//	go test -bench . -benchmem ./jsonsqlbench
+//	go test -bench 'Scan/json/large' -cpuprofile cpu.out ./jsonsqlbench
+//
+// Compare runs across releases with golang.org/x/perf/cmd/benchstat.
+// The payload generators are exported so downstream projects can benchmark their own setups.
+package jsonsqlbench
+
+import (
+	"fmt"
+	"time"
+)
+
+// Item is a line of an Order.
+type Item struct {
+	SKU      string            `json:"sku"`
+	Name     string            `json:"name"`
+	Quantity int               `json:"quantity"`
+	Price    float64           `json:"price"`
+	Tags     []string          `json:"tags"`
+	Attrs    map[string]string `json:"attrs"`
+}
+
+// Order is a representative document: nested structs, slices, maps, numbers and timestamps.
+type Order struct {
+	ID        int64     `json:"id"`
+	Customer  string    `json:"customer"`
+	CreatedAt time.Time `json:"created_at"`
+	Paid      bool      `json:"paid"`
+	Items     []Item    `json:"items"`
+	Notes     *string   `json:"notes,omitempty"`
+}
+
+// Size is a named payload size.
+type Size struct {
+	Name  string
+	Items int
+}
+
+// Sizes are the payload sizes used by the benchmarks: roughly 200 B, 20 KB and 2 MB of JSON.
+var Sizes = []Size{
+	{Name: "small", Items: 1},
+	{Name: "medium", Items: 100},
+	{Name: "large", Items: 10000},
+}
+
+// NewOrder returns a deterministic Order with n items.
+func NewOrder(n int) Order {
+	notes := "leave at the door"
+	o := Order{
+		ID:        42,
+		Customer:  "customer@example.com",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Paid:      true,
+		Items:     make([]Item, n),
+		Notes:     &notes,
+	}
+	for i := range o.Items {
+		o.Items[i] = Item{
+			SKU:      fmt.Sprintf("SKU-%06d", i),
+			Name:     fmt.Sprintf("Item number %d", i),
+			Quantity: i%5 + 1,
+			Price:    float64(i%1000) + 0.99,
+			Tags:     []string{"new", "sale"},
+			Attrs:    map[string]string{"color": "blue", "size": "M"},
+		}
+	}
+	return o
+}
@@ -0,0 +1,137 @@
+// Package jsonsqlcli implements the jsonsql command line tool. Go types cannot be loaded at
+// run time, so the tool is built as a small main package registering the types to check:
+//
+//	package main
+//
+//	import (
+//		_ "github.com/jackc/pgx/v5/stdlib"
+//		"github.com/jinford/jsonsql/jsonsqlcli"
+//		"example.com/app/model"
+//	)
+//
+//	func main() {
+//		jsonsqlcli.Register[model.Settings]("settings")
+//		jsonsqlcli.Main()
+//	}
+//
+// and run as
+//
+//	go run ./cmd/jsonsql validate -driver pgx -dsn "$DSN" -table users -column settings -type settings
+//
+// Migrations rewriting stored documents are registered the same way with RegisterMigration
+// and run with the migrate command.
+//
+// The command github.com/jinford/jsonsql/cmd/jsonsql is a prebuilt variant with common drivers
+// and the generic types "any" and "object".
+package jsonsqlcli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/jinford/jsonsql"
+)
+
+// registeredType is a Go type registered under a name.
+type registeredType struct {
+	name string
+	// scan decodes src like jsonsql.Value[T].Scan, with the options registered for T.
+	scan func(src any) error
+	// schema is jsonsql.Schema[T].
+	schema map[string]any
+}
+
+var (
+	mu    sync.Mutex
+	types = map[string]*registeredType{}
+)
+
+// Register makes T available to the commands under name. Options configured for T with
+// jsonsql.Configure apply. It panics if name is already registered.
+func Register[T any](name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := types[name]; ok {
+		panic(fmt.Sprintf("jsonsqlcli: type %q registered twice", name))
+	}
+	types[name] = &registeredType{
+		name: name,
+		scan: func(src any) error {
+			_, err := jsonsql.ScanJSON[T](src)
+			return err
+		},
+		schema: jsonsql.Schema[T](),
+	}
+}
+
+func lookupType(name string) (*registeredType, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	t, ok := types[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q (registered: %v)", name, slices.Sorted(maps.Keys(types)))
+	}
+	return t, nil
+}
+
+// Exit codes of Run.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// Main runs the command given by os.Args and exits.
+func Main() {
+	os.Exit(Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Run runs the command given by args (without the program name), writing its report to stdout
+// and errors to stderr, and returns the exit code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return ExitUsage
+	}
+	switch args[0] {
+	case "validate":
+		return runValidate(ctx, args[1:], stdout, stderr)
+	case "migrate":
+		return runMigrate(ctx, args[1:], stdout, stderr)
+	case "types":
+		mu.Lock()
+		typeNames := slices.Sorted(maps.Keys(types))
+		migrationNames := slices.Sorted(maps.Keys(migrations))
+		mu.Unlock()
+		for _, name := range typeNames {
+			fmt.Fprintln(stdout, "type", name)
+		}
+		for _, name := range migrationNames {
+			fmt.Fprintln(stdout, "migration", name)
+		}
+		return ExitOK
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return ExitOK
+	}
+	fmt.Fprintf(stderr, "jsonsql: unknown command %q\n", args[0])
+	usage(stderr)
+	return ExitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `usage: jsonsql <command> [flags]
+
+commands:
+  validate  scan every row of a JSON column into a registered type and report failures and drift
+  migrate   rewrite every row of a JSON column with a registered migration
+  types     list the registered types and migrations
+
+Run "jsonsql <command> -h" for the flags of a command.
+`)
+}
@@ -0,0 +1,135 @@
+package jsonsqlcli
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// fakeDriver is a database/sql driver serving the rows stored under the DSN for every query
+// and recording the statements it receives. Like SQLite in rollback-journal mode, it rejects
+// writes while the rows of a query are open.
+type fakeDriver struct {
+	mu       sync.Mutex
+	tables   map[string][][]driver.Value
+	log      []fakeStatement
+	openRows int
+}
+
+type fakeStatement struct {
+	query string
+	args  []driver.Value
+}
+
+var fake = &fakeDriver{tables: map[string][][]driver.Value{}}
+
+func init() {
+	sql.Register("jsonsqlcli-fake", fake)
+}
+
+// setRows stores the rows served for dsn and clears the statement log.
+func (d *fakeDriver) setRows(dsn string, rows [][]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tables[dsn] = rows
+	d.log = nil
+}
+
+func (d *fakeDriver) statements() []fakeStatement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]fakeStatement(nil), d.log...)
+}
+
+func (d *fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{d: d, dsn: dsn}, nil
+}
+
+type fakeConn struct {
+	d   *fakeDriver
+	dsn string
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return (&fakeStmt{c: c, query: query}).Query(namedValues(args))
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return (&fakeStmt{c: c, query: query}).Exec(namedValues(args))
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return values
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	if s.c.d.openRows > 0 {
+		return nil, errors.New("database is locked")
+	}
+	s.c.d.log = append(s.c.d.log, fakeStatement{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	s.c.d.log = append(s.c.d.log, fakeStatement{query: s.query, args: args})
+	s.c.d.openRows++
+	return &fakeRows{d: s.c.d, rows: s.c.d.tables[s.c.dsn]}, nil
+}
+
+type fakeRows struct {
+	d      *fakeDriver
+	rows   [][]driver.Value
+	i      int
+	closed bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id", "doc"} }
+
+func (r *fakeRows) Close() error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		r.d.openRows--
+	}
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i == len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.i])
+	r.i++
+	return nil
+}
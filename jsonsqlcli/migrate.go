@@ -0,0 +1,306 @@
+package jsonsqlcli
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/jinford/jsonsql"
+)
+
+// Migration rewrites a stored JSON document, typically to the current version of its Go type.
+// Documents that need no change may be returned as they are; they are not written back.
+type Migration func(doc []byte) ([]byte, error)
+
+var migrations = map[string]Migration{}
+
+// RegisterMigration makes m available to the migrate command under name.
+// It panics if name is already registered.
+func RegisterMigration(name string, m Migration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := migrations[name]; ok {
+		panic(fmt.Sprintf("jsonsqlcli: migration %q registered twice", name))
+	}
+	migrations[name] = m
+}
+
+// MigrateType returns a Migration decoding documents into T like jsonsql.Value[T], calling fn
+// and encoding the result again, with the options registered for T:
+//
+//	jsonsqlcli.RegisterMigration("settings-v2", jsonsqlcli.MigrateType(func(s *model.Settings) error {
+//		if s.Version < 2 {
+//			s.Theme, s.Version = strings.ToLower(s.Theme), 2
+//		}
+//		return nil
+//	}))
+func MigrateType[T any](fn func(v *T) error) Migration {
+	return func(doc []byte) ([]byte, error) {
+		v, err := jsonsql.ScanJSON[T](doc)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(&v); err != nil {
+			return nil, err
+		}
+		return jsonsql.EncodeJSON(v)
+	}
+}
+
+// RotateKeys returns a Migration re-signing jsonsql.Signed[T] documents with the current key
+// configured for T, verifying them with the previous keys through the same configuration
+// (typically a jsonsql.KeyResolver that still knows the old key IDs). ctx is passed to the
+// resolver. Documents already signed with the current key are left unchanged:
+//
+//	jsonsqlcli.RegisterMigration("rotate-license-keys", jsonsqlcli.RotateKeys[model.License](ctx))
+//
+// and run with
+//
+//	go run ./cmd/jsonsql migrate -driver pgx -dsn "$DSN" -table licenses -column doc -migration rotate-license-keys
+func RotateKeys[T any](ctx context.Context) Migration {
+	return func(doc []byte) ([]byte, error) {
+		return jsonsql.Resign[T](ctx, doc)
+	}
+}
+
+func lookupMigration(name string) (Migration, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	m, ok := migrations[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown migration %q (registered: %v)", name, slices.Sorted(maps.Keys(migrations)))
+	}
+	return m, nil
+}
+
+// migrateOptions are the flags of the migrate command besides the row selection.
+type migrateOptions struct {
+	batch       int
+	dryRun      bool
+	rate        float64
+	placeholder string
+	maxErrors   int
+}
+
+func runMigrate(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		db            dbFlags
+		migrationName string
+		opts          migrateOptions
+	)
+	db.register(fs)
+	fs.StringVar(&migrationName, "migration", "", "registered migration name")
+	fs.IntVar(&opts.batch, "batch", 500, "number of updated rows per transaction")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "report the rows that would change without writing them")
+	fs.Float64Var(&opts.rate, "rate", 0, "maximum number of rows read per second (0 for unlimited)")
+	fs.StringVar(&opts.placeholder, "placeholder", "", `query placeholder style, "?" or "$" (default "$" for pgx and postgres, "?" otherwise)`)
+	fs.IntVar(&opts.maxErrors, "max-errors", 100, "maximum number of failing rows to list (0 for all)")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if err := db.validate(); err != nil {
+		fmt.Fprintf(stderr, "jsonsql migrate: %v\n", err)
+		return ExitUsage
+	}
+	if migrationName == "" {
+		fmt.Fprintln(stderr, "jsonsql migrate: -migration is required")
+		return ExitUsage
+	}
+	if opts.batch < 1 {
+		fmt.Fprintln(stderr, "jsonsql migrate: -batch must be positive")
+		return ExitUsage
+	}
+	m, err := lookupMigration(migrationName)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonsql migrate: %v\n", err)
+		return ExitUsage
+	}
+
+	report, err := migrate(ctx, db, m, opts, stdout)
+	if report != nil {
+		report.write(stdout, opts.dryRun)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonsql migrate: %v\n", err)
+		return ExitFailure
+	}
+	if report.failed > 0 {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// migrateReport summarizes a migrate run.
+type migrateReport struct {
+	rows, changed, failed, null int
+}
+
+func (r *migrateReport) write(w io.Writer, dryRun bool) {
+	verb := "updated"
+	if dryRun {
+		verb = "would update"
+	}
+	fmt.Fprintf(w, "scanned %d rows: %s %d, %d unchanged, %d failed, %d NULL\n",
+		r.rows, verb, r.changed, r.rows-r.changed-r.failed-r.null, r.failed, r.null)
+}
+
+// pendingUpdate is a migrated document waiting to be written.
+type pendingUpdate struct {
+	key any
+	doc []byte
+}
+
+// migrate streams the rows selected by db, applies m and writes changed documents back in
+// batches of opts.batch rows per transaction. The report covers the rows processed before an error.
+//
+// The changed documents are collected until the query has been read completely and its rows
+// closed, because writing while a cursor is open fails with "database is locked" on SQLite
+// and blocks on single-connection pools. Memory use therefore grows with the changed documents.
+func migrate(ctx context.Context, db dbFlags, m Migration, opts migrateOptions, stdout io.Writer) (*migrateReport, error) {
+	conn, err := sql.Open(db.driver, db.dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	placeholder := opts.placeholder
+	if placeholder == "" {
+		placeholder = "?"
+		if db.driver == "pgx" || db.driver == "postgres" {
+			placeholder = "$"
+		}
+	}
+	update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", db.table, db.column, db.key)
+	if placeholder == "$" {
+		update = fmt.Sprintf("UPDATE %s SET %s = $1 WHERE %s = $2", db.table, db.column, db.key)
+	}
+
+	report := &migrateReport{}
+	pending, err := readMigrations(ctx, conn, db, m, opts, report, stdout)
+	if err != nil || opts.dryRun {
+		return report, err
+	}
+	for batch := range slices.Chunk(pending, opts.batch) {
+		if err := writeBatch(ctx, conn, update, batch); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// readMigrations reads the rows selected by db, applies m and returns the changed documents,
+// counting the rows in report. The rows are closed when it returns.
+func readMigrations(ctx context.Context, conn *sql.DB, db dbFlags, m Migration, opts migrateOptions, report *migrateReport, stdout io.Writer) ([]pendingUpdate, error) {
+	rows, err := conn.QueryContext(ctx, db.query())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		pending []pendingUpdate
+		listed  int
+		start   = time.Now()
+	)
+	for rows.Next() {
+		if opts.rate > 0 {
+			// Wait until the row is due at the configured rate.
+			due := start.Add(time.Duration(float64(report.rows) / opts.rate * float64(time.Second)))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		var key, raw any
+		if err := rows.Scan(&key, &raw); err != nil {
+			return nil, err
+		}
+		if b, ok := key.([]byte); ok {
+			key = string(b)
+		}
+		report.rows++
+
+		var doc []byte
+		switch raw := raw.(type) {
+		case nil:
+			report.null++
+			continue
+		case []byte:
+			doc = bytes.Clone(raw)
+		case string:
+			doc = []byte(raw)
+		default:
+			return nil, fmt.Errorf("row %v: unsupported column value %T", key, raw)
+		}
+
+		migrated, err := m(doc)
+		if err == nil && !json.Valid(migrated) {
+			err = fmt.Errorf("migration returned invalid JSON")
+		}
+		if err != nil {
+			report.failed++
+			if opts.maxErrors == 0 || listed < opts.maxErrors {
+				listed++
+				fmt.Fprintf(stdout, "row %v: %v\n", key, err)
+			}
+			continue
+		}
+		if jsonEqual(doc, migrated) {
+			continue
+		}
+		report.changed++
+		if opts.dryRun {
+			fmt.Fprintf(stdout, "row %v: would update\n", key)
+			continue
+		}
+		pending = append(pending, pendingUpdate{key: key, doc: migrated})
+	}
+	return pending, rows.Err()
+}
+
+// writeBatch writes the documents of batch in one transaction.
+func writeBatch(ctx context.Context, conn *sql.DB, update string, batch []pendingUpdate) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, u := range batch {
+		if _, err := tx.ExecContext(ctx, update, u.doc, u.key); err != nil {
+			return fmt.Errorf("row %v: %w", u.key, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// jsonEqual reports whether a and b hold the same JSON value, ignoring formatting and key order.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if err := decodeNumbers(a, &va); err != nil {
+		return false
+	}
+	if err := decodeNumbers(b, &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func decodeNumbers(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
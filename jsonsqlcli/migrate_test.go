@@ -0,0 +1,188 @@
+package jsonsqlcli
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+type testVersioned struct {
+	Version int    `json:"version"`
+	Theme   string `json:"theme"`
+}
+
+func init() {
+	RegisterMigration("theme-v2", MigrateType(func(v *testVersioned) error {
+		if v.Theme == "broken" {
+			return errors.New("cannot migrate broken theme")
+		}
+		if v.Version < 2 {
+			v.Theme, v.Version = strings.ToLower(v.Theme), 2
+		}
+		return nil
+	}))
+	RegisterMigration("rotate-license-keys", RotateKeys[testLicense](context.Background()))
+}
+
+func migrateRows() [][]driver.Value {
+	return [][]driver.Value{
+		{int64(1), []byte(`{"version":1,"theme":"DARK"}`)},
+		{int64(2), []byte(`{"theme": "light", "version": 2}`)},
+		{int64(3), []byte(`{"version":1,"theme":"broken"}`)},
+		{int64(4), nil},
+		{int64(5), `{"version":1,"theme":"Light"}`},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	fake.setRows("migrate", migrateRows())
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), []string{"migrate",
+		"-driver", "jsonsqlcli-fake", "-dsn", "migrate", "-batch", "1",
+		"-table", "users", "-column", "settings", "-migration", "theme-v2",
+	}, &stdout, &stderr)
+	if code != ExitFailure {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitFailure, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "row 3: cannot migrate broken theme\n") {
+		t.Errorf("expected failure of row 3, got:\n%s", stdout.String())
+	}
+	if !strings.HasSuffix(stdout.String(), "scanned 5 rows: updated 2, 1 unchanged, 1 failed, 1 NULL\n") {
+		t.Errorf("unexpected summary:\n%s", stdout.String())
+	}
+
+	var updates []fakeStatement
+	for _, s := range fake.statements() {
+		if strings.HasPrefix(s.query, "UPDATE") {
+			updates = append(updates, s)
+		}
+	}
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %+v", updates)
+	}
+	if updates[0].query != "UPDATE users SET settings = ? WHERE id = ?" {
+		t.Errorf("unexpected query: %s", updates[0].query)
+	}
+	if string(updates[0].args[0].([]byte)) != `{"version":2,"theme":"dark"}` || updates[0].args[1] != int64(1) {
+		t.Errorf("unexpected arguments: %v", updates[0].args)
+	}
+	if string(updates[1].args[0].([]byte)) != `{"version":2,"theme":"light"}` || updates[1].args[1] != int64(5) {
+		t.Errorf("unexpected arguments: %v", updates[1].args)
+	}
+}
+
+func TestMigrateType_RawMessage(t *testing.T) {
+	jsonsql.Configure[testVersioned](jsonsql.ValueAsRawMessage(true))
+	t.Cleanup(func() { jsonsql.Configure[testVersioned]() })
+
+	out, err := migrations["theme-v2"]([]byte(`{"version":1,"theme":"DARK"}`))
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if string(out) != `{"version":2,"theme":"dark"}` {
+		t.Errorf("unexpected document %s", out)
+	}
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	fake.setRows("dry-run", migrateRows()[:2])
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), []string{"migrate", "-dry-run", "-placeholder", "$",
+		"-driver", "jsonsqlcli-fake", "-dsn", "dry-run",
+		"-table", "users", "-column", "settings", "-migration", "theme-v2",
+	}, &stdout, &stderr)
+	if code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitOK, code, stderr.String())
+	}
+	expected := "row 1: would update\nscanned 2 rows: would update 1, 1 unchanged, 0 failed, 0 NULL\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q, got %q", expected, stdout.String())
+	}
+	for _, s := range fake.statements() {
+		if strings.HasPrefix(s.query, "UPDATE") {
+			t.Errorf("unexpected write in dry run: %+v", s)
+		}
+	}
+}
+
+type testLicense struct {
+	Plan string `json:"plan"`
+}
+
+// testKeyRing signs with the current key and verifies with any known key.
+type testKeyRing struct {
+	current string
+	keys    map[string]jsonsql.Signer
+}
+
+func (r *testKeyRing) SigningKey(context.Context) (string, jsonsql.Signer, error) {
+	return r.current, r.keys[r.current], nil
+}
+
+func (r *testKeyRing) VerificationKey(_ context.Context, keyID string) (jsonsql.Signer, error) {
+	if s := r.keys[keyID]; s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", keyID)
+}
+
+func TestMigrate_RotateKeys(t *testing.T) {
+	ring := &testKeyRing{current: "v1", keys: map[string]jsonsql.Signer{
+		"v1": jsonsql.HMACSigner([]byte("old")),
+		"v2": jsonsql.HMACSigner([]byte("new")),
+	}}
+	jsonsql.Configure[testLicense](jsonsql.ResolveKeys(ring))
+	t.Cleanup(func() { jsonsql.Configure[testLicense](jsonsql.ResolveKeys(nil)) })
+	sign := func(plan string) driver.Value {
+		v, err := jsonsql.NewSigned(testLicense{Plan: plan}).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	old := sign("pro")
+	ring.current = "v2"
+	current := sign("free")
+	fake.setRows("rotate", [][]driver.Value{
+		{int64(1), old},
+		{int64(2), current},
+		{int64(3), []byte(`{"plan":"pro"}`)},
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), []string{"migrate",
+		"-driver", "jsonsqlcli-fake", "-dsn", "rotate",
+		"-table", "licenses", "-column", "doc", "-migration", "rotate-license-keys",
+	}, &stdout, &stderr)
+	if code != ExitFailure {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitFailure, code, stderr.String())
+	}
+	if !strings.HasSuffix(stdout.String(), "scanned 3 rows: updated 1, 1 unchanged, 1 failed, 0 NULL\n") {
+		t.Errorf("unexpected report:\n%s", stdout.String())
+	}
+
+	var updates []fakeStatement
+	for _, s := range fake.statements() {
+		if strings.HasPrefix(s.query, "UPDATE") {
+			updates = append(updates, s)
+		}
+	}
+	if len(updates) != 1 || updates[0].args[1] != int64(1) {
+		t.Fatalf("expected row 1 to be updated, got %+v", updates)
+	}
+	var license jsonsql.Signed[testLicense]
+	if err := license.Scan(updates[0].args[0]); err != nil || license.V.Plan != "pro" {
+		t.Errorf("expected re-signed document to scan, got %+v, %v", license.V, err)
+	}
+	if !strings.Contains(string(updates[0].args[0].([]byte)), `"kid":"v2"`) {
+		t.Errorf("expected current key ID, got %s", updates[0].args[0])
+	}
+}
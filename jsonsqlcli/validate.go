@@ -0,0 +1,322 @@
+package jsonsqlcli
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// dbFlags are the flags selecting the rows of a JSON column.
+type dbFlags struct {
+	driver string
+	dsn    string
+	table  string
+	column string
+	key    string
+	where  string
+	limit  int
+}
+
+func (f *dbFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.driver, "driver", "", "database/sql driver name, e.g. pgx, mysql or sqlite")
+	fs.StringVar(&f.dsn, "dsn", os.Getenv("JSONSQL_DSN"), "data source name (default $JSONSQL_DSN)")
+	fs.StringVar(&f.table, "table", "", "table to read")
+	fs.StringVar(&f.column, "column", "", "JSON column to check")
+	fs.StringVar(&f.key, "key", "id", "column identifying rows in the report")
+	fs.StringVar(&f.where, "where", "", "optional SQL condition selecting the rows")
+	fs.IntVar(&f.limit, "limit", 0, "maximum number of rows to read (0 for all)")
+}
+
+func (f *dbFlags) validate() error {
+	for _, flag := range []struct{ name, value string }{
+		{"driver", f.driver}, {"dsn", f.dsn}, {"table", f.table}, {"column", f.column},
+	} {
+		if flag.value == "" {
+			return fmt.Errorf("-%s is required", flag.name)
+		}
+	}
+	return nil
+}
+
+// query returns the SELECT statement reading the key and the column.
+// Identifiers and the condition are inserted as given.
+func (f *dbFlags) query() string {
+	q := fmt.Sprintf("SELECT %s, %s FROM %s", f.key, f.column, f.table)
+	if f.where != "" {
+		q += " WHERE " + f.where
+	}
+	q += " ORDER BY " + f.key
+	if f.limit > 0 {
+		q += fmt.Sprintf(" LIMIT %d", f.limit)
+	}
+	return q
+}
+
+func runValidate(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		db        dbFlags
+		typeName  string
+		strict    bool
+		maxErrors int
+	)
+	db.register(fs)
+	fs.StringVar(&typeName, "type", "", "registered type name")
+	fs.BoolVar(&strict, "strict", false, "also fail rows with unknown keys or values of the wrong JSON type")
+	fs.IntVar(&maxErrors, "max-errors", 100, "maximum number of failing rows to list (0 for all)")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if err := db.validate(); err != nil {
+		fmt.Fprintf(stderr, "jsonsql validate: %v\n", err)
+		return ExitUsage
+	}
+	if typeName == "" {
+		fmt.Fprintln(stderr, "jsonsql validate: -type is required")
+		return ExitUsage
+	}
+	typ, err := lookupType(typeName)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonsql validate: %v\n", err)
+		return ExitUsage
+	}
+
+	listed := 0
+	report, err := validate(ctx, db, typ, strict, func(key any, problems []string) {
+		if maxErrors > 0 && listed == maxErrors {
+			return
+		}
+		listed++
+		fmt.Fprintf(stdout, "row %v: %s\n", key, strings.Join(problems, "; "))
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonsql validate: %v\n", err)
+		return ExitFailure
+	}
+	report.write(stdout)
+	if report.failed > 0 {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// validateReport summarizes a validate run.
+type validateReport struct {
+	rows, failed, null int
+	// drift counts the rows per drift entry.
+	drift map[driftEntry]int
+}
+
+// driftEntry is a difference between stored documents and the schema of the Go type.
+type driftEntry struct {
+	// kind is "unknown" (key not in the type), "missing" (field not in the document)
+	// or "type" (value of another JSON type).
+	kind string
+	// path is a JSON Pointer with "*" for array elements and map values.
+	path string
+	// expected is the expected JSON type for kind "type".
+	expected string
+}
+
+func (r *validateReport) write(w io.Writer) {
+	fmt.Fprintf(w, "scanned %d rows: %d failed, %d ok, %d NULL\n", r.rows, r.failed, r.rows-r.failed-r.null, r.null)
+	if len(r.drift) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "drift:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	entries := slices.SortedFunc(maps.Keys(r.drift), func(a, b driftEntry) int {
+		if c := strings.Compare(a.path, b.path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.kind, b.kind)
+	})
+	for _, e := range entries {
+		detail := ""
+		if e.kind == "type" {
+			detail = " (expected " + e.expected + ")"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%d rows%s\n", e.kind, e.path, r.drift[e], detail)
+	}
+	tw.Flush()
+}
+
+// validate reads the rows selected by db and checks them against typ, calling fail for every
+// failing row.
+func validate(ctx context.Context, db dbFlags, typ *registeredType, strict bool, fail func(key any, problems []string)) (*validateReport, error) {
+	conn, err := sql.Open(db.driver, db.dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, db.query())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &validateReport{drift: map[driftEntry]int{}}
+	for rows.Next() {
+		var key, raw any
+		if err := rows.Scan(&key, &raw); err != nil {
+			return nil, err
+		}
+		if b, ok := key.([]byte); ok {
+			key = string(b)
+		}
+		report.rows++
+		if raw == nil {
+			report.null++
+			continue
+		}
+
+		var problems []string
+		if err := typ.scan(raw); err != nil {
+			problems = append(problems, "decode: "+err.Error())
+		}
+		entries := documentDrift(raw, typ.schema)
+		for _, e := range entries {
+			report.drift[e]++
+			if strict && e.kind != "missing" {
+				problems = append(problems, e.String())
+			}
+		}
+		if len(problems) > 0 {
+			report.failed++
+			fail(key, problems)
+		}
+	}
+	return report, rows.Err()
+}
+
+func (e driftEntry) String() string {
+	switch e.kind {
+	case "unknown":
+		return "unknown key " + e.path
+	case "type":
+		return fmt.Sprintf("%s is not %s", e.path, e.expected)
+	}
+	return "missing " + e.path
+}
+
+// documentDrift compares the stored document raw with schema. Each entry is reported once.
+func documentDrift(raw any, schema map[string]any) []driftEntry {
+	var data []byte
+	switch raw := raw.(type) {
+	case []byte:
+		data = raw
+	case string:
+		data = []byte(raw)
+	default:
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil
+	}
+	seen := map[driftEntry]bool{}
+	var entries []driftEntry
+	compareSchema(doc, schema, "", func(e driftEntry) {
+		if !seen[e] {
+			seen[e] = true
+			entries = append(entries, e)
+		}
+	})
+	return entries
+}
+
+// compareSchema reports the differences between the decoded JSON value v and schema.
+func compareSchema(v any, schema map[string]any, path string, report func(driftEntry)) {
+	typ, _ := schema["type"].(string)
+	if typ == "" {
+		return
+	}
+	if v == nil {
+		if nullable, _ := schema["nullable"].(bool); !nullable {
+			report(driftEntry{kind: "type", path: pointer(path), expected: typ})
+		}
+		return
+	}
+	if !matchesType(v, typ) {
+		report(driftEntry{kind: "type", path: pointer(path), expected: typ})
+		return
+	}
+
+	switch typ {
+	case "object":
+		obj := v.(map[string]any)
+		props, hasProps := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for _, k := range slices.Sorted(maps.Keys(obj)) {
+			switch ps, ok := props[k].(map[string]any); {
+			case ok:
+				compareSchema(obj[k], ps, path+"/"+escapeToken(k), report)
+			case additional != nil:
+				compareSchema(obj[k], additional, path+"/*", report)
+			case hasProps:
+				report(driftEntry{kind: "unknown", path: path + "/" + escapeToken(k)})
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(props)) {
+			if _, ok := obj[k]; !ok {
+				report(driftEntry{kind: "missing", path: path + "/" + escapeToken(k)})
+			}
+		}
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		for _, elem := range v.([]any) {
+			compareSchema(elem, items, path+"/*", report)
+		}
+	}
+}
+
+// matchesType reports whether the decoded JSON value v has the JSON Schema type typ.
+func matchesType(v any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		return ok && !strings.ContainsAny(n.String(), ".eE")
+	}
+	return true
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+var tokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapeToken(s string) string {
+	return tokenEscaper.Replace(s)
+}
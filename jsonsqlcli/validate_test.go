@@ -0,0 +1,94 @@
+package jsonsqlcli
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+type testSettings struct {
+	Theme string `json:"theme"`
+	Count int    `json:"count"`
+	Tags  []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+func init() {
+	Register[testSettings]("settings")
+}
+
+func TestValidate(t *testing.T) {
+	fake.setRows("validate", [][]driver.Value{
+		{int64(1), []byte(`{"theme":"dark","count":1,"tags":[]}`)},
+		{int64(2), []byte(`{"theme":"light","count":"2","tags":[]}`)},
+		{int64(3), []byte(`{"theme":"dark","legacy":true,"tags":[{"name":"a","color":"red"}]}`)},
+		{int64(4), nil},
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), []string{"validate",
+		"-driver", "jsonsqlcli-fake", "-dsn", "validate",
+		"-table", "users", "-column", "settings", "-type", "settings", "-where", "id < 10",
+	}, &stdout, &stderr)
+	if code != ExitFailure {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitFailure, code, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"row 2: decode: jsonsql.ScanJSON: jsonsql: /count: json: cannot unmarshal string into Go struct field testSettings.count of type int\n",
+		"scanned 4 rows: 1 failed, 2 ok, 1 NULL\n",
+		"  missing  /count         1 rows\n",
+		"  type     /count         1 rows (expected integer)\n",
+		"  unknown  /legacy        1 rows\n",
+		"  unknown  /tags/*/color  1 rows\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "row 3") {
+		t.Errorf("expected row 3 to pass without -strict, got:\n%s", out)
+	}
+
+	queries := fake.statements()
+	if len(queries) != 1 || queries[0].query != "SELECT id, settings FROM users WHERE id < 10 ORDER BY id" {
+		t.Errorf("unexpected queries: %+v", queries)
+	}
+}
+
+func TestValidate_Strict(t *testing.T) {
+	fake.setRows("strict", [][]driver.Value{
+		{int64(3), `{"theme":"dark","count":1,"legacy":true,"tags":null}`},
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), []string{"validate", "-strict",
+		"-driver", "jsonsqlcli-fake", "-dsn", "strict",
+		"-table", "users", "-column", "settings", "-type", "settings",
+	}, &stdout, &stderr)
+	if code != ExitFailure {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitFailure, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "row 3: unknown key /legacy\n") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := Run(context.Background(), []string{"validate", "-table", "users"}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+	}
+	if code := Run(context.Background(), []string{"validate",
+		"-driver", "jsonsqlcli-fake", "-dsn", "x", "-table", "t", "-column", "c", "-type", "unknown",
+	}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), `unknown type "unknown" (registered: [settings])`) {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+}
@@ -0,0 +1,235 @@
+// Package jsonsqldynamo lets structs shared between Postgres JSON columns and DynamoDB items
+// use the same wrapper fields, without parallel type definitions.
+// It lives in its own module so that the AWS SDK stays an optional dependency.
+//
+// Value[T] and Nullable[T] embed the corresponding jsonsql wrappers and implement
+// attributevalue.Marshaler and attributevalue.Unmarshaler. Documents are stored as native
+// DynamoDB attributes (M, L, S, N, BOOL and NULL), so they remain readable in the console
+// and usable in expressions:
+//
+//	type Order struct {
+//		ID    string                          `dynamodbav:"id"`
+//		Items jsonsqldynamo.Value[[]Item]     `dynamodbav:"items"`
+//		Notes jsonsqldynamo.Nullable[Notes]   `dynamodbav:"notes"`
+//	}
+package jsonsqldynamo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jinford/jsonsql"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ attributevalue.Marshaler   = Value[struct{}]{}
+	_ attributevalue.Unmarshaler = (*Value[struct{}])(nil)
+	_ attributevalue.Marshaler   = Nullable[struct{}]{}
+	_ attributevalue.Unmarshaler = (*Nullable[struct{}])(nil)
+)
+
+// Value[T] is a jsonsql.Value[T] that can also be stored as a DynamoDB attribute.
+type Value[T any] struct {
+	jsonsql.Value[T]
+}
+
+// NewValue creates a new Value[T] with the given value.
+func NewValue[T any](v T) Value[T] {
+	return Value[T]{jsonsql.NewValue(v)}
+}
+
+// MarshalDynamoDBAttributeValue implements attributevalue.Marshaler interface.
+// V is encoded with the jsonsql options registered for T and converted to native attributes.
+func (v Value[T]) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	av, err := toAttributeValue(v.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqldynamo.Value.MarshalDynamoDBAttributeValue: %w", err)
+	}
+	return av, nil
+}
+
+// UnmarshalDynamoDBAttributeValue implements attributevalue.Unmarshaler interface.
+// It decodes the attribute with the same rules as jsonsql.Value[T].Scan,
+// so a NULL attribute fails with jsonsql.ErrNullNotAllowed.
+func (v *Value[T]) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	src, err := fromAttributeValue(av)
+	if err != nil {
+		return fmt.Errorf("jsonsqldynamo.Value.UnmarshalDynamoDBAttributeValue: %w", err)
+	}
+	return v.Scan(src)
+}
+
+// Nullable[T] is a jsonsql.Nullable[T] that can also be stored as a DynamoDB attribute.
+type Nullable[T any] struct {
+	jsonsql.Nullable[T]
+}
+
+// NullableFrom creates a new Nullable[T] with Valid=true and the given value.
+func NullableFrom[T any](v T) Nullable[T] {
+	return Nullable[T]{jsonsql.NullableFrom(v)}
+}
+
+// MarshalDynamoDBAttributeValue implements attributevalue.Marshaler interface.
+// Returns a NULL attribute when Valid is false.
+func (n Nullable[T]) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	if !n.Valid {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	av, err := toAttributeValue(n.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqldynamo.Nullable.MarshalDynamoDBAttributeValue: %w", err)
+	}
+	return av, nil
+}
+
+// UnmarshalDynamoDBAttributeValue implements attributevalue.Unmarshaler interface.
+// It decodes the attribute with the same rules as jsonsql.Nullable[T].Scan.
+func (n *Nullable[T]) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	src, err := fromAttributeValue(av)
+	if err != nil {
+		return fmt.Errorf("jsonsqldynamo.Nullable.UnmarshalDynamoDBAttributeValue: %w", err)
+	}
+	return n.Scan(src)
+}
+
+// toAttributeValue encodes v as JSON with jsonsql.EncodeJSON and converts the document to attributes.
+func toAttributeValue[T any](v T) (types.AttributeValue, error) {
+	data, err := jsonsql.EncodeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, jsonsql.ErrTrailingData
+	}
+	return jsonToAttribute(doc), nil
+}
+
+func jsonToAttribute(v any) types.AttributeValue {
+	switch v := v.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: v}
+	case json.Number:
+		return &types.AttributeValueMemberN{Value: v.String()}
+	case string:
+		return &types.AttributeValueMemberS{Value: v}
+	case []any:
+		l := make([]types.AttributeValue, len(v))
+		for i, e := range v {
+			l[i] = jsonToAttribute(e)
+		}
+		return &types.AttributeValueMemberL{Value: l}
+	case map[string]any:
+		m := make(map[string]types.AttributeValue, len(v))
+		for k, e := range v {
+			m[k] = jsonToAttribute(e)
+		}
+		return &types.AttributeValueMemberM{Value: m}
+	}
+	panic(fmt.Sprintf("jsonsqldynamo: unexpected JSON value %T", v))
+}
+
+// fromAttributeValue converts an attribute to a source value for Scan: JSON bytes, or nil for NULL.
+// Binary attributes become base64 strings and sets become arrays, as encoding/json would write them.
+func fromAttributeValue(av types.AttributeValue) (any, error) {
+	if null, ok := av.(*types.AttributeValueMemberNULL); ok && null.Value {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if err := writeAttribute(&buf, av); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeAttribute(buf *bytes.Buffer, av types.AttributeValue) error {
+	switch av := av.(type) {
+	case *types.AttributeValueMemberNULL:
+		buf.WriteString("null")
+	case *types.AttributeValueMemberBOOL:
+		return writeJSON(buf, av.Value)
+	case *types.AttributeValueMemberN:
+		return writeNumber(buf, av.Value)
+	case *types.AttributeValueMemberS:
+		return writeJSON(buf, av.Value)
+	case *types.AttributeValueMemberB:
+		return writeJSON(buf, base64.StdEncoding.EncodeToString(av.Value))
+	case *types.AttributeValueMemberSS:
+		return writeJSON(buf, av.Value)
+	case *types.AttributeValueMemberBS:
+		return writeJSON(buf, av.Value)
+	case *types.AttributeValueMemberNS:
+		buf.WriteByte('[')
+		for i, n := range av.Value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeNumber(buf, n); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case *types.AttributeValueMemberL:
+		buf.WriteByte('[')
+		for i, e := range av.Value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeAttribute(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case *types.AttributeValueMemberM:
+		buf.WriteByte('{')
+		first := true
+		for k, e := range av.Value {
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			if err := writeJSON(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeAttribute(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported attribute type %T", av)
+	}
+	return nil
+}
+
+func writeJSON(buf *bytes.Buffer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// writeNumber writes a DynamoDB number, validating it as a JSON number.
+func writeNumber(buf *bytes.Buffer, n string) error {
+	if n == "" || (n[0] != '-' && (n[0] < '0' || n[0] > '9')) || !json.Valid([]byte(n)) {
+		return fmt.Errorf("invalid number %q", n)
+	}
+	buf.WriteString(n)
+	return nil
+}
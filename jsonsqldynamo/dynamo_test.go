@@ -0,0 +1,77 @@
+package jsonsqldynamo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jinford/jsonsql"
+)
+
+type testItem struct {
+	SKU   string  `json:"sku"`
+	Qty   int     `json:"qty"`
+	Price float64 `json:"price"`
+}
+
+type testOrder struct {
+	ID    string             `dynamodbav:"id"`
+	Items Value[[]testItem]  `dynamodbav:"items"`
+	Notes Nullable[[]string] `dynamodbav:"notes"`
+}
+
+func TestAttributeValue_RoundTrip(t *testing.T) {
+	order := testOrder{
+		ID:    "o-1",
+		Items: NewValue([]testItem{{SKU: "a", Qty: 2, Price: 9.5}}),
+	}
+
+	item, err := attributevalue.MarshalMap(order)
+	if err != nil {
+		t.Fatalf("MarshalMap failed: %v", err)
+	}
+
+	items, ok := item["items"].(*types.AttributeValueMemberL)
+	if !ok || len(items.Value) != 1 {
+		t.Fatalf("expected list attribute, got %#v", item["items"])
+	}
+	first := items.Value[0].(*types.AttributeValueMemberM).Value
+	if qty := first["qty"].(*types.AttributeValueMemberN).Value; qty != "2" {
+		t.Errorf("expected qty 2, got %s", qty)
+	}
+	if _, ok := item["notes"].(*types.AttributeValueMemberNULL); !ok {
+		t.Errorf("expected NULL notes, got %#v", item["notes"])
+	}
+
+	var restored testOrder
+	if err := attributevalue.UnmarshalMap(item, &restored); err != nil {
+		t.Fatalf("UnmarshalMap failed: %v", err)
+	}
+	if len(restored.Items.V) != 1 || restored.Items.V[0] != order.Items.V[0] {
+		t.Errorf("unexpected items: %+v", restored.Items.V)
+	}
+	if restored.Notes.Valid {
+		t.Errorf("expected NULL notes, got %+v", restored.Notes)
+	}
+}
+
+func TestNullable_UnmarshalSets(t *testing.T) {
+	var n Nullable[[]string]
+	if err := n.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberSS{Value: []string{"x", "y"}}); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !n.Valid || len(n.V) != 2 || n.V[1] != "y" {
+		t.Errorf("unexpected result: %+v", n)
+	}
+}
+
+func TestValue_UnmarshalErrors(t *testing.T) {
+	var v Value[testItem]
+	if err := v.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberNULL{Value: true}); !errors.Is(err, jsonsql.ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if err := v.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberN{Value: "true"}); err == nil {
+		t.Error("expected error for invalid number")
+	}
+}
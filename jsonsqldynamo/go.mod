@@ -0,0 +1,14 @@
+module github.com/jinford/jsonsql/jsonsqldynamo
+
+go 1.25.0
+
+require (
+	github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue v1.21.7
+	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.69.1
+	github.com/jinford/jsonsql v0.0.0-20261015212743-e91594b7692f
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2/service/dynamodbstreams v1.43.0 // indirect
+	github.com/aws/smithy-go v1.28.1 // indirect
+)
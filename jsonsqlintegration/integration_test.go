@@ -0,0 +1,53 @@
+//go:build integration
+
+package jsonsqlintegration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinford/jsonsql"
+)
+
+type testDocument struct {
+	Name    string            `json:"name"`
+	Count   int64             `json:"count"`
+	Ratio   float64           `json:"ratio"`
+	Tags    []string          `json:"tags"`
+	Attrs   map[string]string `json:"attrs"`
+	Created time.Time         `json:"created"`
+	Unicode string            `json:"unicode"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := testDocument{
+		Name:    "order",
+		Count:   1 << 40,
+		Ratio:   0.25,
+		Tags:    []string{"a", "b"},
+		Attrs:   map[string]string{"k": "v"},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Unicode: "é 😀 <&>",
+	}
+	for _, db := range Databases(t) {
+		t.Run(db.Name, func(t *testing.T) {
+			RoundTrip(t, db, doc)
+			RoundTrip(t, db, []any{"x", 1.5, true, nil})
+			RoundTrip(t, db, "scalar string")
+		})
+	}
+}
+
+func TestScanNull(t *testing.T) {
+	for _, db := range Databases(t) {
+		t.Run(db.Name, func(t *testing.T) {
+			var n jsonsql.Nullable[testDocument]
+			if err := db.DB.QueryRow("SELECT NULL").Scan(&n); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if n.Valid {
+				t.Errorf("expected NULL, got %+v", n)
+			}
+		})
+	}
+}
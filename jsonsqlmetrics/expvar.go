@@ -0,0 +1,74 @@
+package jsonsqlmetrics
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// Compile-time interface satisfaction check
+var _ Collector = (*Expvar)(nil)
+
+// DefaultSizeBuckets are the upper bounds in bytes of the payload size histogram buckets used by NewExpvar.
+var DefaultSizeBuckets = []int{256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20}
+
+// Expvar is a Collector publishing metrics as an expvar.Map, so they are served on /debug/vars.
+// The map holds one entry per operation and type, such as "scan main.Settings", with the keys
+// count, errors, bytes (total payload size) and the cumulative histogram buckets le_<bound> and le_inf.
+type Expvar struct {
+	m       *expvar.Map
+	buckets []int
+
+	// mu serializes the creation of entries.
+	mu sync.Mutex
+}
+
+// NewExpvar creates an Expvar collector published under name with DefaultSizeBuckets.
+// Like expvar.Publish, it panics if name is already in use.
+func NewExpvar(name string) *Expvar {
+	return NewExpvarBuckets(name, DefaultSizeBuckets)
+}
+
+// NewExpvarBuckets creates an Expvar collector published under name with the given
+// ascending bucket bounds in bytes.
+func NewExpvarBuckets(name string, buckets []int) *Expvar {
+	return &Expvar{m: expvar.NewMap(name), buckets: buckets}
+}
+
+// Count implements Collector.
+func (e *Expvar) Count(op, typ string) {
+	e.entry(op, typ).Add("count", 1)
+}
+
+// Error implements Collector.
+func (e *Expvar) Error(op, typ string) {
+	e.entry(op, typ).Add("errors", 1)
+}
+
+// Size implements Collector.
+func (e *Expvar) Size(op, typ string, bytes int) {
+	m := e.entry(op, typ)
+	m.Add("bytes", int64(bytes))
+	for _, bound := range e.buckets {
+		if bytes <= bound {
+			m.Add("le_"+strconv.Itoa(bound), 1)
+		}
+	}
+	m.Add("le_inf", 1)
+}
+
+// entry returns the map holding the metrics of op and typ, creating it on first use.
+func (e *Expvar) entry(op, typ string) *expvar.Map {
+	key := op + " " + typ
+	if m, ok := e.m.Get(key).(*expvar.Map); ok {
+		return m
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if m, ok := e.m.Get(key).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map)
+	e.m.Set(key, m)
+	return m
+}
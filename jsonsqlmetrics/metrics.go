@@ -0,0 +1,46 @@
+// Package jsonsqlmetrics collects scan/value counts, error counts and payload sizes of the
+// jsonsql wrappers per wrapped type. Metrics are recorded through the Collector interface,
+// which maps directly onto Prometheus vectors or OpenTelemetry instruments; Expvar is a
+// ready-made implementation publishing the metrics through the standard expvar package.
+//
+//	jsonsql.SetDefaults(jsonsqlmetrics.Option(jsonsqlmetrics.NewExpvar("jsonsql")))
+package jsonsqlmetrics
+
+import (
+	"github.com/jinford/jsonsql"
+)
+
+// Collector records the metrics of the jsonsql wrappers. op is jsonsql.OpScan or jsonsql.OpValue,
+// and typ is the Go type of the wrapped value. Implementations must be safe for concurrent use.
+//
+// A Prometheus implementation typically backs Count and Error with CounterVecs and Size with a
+// HistogramVec, all labeled by op and type.
+type Collector interface {
+	// Count is called for every Scan or Value.
+	Count(op, typ string)
+	// Error is called for every failed Scan or Value.
+	Error(op, typ string)
+	// Size is called with the payload size in bytes of every successful Scan or Value
+	// that has one. SQL NULL has no payload.
+	Size(op, typ string, bytes int)
+}
+
+// Hooks returns jsonsql hooks recording to c.
+func Hooks(c Collector) jsonsql.Hooks {
+	record := func(e jsonsql.HookEvent) {
+		c.Count(e.Op, e.Type)
+		if e.Err != nil {
+			c.Error(e.Op, e.Type)
+			return
+		}
+		if e.Size > 0 {
+			c.Size(e.Op, e.Type, e.Size)
+		}
+	}
+	return jsonsql.Hooks{OnScan: record, OnValue: record}
+}
+
+// Option returns a jsonsql option recording to c. It replaces any hooks set with jsonsql.Observe.
+func Option(c Collector) jsonsql.Option {
+	return jsonsql.Observe(Hooks(c))
+}
@@ -0,0 +1,45 @@
+package jsonsqlmetrics
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+type testDoc struct {
+	Name string `json:"name"`
+}
+
+func TestExpvar(t *testing.T) {
+	c := NewExpvarBuckets("jsonsqlmetrics_test", []int{8, 64})
+	jsonsql.Configure[testDoc](Option(c))
+	t.Cleanup(func() { jsonsql.Configure[testDoc]() })
+
+	var v jsonsql.Value[testDoc]
+	if err := v.Scan(`{"name":"a"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if err := v.Scan(`{`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	if _, err := v.Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	scan := expvar.Get("jsonsqlmetrics_test").(*expvar.Map).Get("scan jsonsqlmetrics.testDoc").(*expvar.Map)
+	expected := map[string]string{"count": "2", "errors": "1", "bytes": "12", "le_64": "1", "le_inf": "1"}
+	for key, want := range expected {
+		if got := scan.Get(key); got == nil || got.String() != want {
+			t.Errorf("scan %s: expected %s, got %v", key, want, got)
+		}
+	}
+	if scan.Get("le_8") != nil {
+		t.Errorf("expected empty le_8 bucket, got %v", scan.Get("le_8"))
+	}
+
+	value := expvar.Get("jsonsqlmetrics_test").(*expvar.Map).Get("value jsonsqlmetrics.testDoc").(*expvar.Map)
+	if got := value.Get("count"); got == nil || got.String() != "1" {
+		t.Errorf("value count: expected 1, got %v", got)
+	}
+}
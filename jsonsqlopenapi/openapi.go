@@ -0,0 +1,65 @@
+// Package jsonsqlopenapi adapts the schemas produced by jsonsql.Schema to kin-openapi,
+// so generated API documents describe Value[T] and Nullable[T] by their contents
+// instead of their V and Valid fields.
+// It lives in its own module so that kin-openapi stays an optional dependency.
+//
+//	ref, err := openapi3gen.NewSchemaRefForValue(&UserResponse{}, nil, jsonsqlopenapi.GeneratorOption())
+//
+// swaggo/swag builds documents from source code rather than reflection, so it cannot use this adapter;
+// declare wrapped fields there with a swaggertype tag naming the wrapped type instead.
+package jsonsqlopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/jinford/jsonsql"
+)
+
+// Compile-time interface satisfaction check
+var _ openapi3gen.SchemaCustomizerFn = Customize
+
+var schemaProviderType = reflect.TypeFor[jsonsql.SchemaProvider]()
+
+// Customize is an openapi3gen.SchemaCustomizerFn that replaces the generated schema of types
+// implementing jsonsql.SchemaProvider, such as the jsonsql wrappers, with the schema they provide.
+func Customize(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if !t.Implements(schemaProviderType) {
+		return nil
+	}
+	s, err := toSchema(reflect.Zero(t).Interface().(jsonsql.SchemaProvider).JSONSchema())
+	if err != nil {
+		return fmt.Errorf("jsonsqlopenapi.Customize: %s: %w", t, err)
+	}
+	*schema = *s
+	return nil
+}
+
+// GeneratorOption returns an openapi3gen option installing Customize.
+func GeneratorOption() openapi3gen.Option {
+	return openapi3gen.SchemaCustomizer(Customize)
+}
+
+// SchemaFor returns the kin-openapi schema of T as described by jsonsql.Schema.
+func SchemaFor[T any]() (*openapi3.Schema, error) {
+	s, err := toSchema(jsonsql.Schema[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlopenapi.SchemaFor: %w", err)
+	}
+	return s, nil
+}
+
+func toSchema(m map[string]any) (*openapi3.Schema, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	s := &openapi3.Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
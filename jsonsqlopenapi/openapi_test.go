@@ -0,0 +1,50 @@
+package jsonsqlopenapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/jinford/jsonsql"
+)
+
+type testProfile struct {
+	Name string `json:"name"`
+}
+
+type testUser struct {
+	ID      int64                      `json:"id"`
+	Profile jsonsql.Value[testProfile] `json:"profile"`
+	Tags    jsonsql.Nullable[[]string] `json:"tags"`
+}
+
+func TestGeneratorOption(t *testing.T) {
+	ref, err := openapi3gen.NewSchemaRefForValue(&testUser{}, nil, GeneratorOption())
+	if err != nil {
+		t.Fatalf("NewSchemaRefForValue failed: %v", err)
+	}
+
+	props := ref.Value.Properties
+	profile, err := json.Marshal(props["profile"].Value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(profile) != `{"properties":{"name":{"type":"string"}},"type":"object"}` {
+		t.Errorf("unexpected profile schema: %s", profile)
+	}
+
+	tags := props["tags"].Value
+	if !tags.Nullable || !tags.Type.Is("array") || !tags.Items.Value.Type.Is("string") {
+		t.Errorf("unexpected tags schema: %+v", tags)
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	s, err := SchemaFor[jsonsql.Nullable[int64]]()
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+	if !s.Nullable || !s.Type.Is("integer") || s.Format != "int64" {
+		t.Errorf("unexpected schema: %+v", s)
+	}
+}
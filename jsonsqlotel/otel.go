@@ -0,0 +1,192 @@
+// Package jsonsqlotel records OpenTelemetry span events for failed Scan and Value calls of
+// jsonsql wrappers, so decode failures show up on the trace of the request that caused them.
+// It lives in its own module so that OpenTelemetry stays an optional dependency.
+//
+// database/sql does not pass a context to sql.Scanner and driver.Valuer, so the context is
+// bound to the destination or value explicitly:
+//
+//	err := row.Scan(&id, jsonsqlotel.Scanner(ctx, &settings, jsonsqlotel.Column("settings")))
+//	_, err = db.ExecContext(ctx, query, jsonsqlotel.Valuer(ctx, settings))
+package jsonsqlotel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jinford/jsonsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner   = contextScanner{}
+	_ driver.Valuer = contextValuer{}
+)
+
+// Names of the span event and its attributes.
+const (
+	EventName = "jsonsql.error"
+
+	OpKey         = attribute.Key("jsonsql.op")
+	TypeKey       = attribute.Key("jsonsql.type")
+	ColumnKey     = attribute.Key("jsonsql.column")
+	ErrorClassKey = attribute.Key("jsonsql.error.class")
+	PayloadKey    = attribute.Key("jsonsql.payload.size")
+)
+
+// Option configures the recorded attributes.
+type Option func(*options)
+
+type options struct {
+	column string
+}
+
+// Column sets the column name recorded with the event.
+func Column(name string) Option {
+	return func(o *options) {
+		o.column = name
+	}
+}
+
+// Scanner returns a sql.Scanner scanning into dest that records a span event on the span
+// in ctx when dest.Scan fails. The error is returned unchanged. If dest implements
+// jsonsql.ContextScanner, it is scanned with ScanContext, so ctx also reaches the jsonsql
+// observability hooks and key resolvers.
+func Scanner(ctx context.Context, dest sql.Scanner, opts ...Option) sql.Scanner {
+	return contextScanner{ctx: ctx, dest: dest, opts: resolve(opts)}
+}
+
+// Valuer returns a driver.Valuer for v that records a span event on the span in ctx
+// when v.Value fails. The error is returned unchanged. If v implements jsonsql.ContextValuer,
+// it is encoded with ValueContext.
+func Valuer(ctx context.Context, v driver.Valuer, opts ...Option) driver.Valuer {
+	return contextValuer{ctx: ctx, v: v, opts: resolve(opts)}
+}
+
+type contextScanner struct {
+	ctx  context.Context
+	dest sql.Scanner
+	opts options
+}
+
+// Scan implements sql.Scanner interface.
+func (s contextScanner) Scan(src any) error {
+	var err error
+	if cs, ok := s.dest.(jsonsql.ContextScanner); ok {
+		err = cs.ScanContext(s.ctx, src)
+	} else {
+		err = s.dest.Scan(src)
+	}
+	if err != nil {
+		record(s.ctx, jsonsql.OpScan, s.dest, payloadSize(src), err, s.opts)
+	}
+	return err
+}
+
+type contextValuer struct {
+	ctx  context.Context
+	v    driver.Valuer
+	opts options
+}
+
+// Value implements driver.Valuer interface.
+func (v contextValuer) Value() (driver.Value, error) {
+	var (
+		value driver.Value
+		err   error
+	)
+	if cv, ok := v.v.(jsonsql.ContextValuer); ok {
+		value, err = cv.ValueContext(v.ctx)
+	} else {
+		value, err = v.v.Value()
+	}
+	if err != nil {
+		record(v.ctx, jsonsql.OpValue, v.v, 0, err, v.opts)
+	}
+	return value, err
+}
+
+func resolve(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// record adds the error event to the span in ctx and marks the span as failed.
+// Spans that are not recording are left alone.
+func record(ctx context.Context, op string, target any, size int, err error, o options) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		OpKey.String(op),
+		TypeKey.String(fmt.Sprintf("%T", target)),
+		ErrorClassKey.String(ErrorClass(err)),
+	}
+	if o.column != "" {
+		attrs = append(attrs, ColumnKey.String(o.column))
+	}
+	if op == jsonsql.OpScan {
+		attrs = append(attrs, PayloadKey.Int(size))
+	}
+	span.AddEvent(EventName, trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ErrorClass returns a short, low-cardinality classification of a jsonsql error suitable
+// as an attribute value: "null", "empty", "syntax", "type", "utf8", "trailing",
+// "unsupported_type", "too_large", "invalid_value", "validation", "read_only", "modified" or "other".
+func ErrorClass(err error) string {
+	var (
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		invalidErr *jsonsql.InvalidValueError
+	)
+	switch {
+	case errors.Is(err, jsonsql.ErrNullNotAllowed):
+		return "null"
+	case errors.Is(err, jsonsql.ErrEmptyInput):
+		return "empty"
+	case errors.Is(err, jsonsql.ErrInvalidJSON), errors.As(err, &syntaxErr):
+		return "syntax"
+	case errors.As(err, &typeErr):
+		return "type"
+	case errors.Is(err, jsonsql.ErrInvalidUTF8):
+		return "utf8"
+	case errors.Is(err, jsonsql.ErrTrailingData):
+		return "trailing"
+	case errors.Is(err, jsonsql.ErrUnsupportedSrcType):
+		return "unsupported_type"
+	case errors.Is(err, jsonsql.ErrTooLarge):
+		return "too_large"
+	case errors.As(err, &invalidErr):
+		return "invalid_value"
+	case errors.Is(err, jsonsql.ErrValidation):
+		return "validation"
+	case errors.Is(err, jsonsql.ErrReadOnly):
+		return "read_only"
+	case errors.Is(err, jsonsql.ErrImmutableModified):
+		return "modified"
+	}
+	return "other"
+}
+
+// payloadSize returns the size of a source value holding raw data.
+func payloadSize(src any) int {
+	switch s := src.(type) {
+	case []byte:
+		return len(s)
+	case string:
+		return len(s)
+	}
+	return 0
+}
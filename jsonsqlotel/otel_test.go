@@ -0,0 +1,140 @@
+package jsonsqlotel
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testDoc struct {
+	Name string `json:"name"`
+}
+
+func TestScanner_RecordsFailure(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "query")
+
+	var v jsonsql.Value[testDoc]
+	if err := Scanner(ctx, &v, Column("doc")).Scan(`{"name":"a"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if err := Scanner(ctx, &v, Column("doc")).Scan(`{"name":`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	var found bool
+	for _, e := range spans[0].Events() {
+		if e.Name != EventName {
+			continue
+		}
+		found = true
+		attrs := map[string]string{}
+		for _, kv := range e.Attributes {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		expected := map[string]string{
+			"jsonsql.op":           "scan",
+			"jsonsql.type":         "*jsonsql.Value[github.com/jinford/jsonsql/jsonsqlotel.testDoc]",
+			"jsonsql.column":       "doc",
+			"jsonsql.error.class":  "syntax",
+			"jsonsql.payload.size": "8",
+		}
+		for k, want := range expected {
+			if attrs[k] != want {
+				t.Errorf("%s: expected %s, got %s", k, want, attrs[k])
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected %s event, got %+v", EventName, spans[0].Events())
+	}
+}
+
+func TestValuer_RecordsFailure(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "query")
+
+	r := jsonsql.ReadOnly[testDoc]{Valid: true}
+	if _, err := Valuer(ctx, r).Value(); !errors.Is(err, jsonsql.ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if events := ended.Events(); len(events) != 1 || events[0].Name != EventName {
+		t.Errorf("unexpected events: %+v", events)
+	}
+	if status := ended.Status(); status.Code != codes.Error || status.Description == "" {
+		t.Errorf("expected error status, got %+v", status)
+	}
+}
+
+func TestScanner_PassesContext(t *testing.T) {
+	t.Cleanup(func() { jsonsql.Configure[testDoc]() })
+	var got context.Context
+	jsonsql.Configure[testDoc](jsonsql.Observe(jsonsql.Hooks{
+		OnScan: func(e jsonsql.HookEvent) { got = e.Context },
+	}))
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	var v jsonsql.Value[testDoc]
+	if err := Scanner(ctx, &v).Scan(`{"name":"a"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if got == nil || got.Value(ctxKey{}) != "request" {
+		t.Errorf("expected the Scanner context in the hook event, got %v", got)
+	}
+
+	var valuer contextRecorder
+	if _, err := Valuer(ctx, &valuer).Value(); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if valuer.ctx == nil || valuer.ctx.Value(ctxKey{}) != "request" {
+		t.Errorf("expected ValueContext to get the Valuer context, got %v", valuer.ctx)
+	}
+}
+
+// contextRecorder is a jsonsql.ContextValuer recording the context it was called with.
+type contextRecorder struct {
+	ctx context.Context
+}
+
+func (r *contextRecorder) Value() (driver.Value, error) {
+	return r.ValueContext(context.Background())
+}
+
+func (r *contextRecorder) ValueContext(ctx context.Context) (driver.Value, error) {
+	r.ctx = ctx
+	return "{}", nil
+}
+
+func TestErrorClass(t *testing.T) {
+	if got := ErrorClass(jsonsql.ErrNullNotAllowed); got != "null" {
+		t.Errorf("expected null, got %s", got)
+	}
+	var v jsonsql.Value[map[string]any]
+	if got := ErrorClass(v.Scan(42)); got != "unsupported_type" {
+		t.Errorf("expected unsupported_type, got %s", got)
+	}
+	if got := ErrorClass(fmt.Errorf("wrapped: %w", jsonsql.ErrValidation)); got != "validation" {
+		t.Errorf("expected validation, got %s", got)
+	}
+	if got := ErrorClass(errors.New("boom")); got != "other" {
+		t.Errorf("expected other, got %s", got)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/jinford/jsonsql/jsonsqlpb
+
+go 1.25.0
+
+require github.com/jinford/jsonsql v0.0.0-20261015212743-e91594b7692f
+
+require google.golang.org/protobuf v1.36.12
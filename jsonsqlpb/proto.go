@@ -0,0 +1,135 @@
+// Package jsonsqlpb stores protocol buffer messages in JSON columns using protojson,
+// so gRPC services can persist request and response messages type-safely.
+// It lives in its own module so that the protobuf runtime stays an optional dependency.
+//
+//	var req jsonsqlpb.Proto[*pb.CreateOrderRequest]
+//	err := row.Scan(&req)
+package jsonsqlpb
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jinford/jsonsql"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner   = (*Proto[*structpb.Struct])(nil)
+	_ driver.Valuer = Proto[*structpb.Struct]{}
+)
+
+// Proto[M] is a generic type for NOT NULL JSON columns holding a protocol buffer message.
+// Value encodes V with protojson and Scan decodes with protojson, following the proto3 JSON mapping
+// (camelCase field names, enums as strings, 64-bit integers as strings, ...).
+//
+// M is the generated message pointer type, such as *pb.Order.
+// Scan allocates a new message when V is nil.
+type Proto[M proto.Message] struct {
+	V M
+}
+
+// NewProto creates a new Proto[M] with the given message.
+func NewProto[M proto.Message](m M) Proto[M] {
+	return Proto[M]{V: m}
+}
+
+// Get returns the message.
+func (p Proto[M]) Get() M {
+	return p.V
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes protojson data from the database into V, replacing its previous contents.
+// Returns jsonsql.ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (p *Proto[M]) Scan(src any) error {
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		return jsonsql.ErrNullNotAllowed
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("jsonsqlpb.Proto.Scan: %w %T", jsonsql.ErrUnsupportedSrcType, src)
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return jsonsql.ErrNullNotAllowed
+	}
+
+	m := p.V.ProtoReflect().Type().New().Interface().(M)
+	if err := optionsFor[M]().unmarshal.Unmarshal(data, m); err != nil {
+		return fmt.Errorf("jsonsqlpb.Proto.Scan: %w", err)
+	}
+	p.V = m
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It encodes V with protojson for database storage. A nil V is stored as an empty object.
+func (p Proto[M]) Value() (driver.Value, error) {
+	data, err := optionsFor[M]().marshal.Marshal(p.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlpb.Proto.Value: %w", err)
+	}
+	return data, nil
+}
+
+// Option configures how Proto encodes and decodes messages.
+type Option func(*options)
+
+type options struct {
+	marshal   protojson.MarshalOptions
+	unmarshal protojson.UnmarshalOptions
+}
+
+// DiscardUnknown makes Scan ignore fields that are not part of the message, so rows written by
+// a newer schema can still be read. Without it, unknown fields make Scan fail.
+func DiscardUnknown(enabled bool) Option {
+	return func(o *options) {
+		o.unmarshal.DiscardUnknown = enabled
+	}
+}
+
+// UseProtoNames makes Value write the original proto field names (snake_case) instead of
+// their lowerCamelCase JSON names. Scan accepts both forms either way.
+func UseProtoNames(enabled bool) Option {
+	return func(o *options) {
+		o.marshal.UseProtoNames = enabled
+	}
+}
+
+// EmitUnpopulated makes Value write fields that hold their zero value.
+func EmitUnpopulated(enabled bool) Option {
+	return func(o *options) {
+		o.marshal.EmitUnpopulated = enabled
+	}
+}
+
+// registry holds the options registered with Configure, keyed by message type.
+var registry sync.Map
+
+// Configure sets the options used by Proto[M]. Calling Configure without options restores the defaults.
+// It is meant to be called during program initialization.
+func Configure[M proto.Message](opts ...Option) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	registry.Store(reflect.TypeFor[M](), o)
+}
+
+func optionsFor[M proto.Message]() *options {
+	if o, ok := registry.Load(reflect.TypeFor[M]()); ok {
+		return o.(*options)
+	}
+	return &options{}
+}
@@ -0,0 +1,93 @@
+package jsonsqlpb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type testMessage = *descriptorpb.FileDescriptorProto
+
+func resetOptions(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() { Configure[testMessage]() })
+}
+
+func TestProto_RoundTrip(t *testing.T) {
+	p := NewProto(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("order.proto"),
+		Dependency: []string{"common.proto"},
+	})
+
+	result, err := p.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Proto[testMessage]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !proto.Equal(scanned.V, p.V) {
+		t.Errorf("expected %v, got %v", p.V, scanned.V)
+	}
+}
+
+func TestProto_ScanReplaces(t *testing.T) {
+	p := NewProto(&descriptorpb.FileDescriptorProto{Name: proto.String("old.proto"), Package: proto.String("old")})
+	if err := p.Scan(`{"name":"new.proto"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if p.V.GetName() != "new.proto" || p.V.Package != nil {
+		t.Errorf("expected previous contents to be replaced, got %v", p.V)
+	}
+}
+
+func TestProto_Null(t *testing.T) {
+	var p Proto[testMessage]
+	for _, src := range []any{nil, []byte("null")} {
+		if err := p.Scan(src); !errors.Is(err, jsonsql.ErrNullNotAllowed) {
+			t.Errorf("Scan(%v): expected ErrNullNotAllowed, got %v", src, err)
+		}
+	}
+}
+
+func TestProto_UnknownFields(t *testing.T) {
+	resetOptions(t)
+
+	var p Proto[testMessage]
+	if err := p.Scan(`{"name":"a.proto","addedLater":1}`); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+
+	Configure[testMessage](DiscardUnknown(true))
+	if err := p.Scan(`{"name":"a.proto","addedLater":1}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if p.V.GetName() != "a.proto" {
+		t.Errorf("expected a.proto, got %s", p.V.GetName())
+	}
+}
+
+func TestProto_MarshalOptions(t *testing.T) {
+	resetOptions(t)
+	Configure[testMessage](UseProtoNames(true))
+
+	result, err := NewProto(&descriptorpb.FileDescriptorProto{PublicDependency: []int32{1}}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	// protojson randomly inserts whitespace to discourage byte comparisons.
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, result.([]byte)); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if buf.String() != `{"public_dependency":[1]}` {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
@@ -0,0 +1,48 @@
+package jsonsqlpb
+
+import (
+	"fmt"
+
+	"github.com/jinford/jsonsql"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToStruct converts the document held by v to a google.protobuf.Struct, for sending JSON column
+// contents over gRPC. The document is encoded with the jsonsql options registered for map[string]any,
+// so values such as time.Time are converted the same way as when stored.
+func ToStruct(v jsonsql.Value[map[string]any]) (*structpb.Struct, error) {
+	data, err := jsonsql.EncodeJSON(v.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlpb.ToStruct: %w", err)
+	}
+	s := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("jsonsqlpb.ToStruct: %w", err)
+	}
+	return s, nil
+}
+
+// FromStruct converts a google.protobuf.Struct to a Value[map[string]any].
+// Numbers become float64, as in the Struct itself. A nil s yields an empty map.
+func FromStruct(s *structpb.Struct) jsonsql.Value[map[string]any] {
+	return jsonsql.NewValue(s.AsMap())
+}
+
+// NullableToStruct converts n to a google.protobuf.Struct like ToStruct.
+// Returns nil when n is NULL.
+func NullableToStruct(n jsonsql.Nullable[map[string]any]) (*structpb.Struct, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return ToStruct(jsonsql.NewValue(n.V))
+}
+
+// NullableFromStruct converts a google.protobuf.Struct to a Nullable[map[string]any].
+// A nil s yields NULL.
+func NullableFromStruct(s *structpb.Struct) jsonsql.Nullable[map[string]any] {
+	if s == nil {
+		return jsonsql.Null[map[string]any]()
+	}
+	return jsonsql.NullableFrom(s.AsMap())
+}
@@ -0,0 +1,69 @@
+package jsonsqlpb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinford/jsonsql"
+)
+
+func TestToStruct(t *testing.T) {
+	v := jsonsql.NewValue(map[string]any{
+		"name":    "Alice",
+		"tags":    []string{"a", "b"},
+		"created": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"nested":  map[string]int{"n": 1},
+	})
+
+	s, err := ToStruct(v)
+	if err != nil {
+		t.Fatalf("ToStruct failed: %v", err)
+	}
+	if s.Fields["name"].GetStringValue() != "Alice" {
+		t.Errorf("unexpected name: %v", s.Fields["name"])
+	}
+	if s.Fields["created"].GetStringValue() != "2024-01-02T03:04:05Z" {
+		t.Errorf("unexpected created: %v", s.Fields["created"])
+	}
+	if n := s.Fields["nested"].GetStructValue().Fields["n"].GetNumberValue(); n != 1 {
+		t.Errorf("unexpected nested number: %v", n)
+	}
+
+	back := FromStruct(s)
+	if tags, ok := back.V["tags"].([]any); !ok || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("unexpected tags: %v", back.V["tags"])
+	}
+}
+
+func TestToStruct_RawMessage(t *testing.T) {
+	jsonsql.Configure[map[string]any](jsonsql.ValueAsRawMessage(true))
+	t.Cleanup(func() { jsonsql.Configure[map[string]any]() })
+
+	s, err := ToStruct(jsonsql.NewValue(map[string]any{"name": "Alice"}))
+	if err != nil {
+		t.Fatalf("ToStruct failed: %v", err)
+	}
+	if s.Fields["name"].GetStringValue() != "Alice" {
+		t.Errorf("unexpected name: %v", s.Fields["name"])
+	}
+}
+
+func TestNullableStruct(t *testing.T) {
+	s, err := NullableToStruct(jsonsql.Null[map[string]any]())
+	if err != nil || s != nil {
+		t.Fatalf("expected nil Struct for NULL, got %v, %v", s, err)
+	}
+
+	s, err = NullableToStruct(jsonsql.NullableFrom(map[string]any{"ok": true}))
+	if err != nil {
+		t.Fatalf("NullableToStruct failed: %v", err)
+	}
+	n := NullableFromStruct(s)
+	if !n.Valid || n.V["ok"] != true {
+		t.Errorf("unexpected result: %+v", n)
+	}
+
+	if n := NullableFromStruct(nil); n.Valid {
+		t.Errorf("expected NULL for nil Struct, got %+v", n)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/jinford/jsonsql/jsonsqlpgx
+
+go 1.25.0
+
+require (
+	github.com/jackc/pgx/v5 v5.11.0
+	github.com/jinford/jsonsql v0.0.0-20261015212743-e91594b7692f
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	golang.org/x/text v0.29.0 // indirect
+)
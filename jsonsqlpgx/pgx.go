@@ -0,0 +1,86 @@
+// Package jsonsqlpgx adapts jsonsql batch encoding to pgx bulk loading.
+//
+// It is a separate module so that the jsonsql module does not depend on pgx.
+package jsonsqlpgx
+
+import (
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jinford/jsonsql"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ pgx.CopyFromSource = (*source[struct{}])(nil)
+)
+
+// BatchSize is the number of documents CopyFrom encodes at once with jsonsql.BatchJSON.
+const BatchSize = 1024
+
+// CopyFrom returns a pgx.CopyFromSource for use with pgx.Conn.CopyFrom that produces one row per
+// element of docs. Documents are encoded in batches of BatchSize with the options registered for T,
+// and row builds the column values of row i from its encoded document, e.g. []any{ids[i], doc}.
+// The document can be passed directly for json and jsonb columns.
+func CopyFrom[T any](docs []T, row func(i int, doc []byte) []any) pgx.CopyFromSource {
+	return &source[T]{docs: docs, row: row, i: -1}
+}
+
+// source implements pgx.CopyFromSource over a slice of documents.
+type source[T any] struct {
+	docs   []T
+	row    func(i int, doc []byte) []any
+	i      int
+	base   int
+	batch  [][]byte
+	values []any
+	err    error
+}
+
+// Next advances to the next row, encoding the next batch of documents when needed.
+func (s *source[T]) Next() bool {
+	if s.err != nil || s.i+1 >= len(s.docs) {
+		return false
+	}
+	s.i++
+	if s.i-s.base >= len(s.batch) {
+		if err := s.encode(); err != nil {
+			s.err = err
+			return false
+		}
+	}
+	s.values = s.row(s.i, s.batch[s.i-s.base])
+	return true
+}
+
+// encode encodes the batch of documents starting at the current row.
+func (s *source[T]) encode() error {
+	end := min(s.i+BatchSize, len(s.docs))
+	values, err := jsonsql.BatchJSON(s.docs[s.i:end])
+	if err != nil {
+		return err
+	}
+	s.base = s.i
+	s.batch = s.batch[:0]
+	for _, v := range values {
+		// ValueAsRawMessage returns the documents as json.RawMessage.
+		switch v := v.(type) {
+		case []byte:
+			s.batch = append(s.batch, v)
+		case json.RawMessage:
+			s.batch = append(s.batch, v)
+		}
+	}
+	return nil
+}
+
+// Values returns the column values of the current row.
+func (s *source[T]) Values() ([]any, error) {
+	return s.values, nil
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (s *source[T]) Err() error {
+	return s.err
+}
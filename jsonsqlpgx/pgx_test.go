@@ -0,0 +1,72 @@
+package jsonsqlpgx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+type doc struct {
+	ID int `json:"id"`
+}
+
+func TestCopyFrom(t *testing.T) {
+	docs := make([]doc, BatchSize*2+3)
+	for i := range docs {
+		docs[i] = doc{ID: i}
+	}
+	src := CopyFrom(docs, func(i int, data []byte) []any {
+		return []any{i, data}
+	})
+
+	n := 0
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			t.Fatalf("Values failed: %v", err)
+		}
+		if values[0] != n {
+			t.Fatalf("row %d: unexpected index %v", n, values[0])
+		}
+		if expected := fmt.Sprintf(`{"id":%d}`, n); string(values[1].([]byte)) != expected {
+			t.Fatalf("row %d: expected %s, got %s", n, expected, values[1])
+		}
+		n++
+	}
+	if err := src.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if n != len(docs) {
+		t.Errorf("expected %d rows, got %d", len(docs), n)
+	}
+}
+
+func TestCopyFrom_Error(t *testing.T) {
+	src := CopyFrom([]any{1, make(chan int)}, func(i int, data []byte) []any {
+		return []any{data}
+	})
+	if src.Next() {
+		t.Fatal("expected Next to fail")
+	}
+	if err := src.Err(); err == nil || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCopyFrom_RawMessage(t *testing.T) {
+	jsonsql.Configure[doc](jsonsql.ValueAsRawMessage(true))
+	t.Cleanup(func() { jsonsql.Configure[doc]() })
+
+	src := CopyFrom([]doc{{ID: 1}}, func(i int, data []byte) []any {
+		return []any{data}
+	})
+	if !src.Next() {
+		t.Fatalf("expected a row, got error %v", src.Err())
+	}
+	values, _ := src.Values()
+	if string(values[0].([]byte)) != `{"id":1}` {
+		t.Errorf("unexpected document %s", values[0])
+	}
+}
@@ -0,0 +1,118 @@
+// Package jsonsqlspanner stores jsonsql documents in Google Cloud Spanner JSON columns,
+// so the same domain structs work across Spanner and Postgres.
+// It lives in its own module so that the Spanner client stays an optional dependency.
+//
+// Value[T] and Nullable[T] embed the corresponding jsonsql wrappers and add the Spanner client's
+// Encoder and Decoder interfaces, mapping to spanner.NullJSON:
+//
+//	type Order struct {
+//		ID    int64
+//		Items jsonsqlspanner.Value[[]Item]
+//	}
+//
+//	err := row.ToStruct(&order)
+package jsonsqlspanner
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+	"github.com/jinford/jsonsql"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ spanner.Encoder = Value[struct{}]{}
+	_ spanner.Decoder = (*Value[struct{}])(nil)
+	_ spanner.Encoder = Nullable[struct{}]{}
+	_ spanner.Decoder = (*Nullable[struct{}])(nil)
+)
+
+// Value[T] is a jsonsql.Value[T] that can also be read from and written to a NOT NULL Spanner JSON column.
+type Value[T any] struct {
+	jsonsql.Value[T]
+}
+
+// NewValue creates a new Value[T] with the given value.
+func NewValue[T any](v T) Value[T] {
+	return Value[T]{jsonsql.NewValue(v)}
+}
+
+// EncodeSpanner implements spanner.Encoder interface.
+// V is encoded with the jsonsql options registered for T.
+func (v Value[T]) EncodeSpanner() (any, error) {
+	data, err := jsonsql.EncodeJSON(v.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlspanner.Value.EncodeSpanner: %w", err)
+	}
+	return spanner.NullJSON{Value: json.RawMessage(data), Valid: true}, nil
+}
+
+// DecodeSpanner implements spanner.Decoder interface.
+// It decodes the column value with the same rules as jsonsql.Value[T].Scan.
+func (v *Value[T]) DecodeSpanner(input any) error {
+	src, err := spannerSource(input)
+	if err != nil {
+		return fmt.Errorf("jsonsqlspanner.Value.DecodeSpanner: %w", err)
+	}
+	return v.Scan(src)
+}
+
+// Nullable[T] is a jsonsql.Nullable[T] that can also be read from and written to a NULL-able Spanner JSON column.
+type Nullable[T any] struct {
+	jsonsql.Nullable[T]
+}
+
+// NullableFrom creates a new Nullable[T] with Valid=true and the given value.
+func NullableFrom[T any](v T) Nullable[T] {
+	return Nullable[T]{jsonsql.NullableFrom(v)}
+}
+
+// EncodeSpanner implements spanner.Encoder interface.
+// Returns a NULL spanner.NullJSON when Valid is false.
+func (n Nullable[T]) EncodeSpanner() (any, error) {
+	if !n.Valid {
+		return spanner.NullJSON{}, nil
+	}
+	data, err := jsonsql.EncodeJSON(n.V)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsqlspanner.Nullable.EncodeSpanner: %w", err)
+	}
+	return spanner.NullJSON{Value: json.RawMessage(data), Valid: true}, nil
+}
+
+// DecodeSpanner implements spanner.Decoder interface.
+// It decodes the column value with the same rules as jsonsql.Nullable[T].Scan.
+func (n *Nullable[T]) DecodeSpanner(input any) error {
+	src, err := spannerSource(input)
+	if err != nil {
+		return fmt.Errorf("jsonsqlspanner.Nullable.DecodeSpanner: %w", err)
+	}
+	return n.Scan(src)
+}
+
+// spannerSource converts the value passed to DecodeSpanner to a source accepted by Scan.
+// The client passes JSON columns as their string encoding, or nil for NULL.
+func spannerSource(input any) (any, error) {
+	switch in := input.(type) {
+	case nil, string, []byte:
+		return in, nil
+	case *string:
+		if in == nil {
+			return nil, nil
+		}
+		return *in, nil
+	case spanner.NullJSON:
+		if !in.Valid {
+			return nil, nil
+		}
+		return json.Marshal(in.Value)
+	case spanner.NullString:
+		if !in.Valid {
+			return nil, nil
+		}
+		return in.StringVal, nil
+	}
+	return nil, fmt.Errorf("%w %T", jsonsql.ErrUnsupportedSrcType, input)
+}
@@ -0,0 +1,75 @@
+package jsonsqlspanner
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/jinford/jsonsql"
+)
+
+type testProfile struct {
+	Name string `json:"name"`
+}
+
+func TestValue_EncodeSpanner(t *testing.T) {
+	out, err := NewValue(testProfile{Name: "Alice"}).EncodeSpanner()
+	if err != nil {
+		t.Fatalf("EncodeSpanner failed: %v", err)
+	}
+
+	j, ok := out.(spanner.NullJSON)
+	if !ok || !j.Valid {
+		t.Fatalf("expected valid spanner.NullJSON, got %#v", out)
+	}
+	data, err := json.Marshal(j.Value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"name":"Alice"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestValue_DecodeSpanner(t *testing.T) {
+	var v Value[testProfile]
+	if err := v.DecodeSpanner(`{"name":"Alice"}`); err != nil {
+		t.Fatalf("DecodeSpanner failed: %v", err)
+	}
+	if v.V.Name != "Alice" {
+		t.Errorf("expected Alice, got %s", v.V.Name)
+	}
+
+	if err := v.DecodeSpanner(nil); !errors.Is(err, jsonsql.ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if err := v.DecodeSpanner(42); err == nil {
+		t.Error("expected error for unsupported input")
+	}
+}
+
+func TestNullable_Spanner(t *testing.T) {
+	out, err := Nullable[testProfile]{}.EncodeSpanner()
+	if err != nil {
+		t.Fatalf("EncodeSpanner failed: %v", err)
+	}
+	if out.(spanner.NullJSON).Valid {
+		t.Errorf("expected NULL, got %#v", out)
+	}
+
+	n := NullableFrom(testProfile{Name: "Bob"})
+	if err := n.DecodeSpanner(nil); err != nil {
+		t.Fatalf("DecodeSpanner failed: %v", err)
+	}
+	if n.Valid {
+		t.Errorf("expected NULL, got %+v", n)
+	}
+
+	if err := n.DecodeSpanner(spanner.NullJSON{Value: map[string]any{"name": "Bob"}, Valid: true}); err != nil {
+		t.Fatalf("DecodeSpanner failed: %v", err)
+	}
+	if !n.Valid || n.V.Name != "Bob" {
+		t.Errorf("unexpected result: %+v", n)
+	}
+}
@@ -0,0 +1,85 @@
+package jsonsqltest
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("jsonsqltest.update", false, "rewrite golden files with the current output")
+
+// Golden compares the JSON written by v.Value with the golden file at path, ignoring formatting.
+// Run the tests with -jsonsqltest.update to create or rewrite the file with the current output,
+// indented with two spaces. Golden is meant for JSON; it fails for binary codecs.
+func Golden(t testing.TB, v driver.Valuer, path string) {
+	t.Helper()
+	value, err := v.Value()
+	if err != nil {
+		t.Errorf("Value of %T failed: %v", v, err)
+		return
+	}
+	var got []byte
+	switch value := value.(type) {
+	case []byte:
+		got = value
+	case string:
+		got = []byte(value)
+	default:
+		t.Errorf("Value of %T returned %T, expected JSON bytes", v, value)
+		return
+	}
+	gotIndented, err := indent(got)
+	if err != nil {
+		t.Errorf("Value of %T returned invalid JSON %q: %v", v, got, err)
+		return
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("jsonsqltest: %v", err)
+		}
+		if err := os.WriteFile(path, gotIndented, 0o644); err != nil {
+			t.Fatalf("jsonsqltest: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("jsonsqltest: %v (run with -jsonsqltest.update to create it)", err)
+		return
+	}
+	wantIndented, err := indent(want)
+	if err != nil {
+		t.Errorf("jsonsqltest: invalid JSON in golden file %s: %v", path, err)
+		return
+	}
+	if !bytes.Equal(gotIndented, wantIndented) {
+		t.Errorf("Value of %T does not match golden file %s:\nwant %s\n got %s", v, path, wantIndented, gotIndented)
+	}
+}
+
+// ScanGolden scans the golden file at path into a new S with ScanAll and returns the result,
+// to check that documents in a known stored format still decode.
+func ScanGolden[S any, P Scanner[S]](t testing.TB, path string) S {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("jsonsqltest: %v", err)
+	}
+	return ScanAll[S, P](t, string(data))
+}
+
+// indent returns data indented with two spaces and terminated by a newline.
+func indent(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
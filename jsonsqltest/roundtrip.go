@@ -0,0 +1,92 @@
+// Package jsonsqltest provides test helpers for code using jsonsql, so downstream projects
+// can verify that their own types survive the wrappers with the options they configure.
+//
+//	func TestSettingsColumn(t *testing.T) {
+//		jsonsqltest.RoundTrip(t, Settings{Theme: "dark"})
+//	}
+package jsonsqltest
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/jinford/jsonsql"
+)
+
+// RoundTrip asserts that v survives jsonsql.Value[T] and jsonsql.Nullable[T] unchanged:
+// scanning what Value writes must give a value equal to v according to reflect.DeepEqual.
+// Options registered for T with jsonsql.Configure apply.
+//
+// Values that encoding/json does not reproduce exactly, such as nil vs. empty slices under
+// omitempty or times with monotonic clock readings, fail; use RoundTripFunc to compare them
+// with a custom equality function.
+func RoundTrip[T any](t testing.TB, v T) {
+	t.Helper()
+	RoundTripFunc(t, v, func(a, b T) bool { return reflect.DeepEqual(a, b) })
+}
+
+// RoundTripFunc is like RoundTrip but compares values with equal.
+func RoundTripFunc[T any](t testing.TB, v T, equal func(want, got T) bool) {
+	t.Helper()
+
+	data, err := jsonsql.NewValue(v).Value()
+	if err != nil {
+		t.Errorf("jsonsql.Value[%s].Value failed for %#v: %v", typeName[T](), v, err)
+		return
+	}
+	var scanned jsonsql.Value[T]
+	if err := scanned.Scan(data); err != nil {
+		t.Errorf("jsonsql.Value[%s].Scan failed for %s: %v", typeName[T](), data, err)
+		return
+	}
+	if !equal(v, scanned.V) {
+		t.Errorf("jsonsql.Value[%s] round trip through %s:\nwant %#v\n got %#v", typeName[T](), data, v, scanned.V)
+	}
+
+	ndata, err := jsonsql.NullableFrom(v).Value()
+	if err != nil {
+		t.Errorf("jsonsql.Nullable[%s].Value failed for %#v: %v", typeName[T](), v, err)
+		return
+	}
+	var nullable jsonsql.Nullable[T]
+	if err := nullable.Scan(ndata); err != nil {
+		t.Errorf("jsonsql.Nullable[%s].Scan failed for %s: %v", typeName[T](), ndata, err)
+		return
+	}
+	if !nullable.Valid {
+		t.Errorf("jsonsql.Nullable[%s] round trip through %s: got NULL", typeName[T](), ndata)
+		return
+	}
+	if !equal(v, nullable.V) {
+		t.Errorf("jsonsql.Nullable[%s] round trip through %s:\nwant %#v\n got %#v", typeName[T](), ndata, v, nullable.V)
+	}
+}
+
+// RoundTripRandom runs RoundTrip for n values generated by gen from a deterministic source.
+// If gen is nil, values are generated with testing/quick, which supports only types whose
+// fields are all exported (so not time.Time, for example).
+func RoundTripRandom[T any](t testing.TB, n int, gen func(r *rand.Rand) T) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	if gen == nil {
+		gen = func(r *rand.Rand) T {
+			v, ok := quick.Value(reflect.TypeFor[T](), r)
+			if !ok {
+				t.Fatalf("jsonsqltest: testing/quick cannot generate values of %s; pass a generator", typeName[T]())
+			}
+			return v.Interface().(T)
+		}
+	}
+	for range n {
+		RoundTrip(t, gen(r))
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func typeName[T any]() string {
+	return reflect.TypeFor[T]().String()
+}
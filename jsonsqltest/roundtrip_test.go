@@ -0,0 +1,40 @@
+package jsonsqltest
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type testOrder struct {
+	ID    int64             `json:"id"`
+	Items []string          `json:"items"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+type testEvent struct {
+	At   time.Time `json:"at"`
+	Kind string    `json:"kind"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	RoundTrip(t, testOrder{ID: 1, Items: []string{"a"}, Attrs: map[string]string{"k": "v"}})
+}
+
+func TestRoundTrip_ReportsLoss(t *testing.T) {
+	type lossy struct {
+		Items []string `json:"items,omitempty"`
+	}
+	ft := &testing.T{}
+	RoundTrip(ft, lossy{Items: []string{}})
+	if !ft.Failed() {
+		t.Error("expected failure for empty slice decoded as nil")
+	}
+}
+
+func TestRoundTripRandom(t *testing.T) {
+	RoundTripRandom[testOrder](t, 50, nil)
+	RoundTripRandom(t, 50, func(r *rand.Rand) testEvent {
+		return testEvent{At: time.Unix(r.Int63n(1<<32), 0).UTC(), Kind: "k"}
+	})
+}
@@ -0,0 +1,119 @@
+package jsonsqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+// Source is a value as handed to sql.Scanner by a database driver.
+type Source struct {
+	// Name describes the representation, e.g. "[]byte" or "*string".
+	Name string
+	// Src is the value passed to Scan.
+	Src any
+}
+
+// Sources returns data in the representations produced by common drivers and wrappers:
+// []byte, string, json.RawMessage, sql.RawBytes, *[]byte and *string.
+func Sources(data string) []Source {
+	b := []byte(data)
+	s := data
+	return []Source{
+		{Name: "[]byte", Src: []byte(data)},
+		{Name: "string", Src: data},
+		{Name: "json.RawMessage", Src: json.RawMessage(data)},
+		{Name: "sql.RawBytes", Src: sql.RawBytes(data)},
+		{Name: "*[]byte", Src: &b},
+		{Name: "*string", Src: &s},
+	}
+}
+
+// NullSources returns the representations of NULL: SQL NULL as nil and nil pointers,
+// and the JSON literal null as bytes and strings with and without whitespace.
+func NullSources() []Source {
+	return []Source{
+		{Name: "nil", Src: nil},
+		{Name: "(*[]byte)(nil)", Src: (*[]byte)(nil)},
+		{Name: "(*string)(nil)", Src: (*string)(nil)},
+		{Name: "[]byte null", Src: []byte("null")},
+		{Name: "string null", Src: "null"},
+		{Name: "padded null", Src: " null\n"},
+	}
+}
+
+// Scanner constrains P to a pointer to S implementing sql.Scanner, such as *jsonsql.Value[T].
+type Scanner[S any] interface {
+	*S
+	sql.Scanner
+}
+
+// ScanAll scans data in every representation returned by Sources into a new S, failing the
+// test if any Scan fails or the results differ, and returns the scanned value:
+//
+//	v := jsonsqltest.ScanAll[jsonsql.Value[Settings]](t, `{"theme":"dark"}`)
+func ScanAll[S any, P Scanner[S]](t testing.TB, data string) S {
+	t.Helper()
+	var first S
+	for i, src := range Sources(data) {
+		var dest S
+		if err := P(&dest).Scan(src.Src); err != nil {
+			t.Errorf("Scan(%s) of %T failed: %v", src.Name, dest, err)
+			continue
+		}
+		if i == 0 {
+			first = dest
+		} else if !reflect.DeepEqual(first, dest) {
+			t.Errorf("Scan(%s) of %T differs from Scan([]byte):\nwant %#v\n got %#v", src.Name, dest, first, dest)
+		}
+	}
+	return first
+}
+
+// AssertNullNotAllowed asserts that scanning every NullSources value into a new S fails
+// with jsonsql.ErrNullNotAllowed, as for jsonsql.Value[T] and other NOT NULL wrappers.
+func AssertNullNotAllowed[S any, P Scanner[S]](t testing.TB) {
+	t.Helper()
+	for _, src := range NullSources() {
+		var dest S
+		if err := P(&dest).Scan(src.Src); !errors.Is(err, jsonsql.ErrNullNotAllowed) {
+			t.Errorf("Scan(%s) of %T: expected jsonsql.ErrNullNotAllowed, got %v", src.Name, dest, err)
+		}
+	}
+}
+
+// AssertNull asserts that every NullSources value scans into a new S without error and that
+// isNull reports the result as NULL, as for jsonsql.Nullable[T]:
+//
+//	jsonsqltest.AssertNull(t, func(n jsonsql.Nullable[Settings]) bool { return !n.Valid })
+func AssertNull[S any, P Scanner[S]](t testing.TB, isNull func(S) bool) {
+	t.Helper()
+	for _, src := range NullSources() {
+		var dest S
+		if err := P(&dest).Scan(src.Src); err != nil {
+			t.Errorf("Scan(%s) of %T failed: %v", src.Name, dest, err)
+			continue
+		}
+		if !isNull(dest) {
+			t.Errorf("Scan(%s) of %T: expected NULL, got %#v", src.Name, dest, dest)
+		}
+	}
+}
+
+// AssertValueNull asserts that v writes SQL NULL.
+func AssertValueNull(t testing.TB, v driver.Valuer) {
+	t.Helper()
+	value, err := v.Value()
+	if err != nil {
+		t.Errorf("Value of %T failed: %v", v, err)
+		return
+	}
+	if value != nil {
+		t.Errorf("Value of %T: expected NULL, got %v", v, value)
+	}
+}
@@ -0,0 +1,36 @@
+package jsonsqltest
+
+import (
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+func TestScanAll(t *testing.T) {
+	v := ScanAll[jsonsql.Value[testOrder]](t, `{"id":7,"items":["a"]}`)
+	if v.V.ID != 7 || len(v.V.Items) != 1 {
+		t.Errorf("unexpected result: %+v", v.V)
+	}
+}
+
+func TestAssertNull(t *testing.T) {
+	AssertNullNotAllowed[jsonsql.Value[testOrder]](t)
+	AssertNull(t, func(n jsonsql.Nullable[testOrder]) bool { return !n.Valid })
+	AssertValueNull(t, jsonsql.Nullable[testOrder]{})
+}
+
+func TestGolden(t *testing.T) {
+	order := testOrder{ID: 7, Items: []string{"a"}, Attrs: map[string]string{"k": "v"}}
+	Golden(t, jsonsql.NewValue(order), "testdata/order.json")
+
+	scanned := ScanGolden[jsonsql.Nullable[testOrder]](t, "testdata/order.json")
+	if !scanned.Valid || scanned.V.ID != 7 {
+		t.Errorf("unexpected result: %+v", scanned)
+	}
+
+	ft := &testing.T{}
+	Golden(ft, jsonsql.NewValue(testOrder{ID: 8}), "testdata/order.json")
+	if !ft.Failed() {
+		t.Error("expected mismatch to fail")
+	}
+}
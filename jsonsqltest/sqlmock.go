@@ -0,0 +1,166 @@
+package jsonsqltest
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONArg matches query arguments holding a JSON document by semantic equality, ignoring
+// key order and whitespace. It has the method set of go-sqlmock's sqlmock.Argument, so it can
+// be passed to WithArgs without this package depending on go-sqlmock. Build one with JSONEq
+// or ValueArg:
+//
+//	mock.ExpectExec("INSERT INTO users").
+//		WithArgs(jsonsqltest.Args(42, jsonsql.NewValue(profile))...).
+//		WillReturnResult(sqlmock.NewResult(1, 1))
+type JSONArg struct {
+	// want is the expected document, or nil for SQL NULL.
+	want []byte
+	// err is the error building the expectation; such a JSONArg matches nothing.
+	err error
+}
+
+// ValueArg returns a JSONArg matching the driver value written by v, such as a jsonsql.Value[T]
+// or jsonsql.Nullable[T] built from the expected value, with the options registered for T.
+// Values that are not JSON documents, such as those of binary codecs, must match byte for byte.
+func ValueArg(v driver.Valuer) JSONArg {
+	value, err := v.Value()
+	if err != nil {
+		return JSONArg{err: fmt.Errorf("Value of %T failed: %w", v, err)}
+	}
+	want, ok := documentBytes(value)
+	if !ok {
+		return JSONArg{err: fmt.Errorf("Value of %T returned %T, expected JSON bytes or NULL", v, value)}
+	}
+	return JSONArg{want: want}
+}
+
+// JSONEq returns a JSONArg matching documents semantically equal to expected, so tests asserting
+// INSERT arguments do not break on formatting:
+//
+//	mock.ExpectExec("INSERT INTO users").
+//		WithArgs(42, jsonsqltest.JSONEq(`{"name": "Alice", "tags": ["admin"]}`))
+//
+// A string, []byte or json.RawMessage is taken as JSON text, a driver.Valuer as in ValueArg,
+// and nil as SQL NULL; other values are encoded with encoding/json. Since the document is
+// encoded without the options registered with jsonsql, use ValueArg for types relying on them.
+func JSONEq(expected any) JSONArg {
+	var want []byte
+	switch e := expected.(type) {
+	case nil:
+		return JSONArg{}
+	case driver.Valuer:
+		return ValueArg(e)
+	case string:
+		want = []byte(e)
+	case []byte:
+		want = e
+	case json.RawMessage:
+		want = e
+	default:
+		data, err := json.Marshal(expected)
+		if err != nil {
+			return JSONArg{err: err}
+		}
+		want = data
+	}
+	if _, err := decodeAny(want); err != nil {
+		return JSONArg{err: fmt.Errorf("invalid expected JSON %q: %w", want, err)}
+	}
+	return JSONArg{want: want}
+}
+
+// Args returns args for WithArgs, replacing every driver.Valuer with its ValueArg.
+// Other arguments, such as plain IDs, are returned unchanged.
+func Args(args ...any) []driver.Value {
+	out := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if v, ok := arg.(driver.Valuer); ok {
+			out[i] = ValueArg(v)
+		} else {
+			out[i] = arg
+		}
+	}
+	return out
+}
+
+// Match reports whether the driver value v holds the expected document.
+func (a JSONArg) Match(v driver.Value) bool {
+	if a.err != nil {
+		return false
+	}
+	got, ok := documentBytes(v)
+	if !ok {
+		return false
+	}
+	if a.want == nil || got == nil {
+		return a.want == nil && got == nil
+	}
+	return jsonEqual(a.want, got)
+}
+
+// Value implements driver.Valuer interface, returning the expected document, so a JSONArg can
+// also stand in for the argument itself where mocks or recorders compare values.
+func (a JSONArg) Value() (driver.Value, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	if a.want == nil {
+		return nil, nil
+	}
+	return a.want, nil
+}
+
+// String describes the expected document in mismatch reports.
+func (a JSONArg) String() string {
+	switch {
+	case a.err != nil:
+		return "invalid JSON expectation: " + a.err.Error()
+	case a.want == nil:
+		return "NULL"
+	}
+	return "JSON " + string(a.want)
+}
+
+// documentBytes returns the document held by the driver value v, or nil for SQL NULL.
+func documentBytes(v driver.Value) ([]byte, bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, true
+	case []byte:
+		return v, true
+	case json.RawMessage:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
+
+// jsonEqual reports whether a and b are equal JSON documents, or equal bytes if either is
+// not valid JSON.
+func jsonEqual(a, b []byte) bool {
+	va, erra := decodeAny(a)
+	vb, errb := decodeAny(b)
+	if erra != nil || errb != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// decodeAny decodes data into an untyped value, keeping numbers exact.
+func decodeAny(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return v, nil
+}
@@ -0,0 +1,116 @@
+package jsonsqltest
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jinford/jsonsql"
+)
+
+// argument is the interface of go-sqlmock's sqlmock.Argument.
+type argument interface {
+	Match(driver.Value) bool
+}
+
+var _ argument = JSONArg{}
+
+func TestValueArg(t *testing.T) {
+	order := testOrder{ID: 7, Items: []string{"a"}, Attrs: map[string]string{"k": "v", "x": "y"}}
+	arg := ValueArg(jsonsql.NewValue(order))
+
+	for _, v := range []driver.Value{
+		[]byte(`{"attrs":{"x":"y","k":"v"},"items":["a"],"id":7}`),
+		"{\n  \"id\": 7,\n  \"items\": [\"a\"],\n  \"attrs\": {\"k\": \"v\", \"x\": \"y\"}\n}",
+		json.RawMessage(`{"id":7,"items":["a"],"attrs":{"k":"v","x":"y"}}`),
+	} {
+		if !arg.Match(v) {
+			t.Errorf("expected %s to match %v", v, arg)
+		}
+	}
+	for _, v := range []driver.Value{
+		[]byte(`{"id":8,"items":["a"],"attrs":{"k":"v","x":"y"}}`),
+		[]byte(`{"id":7,"items":["a"]}`),
+		nil,
+		int64(7),
+	} {
+		if arg.Match(v) {
+			t.Errorf("expected %v not to match %v", v, arg)
+		}
+	}
+
+	null := ValueArg(jsonsql.Nullable[testOrder]{})
+	if !null.Match(nil) || null.Match([]byte("null")) {
+		t.Errorf("expected NULL expectation to match only nil")
+	}
+	if null.String() != "NULL" {
+		t.Errorf("unexpected description %q", null.String())
+	}
+}
+
+func TestValueArg_Error(t *testing.T) {
+	arg := ValueArg(jsonsql.NewValue(map[string]any{"f": func() {}}))
+	if arg.Match([]byte(`{}`)) {
+		t.Error("expected failed expectation to match nothing")
+	}
+	if !strings.HasPrefix(arg.String(), "invalid JSON expectation") {
+		t.Errorf("unexpected description %q", arg.String())
+	}
+}
+
+func TestArgs(t *testing.T) {
+	args := Args(int64(42), jsonsql.NewValue(testOrder{ID: 1}), "name")
+	if len(args) != 3 || args[0] != int64(42) || args[2] != "name" {
+		t.Fatalf("unexpected args %v", args)
+	}
+	arg, ok := args[1].(argument)
+	if !ok {
+		t.Fatalf("expected argument matcher, got %T", args[1])
+	}
+	if !arg.Match([]byte(`{"items":null,"id":1,"attrs":null}`)) {
+		t.Errorf("expected match for %v", arg)
+	}
+}
+
+var _ driver.Valuer = JSONArg{}
+
+func TestJSONEq(t *testing.T) {
+	doc := []byte(`{"name":"Alice","tags":["admin"],"n":1}`)
+	for _, expected := range []any{
+		`{"tags": ["admin"], "n": 1, "name": "Alice"}`,
+		[]byte(`{"n":1,"name":"Alice","tags":["admin"]}`),
+		json.RawMessage(doc),
+		map[string]any{"name": "Alice", "tags": []string{"admin"}, "n": 1},
+		jsonsql.NewValue(map[string]any{"name": "Alice", "tags": []string{"admin"}, "n": 1}),
+	} {
+		arg := JSONEq(expected)
+		if !arg.Match(doc) {
+			t.Errorf("JSONEq(%v): expected %s to match %v", expected, doc, arg)
+		}
+		if arg.Match([]byte(`{"name":"Alice","tags":["user"],"n":1}`)) {
+			t.Errorf("JSONEq(%v): unexpected match", expected)
+		}
+	}
+
+	if arg := JSONEq(`{"n":1.0}`); arg.Match([]byte(`{"n":1}`)) {
+		t.Error("expected numbers to compare by their text")
+	}
+	if arg := JSONEq(nil); !arg.Match(nil) || arg.Match([]byte("null")) {
+		t.Error("expected JSONEq(nil) to match only NULL")
+	}
+	if arg := JSONEq(`{"n":`); arg.Match([]byte(`{"n":`)) || !strings.Contains(arg.String(), "invalid expected JSON") {
+		t.Errorf("expected invalid expectation, got %v", arg)
+	}
+
+	value, err := JSONEq(`{"a":1}`).Value()
+	if err != nil || string(value.([]byte)) != `{"a":1}` {
+		t.Errorf("unexpected Value %s, %v", value, err)
+	}
+	if value, err := JSONEq(nil).Value(); value != nil || err != nil {
+		t.Errorf("expected NULL, got %v, %v", value, err)
+	}
+	if _, err := JSONEq(`{`).Value(); err == nil {
+		t.Error("expected error for invalid expectation")
+	}
+}
@@ -0,0 +1,11 @@
+// Command jsonsqlvet reports misuses of the jsonsql wrappers. See package jsonsqlvet.
+package main
+
+import (
+	"github.com/jinford/jsonsql/jsonsqlvet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(jsonsqlvet.Analyzer)
+}
@@ -0,0 +1,177 @@
+// Package jsonsqlvet provides an analyzer reporting common misuses of the jsonsql wrappers
+// that otherwise only surface at runtime:
+//
+//   - passing a wrapper such as jsonsql.Nullable[T] by value to (*sql.Row).Scan or (*sql.Rows).Scan,
+//   - wrapping a pointer type, as in jsonsql.Nullable[*T],
+//   - nesting wrappers, as in jsonsql.Nullable[jsonsql.Nullable[T]],
+//   - reading the V field of a jsonsql.Nullable[T] in a function that never checks Valid.
+//
+// It lives in its own module so that golang.org/x/tools stays an optional dependency.
+// Run it standalone with the jsonsqlvet command or add Analyzer to a multichecker:
+//
+//	go run github.com/jinford/jsonsql/jsonsqlvet/cmd/jsonsqlvet ./...
+package jsonsqlvet
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const jsonsqlPath = "github.com/jinford/jsonsql"
+
+// Analyzer reports misuses of the jsonsql wrappers.
+var Analyzer = &analysis.Analyzer{
+	Name:     "jsonsqlvet",
+	Doc:      "report misuses of jsonsql wrappers: Scan destinations passed by value, Nullable[*T], nested Nullable and unchecked Valid",
+	URL:      "https://pkg.go.dev/github.com/jinford/jsonsql/jsonsqlvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// nullableWrappers are the jsonsql types whose contents are only meaningful when Valid is true.
+var nullableWrappers = map[string]bool{
+	"Nullable":        true,
+	"NullableArrayOf": true,
+	"NullableCbor":    true,
+	"NullableMsgpack": true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	checkInstances(pass)
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		checkScanCall(pass, n.(*ast.CallExpr))
+	})
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body != nil {
+			checkValid(pass, body)
+		}
+	})
+	return nil, nil
+}
+
+// wrapperName returns the name of the jsonsql type t is an instance of, or "".
+func wrapperName(t types.Type) string {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return ""
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != jsonsqlPath || named.TypeArgs().Len() == 0 {
+		return ""
+	}
+	return obj.Name()
+}
+
+// checkInstances reports Nullable[*T] and nested wrappers.
+func checkInstances(pass *analysis.Pass) {
+	for id, inst := range pass.TypesInfo.Instances {
+		obj, ok := pass.TypesInfo.Uses[id].(*types.TypeName)
+		if !ok || obj.Pkg() == nil || obj.Pkg().Path() != jsonsqlPath || inst.TypeArgs.Len() != 1 {
+			continue
+		}
+		arg := inst.TypeArgs.At(0)
+		if _, ok := types.Unalias(arg).(*types.TypeParam); ok {
+			continue
+		}
+		if nullableWrappers[obj.Name()] {
+			if _, ok := arg.Underlying().(*types.Pointer); ok {
+				pass.Reportf(id.Pos(), "jsonsql.%s[%s] is nullable twice; use jsonsql.%s[%s] and Valid instead of a nil pointer",
+					obj.Name(), types.TypeString(arg, types.RelativeTo(pass.Pkg)),
+					obj.Name(), types.TypeString(arg.Underlying().(*types.Pointer).Elem(), types.RelativeTo(pass.Pkg)))
+			}
+		}
+		if inner := wrapperName(arg); inner != "" {
+			pass.Reportf(id.Pos(), "jsonsql.%s wraps jsonsql.%s; nested wrappers encode the inner wrapper's fields, use a single wrapper",
+				obj.Name(), inner)
+		}
+	}
+}
+
+// checkScanCall reports jsonsql wrappers passed by value to (*sql.Row).Scan or (*sql.Rows).Scan.
+func checkScanCall(pass *analysis.Pass, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Scan" {
+		return
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "database/sql" {
+		return
+	}
+	for _, arg := range call.Args {
+		t := pass.TypesInfo.TypeOf(arg)
+		if t == nil {
+			continue
+		}
+		if name := wrapperName(t); name != "" {
+			pass.Reportf(arg.Pos(), "jsonsql.%s passed to Scan by value; pass a pointer (&%s)", name, types.ExprString(arg))
+		}
+	}
+}
+
+// checkValid reports reads of V on nullable wrappers in body when body never refers to
+// Valid or calls Get or ToPtr on the same expression.
+func checkValid(pass *analysis.Pass, body *ast.BlockStmt) {
+	type use struct {
+		sel *ast.SelectorExpr
+		key string
+	}
+	var (
+		reads   []use
+		writes  = map[*ast.SelectorExpr]bool{}
+		checked = map[string]bool{}
+	)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// Function literals are checked on their own.
+			return false
+		case *ast.AssignStmt:
+			// Assigning to V is not a read.
+			for _, lhs := range n.Lhs {
+				if sel, ok := lhs.(*ast.SelectorExpr); ok {
+					writes[sel] = true
+				}
+			}
+		case *ast.SelectorExpr:
+			if writes[n] {
+				return true
+			}
+			t := pass.TypesInfo.TypeOf(n.X)
+			if t == nil {
+				return true
+			}
+			if p, ok := t.Underlying().(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if !nullableWrappers[wrapperName(t)] {
+				return true
+			}
+			key := types.ExprString(n.X)
+			switch n.Sel.Name {
+			case "V":
+				reads = append(reads, use{sel: n, key: key})
+			case "Valid", "Get", "ToPtr":
+				checked[key] = true
+			}
+		}
+		return true
+	})
+	for _, r := range reads {
+		if !checked[r.key] {
+			pass.Reportf(r.sel.Sel.Pos(), "%s.V used without checking %s.Valid", r.key, r.key)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package a
+
+import (
+	"database/sql"
+
+	"github.com/jinford/jsonsql"
+)
+
+type profile struct {
+	Name string
+}
+
+func scanByValue(row *sql.Row, rows *sql.Rows) {
+	var v jsonsql.Value[profile]
+	var n jsonsql.Nullable[profile]
+	_ = row.Scan(v)      // want `jsonsql.Value passed to Scan by value; pass a pointer \(&v\)`
+	_ = rows.Scan(&v, n) // want `jsonsql.Nullable passed to Scan by value; pass a pointer \(&n\)`
+	_ = row.Scan(&v, &n)
+	_ = n.Valid
+}
+
+var pointer jsonsql.Nullable[*profile] // want `jsonsql.Nullable\[\*profile\] is nullable twice; use jsonsql.Nullable\[profile\] and Valid instead of a nil pointer`
+
+var nested jsonsql.Nullable[jsonsql.Value[profile]] // want `jsonsql.Nullable wraps jsonsql.Value; nested wrappers encode the inner wrapper's fields, use a single wrapper`
+
+var valuePointer jsonsql.Value[*profile]
+
+func unchecked(n jsonsql.Nullable[profile]) string {
+	return n.V.Name // want `n.V used without checking n.Valid`
+}
+
+func checked(n jsonsql.Nullable[profile]) string {
+	if !n.Valid {
+		return ""
+	}
+	return n.V.Name
+}
+
+func checkedWithGet(n *jsonsql.Nullable[profile]) string {
+	if _, ok := n.Get(); ok {
+		return n.V.Name
+	}
+	return ""
+}
+
+func write(n *jsonsql.Nullable[profile]) {
+	n.V = profile{Name: "a"}
+	n.Valid = true
+}
+
+func generic[T any](n jsonsql.Nullable[T]) jsonsql.Nullable[T] {
+	return n
+}
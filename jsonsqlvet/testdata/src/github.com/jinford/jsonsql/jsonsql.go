@@ -0,0 +1,17 @@
+// Package jsonsql is a stub of the jsonsql wrappers for the analyzer tests.
+package jsonsql
+
+type Value[T any] struct {
+	V T
+}
+
+func (v *Value[T]) Scan(src any) error { return nil }
+
+type Nullable[T any] struct {
+	V     T
+	Valid bool
+}
+
+func (n *Nullable[T]) Scan(src any) error { return nil }
+
+func (n Nullable[T]) Get() (T, bool) { return n.V, n.Valid }
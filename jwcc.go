@@ -0,0 +1,93 @@
+package jsonsql
+
+import (
+	"bytes"
+	"errors"
+)
+
+// errUnterminatedComment is returned when a block comment is not closed.
+var errUnterminatedComment = errors.New("jsonsql: unterminated block comment")
+
+// Lenient makes Scan accept JWCC (JSON with commas and comments): // and /* */ comments
+// and trailing commas in objects and arrays. The data is standardized to plain JSON before
+// decoding, like hujson.Standardize. Strict JSON is the default.
+func Lenient(enabled bool) Option {
+	return func(c *config) {
+		c.lenient = enabled
+	}
+}
+
+// standardize converts JWCC data to standard JSON by replacing comments and trailing commas
+// with whitespace, so byte offsets in later decode errors still point into the original data.
+func standardize(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	// Pass 1: blank out comments.
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, errUnterminatedComment
+			}
+			end += i + 2 + len("*/")
+			for ; i < end; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+
+	// Pass 2: blank out trailing commas.
+	inString = false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case ',':
+			j := i + 1
+			for j < len(out) && isJSONSpace(out[j]) {
+				j++
+			}
+			if j < len(out) && (out[j] == '}' || out[j] == ']') {
+				out[i] = ' '
+			}
+		}
+	}
+	return out, nil
+}
+
+// isJSONSpace reports whether c is insignificant whitespace in JSON.
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,77 @@
+package jsonsql
+
+import "testing"
+
+func TestLenient_Scan(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](Lenient(true))
+
+	input := `{
+		// line comment with "quotes"
+		"url": "http://example.com/*not-a-comment*/", /* block
+		comment */
+		"list": [1, 2, 3,],
+		"nested": {"a": "trailing,",},
+	}`
+
+	var v Value[map[string]any]
+	if err := v.Scan(input); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if v.V["url"] != "http://example.com/*not-a-comment*/" {
+		t.Errorf("unexpected url: %v", v.V["url"])
+	}
+	if len(v.V["list"].([]any)) != 3 {
+		t.Errorf("unexpected list: %v", v.V["list"])
+	}
+	if v.V["nested"].(map[string]any)["a"] != "trailing," {
+		t.Errorf("unexpected nested: %v", v.V["nested"])
+	}
+}
+
+func TestLenient_NullWithComment(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](Lenient(true))
+
+	var n Nullable[map[string]any]
+	if err := n.Scan("null // nothing here"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.Valid {
+		t.Error("expected Valid=false")
+	}
+}
+
+func TestLenient_UnterminatedComment(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](Lenient(true))
+
+	var v Value[map[string]any]
+	if err := v.Scan(`{"a":1} /* open`); err == nil {
+		t.Fatal("expected error for unterminated comment")
+	}
+}
+
+func TestLenient_DisabledByDefault(t *testing.T) {
+	var v Value[map[string]any]
+	if err := v.Scan(`{"a":1,}`); err == nil {
+		t.Fatal("expected error for trailing comma in strict mode")
+	}
+}
+
+func TestStandardize_PreservesOffsets(t *testing.T) {
+	input := []byte("[1, /* c */ 2,]")
+
+	out, err := standardize(input)
+	if err != nil {
+		t.Fatalf("standardize failed: %v", err)
+	}
+
+	if len(out) != len(input) {
+		t.Errorf("expected length %d, got %d", len(input), len(out))
+	}
+	if string(out) != "[1,         2 ]" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
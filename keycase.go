@@ -0,0 +1,149 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyStyle is a naming convention for object keys.
+type KeyStyle int
+
+const (
+	// KeysAsIs leaves keys unchanged (default).
+	KeysAsIs KeyStyle = iota
+	// SnakeCase writes keys as snake_case, e.g. user_id.
+	SnakeCase
+	// CamelCase writes keys as lowerCamelCase, e.g. userId.
+	CamelCase
+)
+
+// KeyCase transforms the object keys of documents between the style used in the database
+// and the style of the Go types, so columns written by another application (such as snake_case
+// keys from a Rails app) can be consumed without duplicating every json tag.
+// Value converts all keys to stored, and Scan converts all keys to local before decoding:
+//
+//	// Stored {"user_id":1}; Go struct tagged `json:"userId"` or untagged UserID.
+//	jsonsql.Configure[Profile](jsonsql.KeyCase(jsonsql.SnakeCase, jsonsql.CamelCase))
+//
+// Untagged fields match either way, as encoding/json matches keys case-insensitively.
+// Keys of maps are transformed too. Keys given to KeyAlias must be in the local style.
+func KeyCase(stored, local KeyStyle) Option {
+	return func(c *config) {
+		c.storedKeys = stored
+		c.localKeys = local
+	}
+}
+
+// transformKeys rewrites every object key in the JSON document data to style.
+// Everything else is copied unchanged; invalid documents are left for the decoder to reject.
+func transformKeys(data []byte, style KeyStyle) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		if data[i] != '"' {
+			out = append(out, data[i])
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(data) && data[end] != '"' {
+			if data[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(data) {
+			return append(out, data[i:]...)
+		}
+		end++
+		str := data[i:end]
+		i = end
+
+		j := i
+		for j < len(data) && isJSONSpace(data[j]) {
+			j++
+		}
+		if j == len(data) || data[j] != ':' {
+			out = append(out, str...)
+			continue
+		}
+
+		var key string
+		if err := json.Unmarshal(str, &key); err != nil {
+			out = append(out, str...)
+			continue
+		}
+		converted := convertKey(key, style)
+		if converted == key {
+			out = append(out, str...)
+			continue
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(converted)
+		out = append(out, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+	}
+	return out
+}
+
+// convertKey converts key to style.
+func convertKey(key string, style KeyStyle) string {
+	switch style {
+	case SnakeCase:
+		return toSnakeCase(key)
+	case CamelCase:
+		return toCamelCase(key)
+	}
+	return key
+}
+
+// toSnakeCase converts camelCase, PascalCase and kebab-case keys to snake_case.
+// Acronyms are kept together: userID and HTTPServer become user_id and http_server.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case r == '-':
+			b.WriteByte('_')
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// toCamelCase converts snake_case, kebab-case and PascalCase keys to lowerCamelCase.
+func toCamelCase(s string) string {
+	if s == "" {
+		return s
+	}
+	if !strings.ContainsAny(s, "_-") {
+		r, size := utf8.DecodeRuneInString(s)
+		return string(unicode.ToLower(r)) + s[size:]
+	}
+	var b strings.Builder
+	upper := false
+	for _, r := range s {
+		switch {
+		case r == '_' || r == '-':
+			upper = b.Len() > 0
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
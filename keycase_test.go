@@ -0,0 +1,70 @@
+package jsonsql
+
+import "testing"
+
+type testRailsUser struct {
+	UserID    int               `json:"userId"`
+	FirstName string            `json:"firstName"`
+	Tags      map[string]string `json:"tags"`
+}
+
+func TestKeyCase_RoundTrip(t *testing.T) {
+	resetOptions[testRailsUser](t)
+	Configure[testRailsUser](KeyCase(SnakeCase, CamelCase))
+
+	var v Value[testRailsUser]
+	if err := v.Scan(`{"user_id":7,"first_name":"Ann","tags":{"created_by":"rails"}}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.UserID != 7 || v.V.FirstName != "Ann" || v.V.Tags["createdBy"] != "rails" {
+		t.Errorf("unexpected result: %+v", v.V)
+	}
+
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"user_id":7,"first_name":"Ann","tags":{"created_by":"rails"}}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestKeyCase_StringValuesUnchanged(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](KeyCase(SnakeCase, KeysAsIs))
+
+	result, err := NewValue(map[string]any{"noteText": "keep_this:Value", "list": []any{"fooBar"}}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"list":["fooBar"],"note_text":"keep_this:Value"}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestConvertKey(t *testing.T) {
+	tests := []struct {
+		in    string
+		style KeyStyle
+		want  string
+	}{
+		{"userID", SnakeCase, "user_id"},
+		{"HTTPServer", SnakeCase, "http_server"},
+		{"line2Total", SnakeCase, "line2_total"},
+		{"already_snake", SnakeCase, "already_snake"},
+		{"kebab-case", SnakeCase, "kebab_case"},
+		{"user_id", CamelCase, "userId"},
+		{"_private_key", CamelCase, "privateKey"},
+		{"UserName", CamelCase, "userName"},
+		{"kebab-case", CamelCase, "kebabCase"},
+		{"", CamelCase, ""},
+		{"AnyKey", KeysAsIs, "AnyKey"},
+	}
+	for _, tt := range tests {
+		if got := convertKey(tt.in, tt.style); got != tt.want {
+			t.Errorf("convertKey(%q, %d) = %q, want %q", tt.in, tt.style, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,581 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Msgpack[struct{}])(nil)
+	_ ContextScanner = (*Msgpack[struct{}])(nil)
+	_ driver.Valuer  = Msgpack[struct{}]{}
+	_ sql.Scanner    = (*NullableMsgpack[struct{}])(nil)
+	_ ContextScanner = (*NullableMsgpack[struct{}])(nil)
+	_ driver.Valuer  = NullableMsgpack[struct{}]{}
+)
+
+// Msgpack[T] is a generic type for NOT NULL BLOB/bytea columns holding MessagePack documents.
+// It has the same API and null semantics as Value[T], so call sites do not change when a table
+// switches from JSON text to msgpack blobs. Documents go through the same checks as Value[T]:
+// MaxDocumentSize, ValidateValues and the observability hooks apply.
+//
+// The built-in encoder converts through the JSON data model, so json struct tags and all options
+// apply unchanged. Integers beyond 64 bits are stored as strings, as MessagePack has no bignum type.
+// A third-party MessagePack library can be plugged in with MsgpackCodec.
+type Msgpack[T any] struct {
+	V T
+}
+
+// NewMsgpack creates a new Msgpack[T] with the given value.
+func NewMsgpack[T any](v T) Msgpack[T] {
+	return Msgpack[T]{V: v}
+}
+
+// Get returns the value.
+func (m Msgpack[T]) Get() T {
+	return m.V
+}
+
+// Set replaces the value with x.
+func (m *Msgpack[T]) Set(x T) {
+	m.V = x
+}
+
+// Replace replaces the value with the result of calling f with the current value.
+func (m *Msgpack[T]) Replace(f func(T) T) {
+	m.V = f(m.V)
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes MessagePack data from the database into V.
+// Returns ErrNullNotAllowed if src is nil or the MessagePack nil value (NOT NULL constraint violation),
+// and ErrEmptyInput for empty input unless another EmptyPolicy is configured.
+func (m *Msgpack[T]) Scan(src any) error {
+	return m.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (m *Msgpack[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeBinarySource(ctx, src, &m.V, msgpackFormat, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Msgpack.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It encodes V to MessagePack bytes for database storage.
+func (m Msgpack[T]) Value() (driver.Value, error) {
+	data, err := encodeBinary(m.V, msgpackFormat)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Msgpack.Value: %w", err)
+	}
+	return data, nil
+}
+
+// NullableMsgpack[T] is a generic type for NULL-able BLOB/bytea columns holding MessagePack documents.
+// It has the same API and null semantics as Nullable[T], except that NULL is always stored as
+// SQL NULL: NullAsJSON and NullAsEmptyObject do not apply.
+type NullableMsgpack[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNullableMsgpack creates a new NullableMsgpack[T] with the given value and valid flag.
+// If valid is false, V is set to the zero value of T.
+func NewNullableMsgpack[T any](v T, valid bool) NullableMsgpack[T] {
+	if !valid {
+		return NullMsgpack[T]()
+	}
+	return NullableMsgpackFrom(v)
+}
+
+// NullableMsgpackFrom creates a new NullableMsgpack[T] with Valid=true and the given value.
+func NullableMsgpackFrom[T any](v T) NullableMsgpack[T] {
+	return NullableMsgpack[T]{V: v, Valid: true}
+}
+
+// NullMsgpack creates a new NullableMsgpack[T] with Valid=false (represents NULL).
+func NullMsgpack[T any]() NullableMsgpack[T] {
+	return NullableMsgpack[T]{Valid: false}
+}
+
+// NullableMsgpackFromPtr creates a NullableMsgpack[T] from a pointer.
+// Returns NullMsgpack[T]() if ptr is nil.
+func NullableMsgpackFromPtr[T any](ptr *T) NullableMsgpack[T] {
+	if ptr == nil {
+		return NullMsgpack[T]()
+	}
+	return NullableMsgpackFrom(*ptr)
+}
+
+// ToPtr returns a pointer to the value if Valid is true, otherwise nil.
+func (n NullableMsgpack[T]) ToPtr() *T {
+	if !n.Valid {
+		return nil
+	}
+	return &n.V
+}
+
+// Get returns the value and a boolean indicating whether it is valid.
+func (n NullableMsgpack[T]) Get() (T, bool) {
+	return n.V, n.Valid
+}
+
+// TryGet returns the value, or ErrNullNotAllowed when n is NULL.
+func (n NullableMsgpack[T]) TryGet() (T, error) {
+	if !n.Valid {
+		var zero T
+		return zero, ErrNullNotAllowed
+	}
+	return n.V, nil
+}
+
+// Set replaces the value with x and sets Valid=true.
+func (n *NullableMsgpack[T]) Set(x T) {
+	n.V = x
+	n.Valid = true
+}
+
+// SetNull resets n to NULL (Valid=false, V=zero value).
+func (n *NullableMsgpack[T]) SetNull() {
+	var zero T
+	n.V = zero
+	n.Valid = false
+}
+
+// Replace replaces the value with the result of calling f with the current value and sets Valid=true.
+// When n is NULL, f receives the zero value of T.
+func (n *NullableMsgpack[T]) Replace(f func(T) T) {
+	n.Set(f(n.V))
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes MessagePack data from the database into V.
+// Sets Valid=false for nil, empty input, or the MessagePack nil value.
+func (n *NullableMsgpack[T]) Scan(src any) error {
+	return n.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (n *NullableMsgpack[T]) ScanContext(ctx context.Context, src any) error {
+	if err := checkNullableParam[T](); err != nil {
+		return fmt.Errorf("jsonsql.NullableMsgpack.Scan: %w", err)
+	}
+	null, err := decodeBinarySource(ctx, src, &n.V, msgpackFormat, NullOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.NullableMsgpack.Scan: %w", err)
+	}
+	if null {
+		n.SetNull()
+		return nil
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// Returns nil (NULL) when Valid is false.
+// Otherwise encodes V to MessagePack bytes.
+func (n NullableMsgpack[T]) Value() (driver.Value, error) {
+	if err := checkNullableParam[T](); err != nil {
+		return nil, fmt.Errorf("jsonsql.NullableMsgpack.Value: %w", err)
+	}
+	if !n.Valid {
+		reportNull[T](configFor[T](), nil)
+		return nil, nil
+	}
+	data, err := encodeBinary(n.V, msgpackFormat)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.NullableMsgpack.Value: %w", err)
+	}
+	return data, nil
+}
+
+// MsgpackCodec replaces the built-in MessagePack encoder used by Msgpack and NullableMsgpack,
+// for example with vmihailenco/msgpack. Passing nil restores the built-in one.
+// A plugged codec receives V directly, so JSON options do not apply to it.
+func MsgpackCodec(codec Codec) Option {
+	return func(c *config) {
+		c.msgpackCodec = codec
+	}
+}
+
+var msgpackFormat = &binaryFormat{
+	name:     "MessagePack",
+	null:     []byte{0xc0},
+	fromJSON: jsonToMsgpack,
+	toJSON:   msgpackToJSON,
+	codec:    func(c *config) Codec { return c.msgpackCodec },
+}
+
+// jsonToMsgpack converts a JSON document to MessagePack, keeping the order of object keys.
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	node, err := parseJSONTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendMsgpack(nil, node)
+}
+
+func appendMsgpack(out []byte, node jsonNode) ([]byte, error) {
+	switch node.kind {
+	case 'n':
+		return append(out, 0xc0), nil
+	case 'b':
+		if node.bool {
+			return append(out, 0xc3), nil
+		}
+		return append(out, 0xc2), nil
+	case '#':
+		return appendMsgpackNumber(out, node.num)
+	case 's':
+		return appendMsgpackString(out, node.str), nil
+	case '[':
+		out = appendMsgpackHead(out, 0x90, 0xdc, uint32(len(node.elems)))
+	case '{':
+		out = appendMsgpackHead(out, 0x80, 0xde, uint32(len(node.elems)))
+	}
+
+	var err error
+	for i, elem := range node.elems {
+		if node.kind == '{' {
+			out = appendMsgpackString(out, node.keys[i])
+		}
+		if out, err = appendMsgpack(out, elem); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// appendMsgpackNumber encodes integers in the smallest integer format and all other numbers as float64.
+func appendMsgpackNumber(out []byte, num json.Number) ([]byte, error) {
+	s := num.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return appendMsgpackInt(out, i), nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return binary.BigEndian.AppendUint64(append(out, 0xcf), u), nil
+		}
+		return appendMsgpackString(out, s), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return binary.BigEndian.AppendUint64(append(out, 0xcb), math.Float64bits(f)), nil
+}
+
+func appendMsgpackInt(out []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= math.MaxInt8:
+		return append(out, byte(i))
+	case i >= -32 && i < 0:
+		return append(out, byte(i))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		return append(out, 0xd0, byte(i))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(out, 0xd1), uint16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(out, 0xd2), uint32(i))
+	}
+	return binary.BigEndian.AppendUint64(append(out, 0xd3), uint64(i))
+}
+
+func appendMsgpackString(out []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		out = append(out, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		out = append(out, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		out = binary.BigEndian.AppendUint16(append(out, 0xda), uint16(n))
+	default:
+		out = binary.BigEndian.AppendUint32(append(out, 0xdb), uint32(n))
+	}
+	return append(out, s...)
+}
+
+// appendMsgpackHead writes an array or map header: fix is the fixarray/fixmap prefix
+// and code the 16-bit variant, followed by the 32-bit one.
+func appendMsgpackHead(out []byte, fix, code byte, n uint32) []byte {
+	switch {
+	case n < 16:
+		return append(out, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, code), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(out, code+1), n)
+}
+
+// msgpackToJSON converts a single MessagePack object to JSON.
+// Binary data becomes base64 strings, integer map keys become strings,
+// and timestamp extensions become RFC 3339 strings. Other extension types are rejected.
+func msgpackToJSON(data []byte) ([]byte, error) {
+	d := &msgpackDecoder{data: data}
+	if err := d.value(0); err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, ErrTrailingData
+	}
+	return d.out.Bytes(), nil
+}
+
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+	out  bytes.Buffer
+}
+
+// take returns the next n bytes of data.
+func (d *msgpackDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of data")
+	}
+	p := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return p, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *msgpackDecoder) uint(size int) (uint64, error) {
+	p, err := d.take(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(p[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(p)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(p)), nil
+	}
+	return binary.BigEndian.Uint64(p), nil
+}
+
+// int reads a big-endian signed integer of size bytes.
+func (d *msgpackDecoder) int(size int) (int64, error) {
+	u, err := d.uint(size)
+	if err != nil {
+		return 0, err
+	}
+	shift := 64 - 8*size
+	return int64(u<<shift) >> shift, nil
+}
+
+func (d *msgpackDecoder) value(depth int) error {
+	if depth > maxBinaryDepth {
+		return errBinaryTooDeep
+	}
+	p, err := d.take(1)
+	if err != nil {
+		return err
+	}
+	b := p[0]
+
+	switch {
+	case b <= 0x7f:
+		d.out.Write(strconv.AppendInt(nil, int64(b), 10))
+		return nil
+	case b >= 0xe0:
+		d.out.Write(strconv.AppendInt(nil, int64(int8(b)), 10))
+		return nil
+	case b <= 0x8f:
+		return d.mapBody(uint64(b&0x0f), depth)
+	case b <= 0x9f:
+		return d.arrayBody(uint64(b&0x0f), depth)
+	case b <= 0xbf:
+		return d.str(uint64(b & 0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		d.out.WriteString("null")
+	case 0xc2:
+		d.out.WriteString("false")
+	case 0xc3:
+		d.out.WriteString("true")
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (b - 0xc4))
+		if err != nil {
+			return err
+		}
+		bin, err := d.take(n)
+		if err != nil {
+			return err
+		}
+		enc, _ := json.Marshal(bin)
+		d.out.Write(enc)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (b - 0xc7))
+		if err != nil {
+			return err
+		}
+		return d.ext(n)
+	case 0xca:
+		u, err := d.uint(4)
+		if err != nil {
+			return err
+		}
+		return writeJSONFloat(&d.out, float64(math.Float32frombits(uint32(u))))
+	case 0xcb:
+		u, err := d.uint(8)
+		if err != nil {
+			return err
+		}
+		return writeJSONFloat(&d.out, math.Float64frombits(u))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (b - 0xcc))
+		if err != nil {
+			return err
+		}
+		d.out.Write(strconv.AppendUint(nil, u, 10))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		i, err := d.int(1 << (b - 0xd0))
+		if err != nil {
+			return err
+		}
+		d.out.Write(strconv.AppendInt(nil, i, 10))
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (b - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (b - 0xd9))
+		if err != nil {
+			return err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (b - 0xdc))
+		if err != nil {
+			return err
+		}
+		return d.arrayBody(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (b - 0xde))
+		if err != nil {
+			return err
+		}
+		return d.mapBody(n, depth)
+	default:
+		return fmt.Errorf("invalid type code 0x%02x", b)
+	}
+	return nil
+}
+
+func (d *msgpackDecoder) str(n uint64) error {
+	s, err := d.take(n)
+	if err != nil {
+		return err
+	}
+	enc, _ := json.Marshal(string(s))
+	d.out.Write(enc)
+	return nil
+}
+
+func (d *msgpackDecoder) arrayBody(n uint64, depth int) error {
+	if n > uint64(len(d.data)-d.pos) {
+		return errors.New("unexpected end of data")
+	}
+	d.out.WriteByte('[')
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			d.out.WriteByte(',')
+		}
+		if err := d.value(depth + 1); err != nil {
+			return err
+		}
+	}
+	d.out.WriteByte(']')
+	return nil
+}
+
+func (d *msgpackDecoder) mapBody(n uint64, depth int) error {
+	if n > uint64(len(d.data)-d.pos)/2 {
+		return errors.New("unexpected end of data")
+	}
+	d.out.WriteByte('{')
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			d.out.WriteByte(',')
+		}
+		// Keys are decoded like values and must come out as strings or integers.
+		mark := d.out.Len()
+		if err := d.value(depth + 1); err != nil {
+			return err
+		}
+		key := d.out.Bytes()[mark:]
+		if key[0] != '"' {
+			if _, err := strconv.ParseInt(string(key), 10, 64); err != nil {
+				if _, err := strconv.ParseUint(string(key), 10, 64); err != nil {
+					return fmt.Errorf("unsupported map key %s", key)
+				}
+			}
+			quoted := strconv.Quote(string(key))
+			d.out.Truncate(mark)
+			d.out.WriteString(quoted)
+		}
+		d.out.WriteByte(':')
+		if err := d.value(depth + 1); err != nil {
+			return err
+		}
+	}
+	d.out.WriteByte('}')
+	return nil
+}
+
+// ext decodes an extension object with n bytes of data.
+// Only the timestamp extension (type -1) is supported.
+func (d *msgpackDecoder) ext(n uint64) error {
+	p, err := d.take(1)
+	if err != nil {
+		return err
+	}
+	typ := int8(p[0])
+	data, err := d.take(n)
+	if err != nil {
+		return err
+	}
+	if typ != -1 {
+		return fmt.Errorf("unsupported extension type %d", typ)
+	}
+
+	var tm time.Time
+	switch n {
+	case 4:
+		tm = time.Unix(int64(binary.BigEndian.Uint32(data)), 0)
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		tm = time.Unix(int64(u&(1<<34-1)), int64(u>>34))
+	case 12:
+		tm = time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data)))
+	default:
+		return fmt.Errorf("invalid timestamp length %d", n)
+	}
+	enc, err := json.Marshal(tm.UTC())
+	if err != nil {
+		return err
+	}
+	d.out.Write(enc)
+	return nil
+}
@@ -0,0 +1,231 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMsgpack_RoundTrip(t *testing.T) {
+	v := NewMsgpack(testProfile{Name: "Alice", Email: strings.Repeat("a", 40) + "@example.com"})
+
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Msgpack[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V != v.V {
+		t.Errorf("expected %+v, got %+v", v.V, scanned.V)
+	}
+}
+
+func TestMsgpack_Encoding(t *testing.T) {
+	result, err := NewMsgpack(map[string]any{"a": 1, "b": []any{true, nil, -2, 200, 1.5}}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := []byte{
+		0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x95, 0xc3, 0xc0, 0xfe, 0xd1, 0x00, 0xc8,
+		0xcb, 0x3f, 0xf8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	if !bytes.Equal(result.([]byte), expected) {
+		t.Errorf("expected % x, got % x", expected, result)
+	}
+}
+
+func TestMsgpack_Numbers(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](UseNumber(true))
+
+	input := map[string]any{
+		"max": json.Number("18446744073709551615"),
+		"min": json.Number("-9223372036854775808"),
+		"i16": json.Number("-300"),
+		"i32": json.Number("70000"),
+	}
+	result, err := NewMsgpack(input).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Msgpack[map[string]any]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	for k, want := range input {
+		if scanned.V[k] != want {
+			t.Errorf("%s: expected %v, got %v", k, want, scanned.V[k])
+		}
+	}
+}
+
+func TestMsgpack_BigNumbersAsStrings(t *testing.T) {
+	resetOptions[testInvoice](t)
+	Configure[testInvoice](BigNumbersAsStrings())
+
+	v := NewMsgpack(testInvoice{})
+	v.V.Rate.SetFrac64(1, 3)
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Msgpack[testInvoice]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V.Rate.String() != "1/3" {
+		t.Errorf("expected 1/3, got %s", scanned.V.Rate.String())
+	}
+}
+
+func TestMsgpack_DecodeForeign(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"float32", []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}, `1.5`},
+		{"uint16", []byte{0xcd, 0x01, 0x00}, `256`},
+		{"int8", []byte{0xd0, 0x80}, `-128`},
+		{"bin", []byte{0xc4, 0x03, 0x01, 0x02, 0x03}, `"AQID"`},
+		{"str8", []byte{0xd9, 0x02, 'h', 'i'}, `"hi"`},
+		{"integer keys", []byte{0x81, 0xff, 0xc0}, `{"-1":null}`},
+		{"timestamp32", []byte{0xd6, 0xff, 0x00, 0x00, 0x00, 0x3c}, `"1970-01-01T00:01:00Z"`},
+		{"timestamp64", []byte{0xd7, 0xff, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x3c}, `"1970-01-01T00:01:00.000000001Z"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Msgpack[json.RawMessage]
+			if err := v.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if string(v.V) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, v.V)
+			}
+		})
+	}
+}
+
+func TestMsgpack_InvalidData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"truncated", []byte{0x92, 0x01}},
+		{"trailing data", []byte{0x01, 0x02}},
+		{"reserved code", []byte{0xc1}},
+		{"huge length", []byte{0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"unknown extension", []byte{0xd4, 0x01, 0x00}},
+		{"array key", []byte{0x81, 0x90, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Msgpack[any]
+			if err := v.Scan(tt.input); err == nil {
+				t.Errorf("expected error, got %v", v.V)
+			}
+		})
+	}
+}
+
+func TestMsgpack_Null(t *testing.T) {
+	var v Msgpack[testProfile]
+	if err := v.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for nil, got %v", err)
+	}
+	if err := v.Scan([]byte{0xc0}); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for msgpack nil, got %v", err)
+	}
+}
+
+func TestNullableMsgpack(t *testing.T) {
+	result, err := NullableMsgpack[testProfile]{}.Value()
+	if err != nil || result != nil {
+		t.Fatalf("expected nil for invalid value, got %v, %v", result, err)
+	}
+
+	result, err = NullableMsgpackFrom(testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var n NullableMsgpack[testProfile]
+	if err := n.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V.Name != "Bob" {
+		t.Errorf("expected valid Bob, got %+v", n)
+	}
+
+	for _, src := range []any{nil, []byte{}, []byte{0xc0}} {
+		n := NullableMsgpackFrom(testProfile{Name: "Bob"})
+		if err := n.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) failed: %v", src, err)
+		}
+		if n.Valid || n.V.Name != "" {
+			t.Errorf("Scan(%v): expected null, got %+v", src, n)
+		}
+	}
+}
+
+func TestMsgpackCodec(t *testing.T) {
+	resetOptions[string](t)
+	Configure[string](MsgpackCodec(testCodec{}))
+
+	result, err := NewMsgpack("hello").Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != "codec:hello" {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestMsgpack_Checks(t *testing.T) {
+	resetOptions[testProfile](t)
+	var errs int
+	Configure[testProfile](MaxDocumentSize(20), Observe(Hooks{
+		OnError: func(HookEvent) { errs++ },
+	}))
+
+	data, err := NewMsgpack(testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var v Msgpack[testProfile]
+	if err := v.ScanContext(context.Background(), data); err != nil || v.V.Name != "Bob" {
+		t.Fatalf("expected Bob, got %+v, err: %v", v.V, err)
+	}
+	if _, err := NewMsgpack(testProfile{Name: "Bob", Email: "bob@example.com"}).Value(); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if errs != 1 {
+		t.Errorf("expected 1 error reported, got %d", errs)
+	}
+}
+
+func TestNullableMsgpack_Accessors(t *testing.T) {
+	n := NullMsgpack[testProfile]()
+	n.Set(testProfile{Name: "Bob"})
+	if p := n.ToPtr(); p == nil || p.Name != "Bob" {
+		t.Errorf("expected Bob, got %+v", n)
+	}
+	n.SetNull()
+	if v, err := n.Value(); err != nil || v != nil {
+		t.Errorf("expected NULL, got %v, err: %v", v, err)
+	}
+	if _, err := (NullableMsgpack[*testProfile]{}).Value(); err == nil {
+		t.Error("expected error for pointer type parameter")
+	}
+}
@@ -0,0 +1,102 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*NDJSON[struct{}])(nil)
+	_ ContextScanner = (*NDJSON[struct{}])(nil)
+	_ driver.Valuer  = NDJSON[struct{}]{}
+)
+
+// NDJSON[T] is a generic type for NOT NULL text columns holding newline-delimited JSON,
+// such as append-only event or audit columns produced by log shippers.
+// Scan decodes every non-blank line into a T with the options registered for T;
+// Value writes each element on its own line, terminated by a newline.
+type NDJSON[T any] struct {
+	V []T
+}
+
+// NewNDJSON creates a new NDJSON[T] with the given elements.
+func NewNDJSON[T any](v ...T) NDJSON[T] {
+	return NDJSON[T]{V: v}
+}
+
+// Get returns the elements.
+func (n NDJSON[T]) Get() []T {
+	return n.V
+}
+
+// Scan implements sql.Scanner interface.
+// Blank lines and carriage returns before newlines are ignored, so empty input scans as no elements.
+// Returns ErrNullNotAllowed if src is nil (NOT NULL constraint violation) or a line is JSON null.
+func (n *NDJSON[T]) Scan(src any) error {
+	return n.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (n *NDJSON[T]) ScanContext(ctx context.Context, src any) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("jsonsql.NDJSON.Scan: %w", err)
+	}
+	src, err := resolveSource(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.NDJSON.Scan: %w", err)
+	}
+	if src == nil {
+		return ErrNullNotAllowed
+	}
+	data, err := sourceBytes(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.NDJSON.Scan: %w", err)
+	}
+
+	v := []T{}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var elem T
+		null, err := decodeSourceContext(ctx, line, &elem, ErrorOnEmpty)
+		if err != nil {
+			return fmt.Errorf("jsonsql.NDJSON.Scan: line %d: %w", i+1, err)
+		}
+		if null {
+			return fmt.Errorf("jsonsql.NDJSON.Scan: line %d: %w", i+1, ErrNullNotAllowed)
+		}
+		v = append(v, elem)
+	}
+	n.V = v
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// Elements are always written compact, even when Indent is configured for T.
+func (n NDJSON[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	var buf bytes.Buffer
+	for i, elem := range n.V {
+		data, err := encodeValue(elem, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("jsonsql.NDJSON.Value: element %d: %w", i, err)
+		}
+		if bytes.IndexByte(data, '\n') >= 0 {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, data); err != nil {
+				return nil, fmt.Errorf("jsonsql.NDJSON.Value: element %d: %w", i, err)
+			}
+			data = compact.Bytes()
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
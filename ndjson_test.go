@@ -0,0 +1,55 @@
+package jsonsql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNDJSON_Scan(t *testing.T) {
+	var n NDJSON[testProfile]
+	src := "{\"name\":\"Alice\"}\r\n\n  \n{\"name\":\"Bob\"}"
+	if err := n.Scan(src); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(n.V) != 2 || n.V[0].Name != "Alice" || n.V[1].Name != "Bob" {
+		t.Errorf("unexpected result: %+v", n.V)
+	}
+
+	if err := n.Scan(""); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.V == nil || len(n.V) != 0 {
+		t.Errorf("expected empty slice, got %#v", n.V)
+	}
+}
+
+func TestNDJSON_ScanErrors(t *testing.T) {
+	var n NDJSON[testProfile]
+	if err := n.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for nil, got %v", err)
+	}
+	if err := n.Scan("{}\nnull\n"); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for null line, got %v", err)
+	}
+
+	err := n.Scan("{}\n{\"name\":1}\n")
+	if err == nil || !strings.HasPrefix(err.Error(), "jsonsql.NDJSON.Scan: line 2:") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+}
+
+func TestNDJSON_Value(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](Indent("  "))
+
+	result, err := NewNDJSON(testProfile{Name: "Alice"}, testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := "{\"name\":\"Alice\",\"email\":\"\"}\n{\"name\":\"Bob\",\"email\":\"\"}\n"
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
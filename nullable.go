@@ -2,21 +2,24 @@ package jsonsql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
-	"encoding/json"
+	"errors"
 	"fmt"
 )
 
 // Compile-time interface satisfaction checks
 var (
-	_ sql.Scanner   = (*Nullable[struct{}])(nil)
-	_ driver.Valuer = Nullable[struct{}]{}
+	_ sql.Scanner    = (*Nullable[struct{}])(nil)
+	_ ContextScanner = (*Nullable[struct{}])(nil)
+	_ driver.Valuer  = Nullable[struct{}]{}
 )
 
 // Nullable[T] is a generic type for NULL-able JSON columns.
 // Valid indicates whether V holds a valid value.
 // When Valid is false, the value represents NULL.
+// T must not be a pointer type; Scan and Value fail with a *TypeParamError for Nullable[*T].
 type Nullable[T any] struct {
 	V     T
 	Valid bool
@@ -63,72 +66,145 @@ func (n Nullable[T]) Get() (T, bool) {
 	return n.V, n.Valid
 }
 
+// TryGet returns the value, or ErrNullNotAllowed when n is NULL, for conversion pipelines that
+// treat a missing value as an error:
+//
+//	profile, err := row.Profile.TryGet()
+//	if err != nil {
+//		return fmt.Errorf("user %d: %w", id, err)
+//	}
+func (n Nullable[T]) TryGet() (T, error) {
+	if !n.Valid {
+		var zero T
+		return zero, ErrNullNotAllowed
+	}
+	return n.V, nil
+}
+
+// Set replaces the value with x and sets Valid=true.
+func (n *Nullable[T]) Set(x T) {
+	n.V = x
+	n.Valid = true
+}
+
+// SetNull resets n to NULL (Valid=false, V=zero value).
+func (n *Nullable[T]) SetNull() {
+	n.setNull()
+}
+
+// Replace replaces the value with the result of calling f with the current value and sets Valid=true.
+// When n is NULL, f receives the zero value of T.
+func (n *Nullable[T]) Replace(f func(T) T) {
+	n.Set(f(n.V))
+}
+
 // Scan implements sql.Scanner interface.
 // It unmarshals JSON data from the database into V.
 // Sets Valid=false for nil, empty []byte, empty string, or JSON literal "null".
+// The handling of empty input can be changed with EmptyInput.
 func (n *Nullable[T]) Scan(src any) error {
-	if src == nil {
-		n.Valid = false
-		var zero T
-		n.V = zero
-		return nil
-	}
+	return n.ScanContext(context.Background(), src)
+}
 
-	var data []byte
-	switch s := src.(type) {
-	case []byte:
-		if len(s) == 0 {
-			n.Valid = false
-			var zero T
-			n.V = zero
-			return nil
-		}
-		data = s
-	case string:
-		if len(s) == 0 {
-			n.Valid = false
-			var zero T
-			n.V = zero
-			return nil
-		}
-		data = []byte(s)
-	case json.RawMessage:
-		if len(s) == 0 {
-			n.Valid = false
-			var zero T
-			n.V = zero
-			return nil
-		}
-		data = s
-	default:
-		return fmt.Errorf("jsonsql.Nullable.Scan: unsupported type %T", src)
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (n *Nullable[T]) ScanContext(ctx context.Context, src any) error {
+	if err := checkNullableParam[T](); err != nil {
+		return fmt.Errorf("jsonsql.Nullable.Scan: %w", err)
 	}
-
-	// JSON literal null (with optional whitespace) should be treated as NULL (Valid=false)
-	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
-		n.Valid = false
-		var zero T
-		n.V = zero
-		return nil
+	null, err := decodeSourceContext(ctx, src, &n.V, NullOnEmpty)
+	if skipped := (*SkippedElementsError)(nil); errors.As(err, &skipped) {
+		n.Valid = true
+		return fmt.Errorf("jsonsql.Nullable.Scan: %w", err)
 	}
-
-	if err := json.Unmarshal(data, &n.V); err != nil {
+	if err != nil {
 		return fmt.Errorf("jsonsql.Nullable.Scan: %w", err)
 	}
+	if null {
+		n.setNull()
+		return nil
+	}
 	n.Valid = true
 	return nil
 }
 
+// setNull resets n to NULL (Valid=false, V=zero value).
+func (n *Nullable[T]) setNull() {
+	var zero T
+	n.V = zero
+	n.Valid = false
+}
+
 // Value implements driver.Valuer interface.
-// Returns nil (NULL) when Valid is false.
+// Returns nil (NULL) when Valid is false, unless NullAsJSON or NullAsEmptyObject is enabled for T.
 // Otherwise marshals V to JSON bytes.
 func (n Nullable[T]) Value() (driver.Value, error) {
+	if err := checkNullableParam[T](); err != nil {
+		return nil, fmt.Errorf("jsonsql.Nullable.Value: %w", err)
+	}
+	cfg := configFor[T]()
 	if !n.Valid {
-		return nil, nil
+		reportNull[T](cfg, cfg.nullDocument)
+		return cfg.nullValue(), nil
 	}
-	data, err := json.Marshal(n.V)
+	data, err := encodeValue(n.V, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("jsonsql.Nullable.Value: %w", err)
 	}
-	return data, nil
+	return cfg.driverValue(data), nil
+}
+
+// NullAsJSON makes Nullable[T].Value store an invalid value as the JSON literal null instead of
+// SQL NULL, for NOT NULL json/jsonb columns that represent "no value" as JSON null.
+// Scan already reads JSON null as Valid=false, so values round-trip either way.
+func NullAsJSON(enabled bool) Option {
+	return nullDocument(enabled, "null")
+}
+
+// NullAsEmptyObject makes Nullable[T].Value store an invalid value as the empty JSON object {}
+// instead of SQL NULL. Use it together with EmptyObjectAsNull so that {} scans back as Valid=false.
+func NullAsEmptyObject(enabled bool) Option {
+	return nullDocument(enabled, "{}")
+}
+
+// nullDocument returns an Option storing invalid values as doc, or as SQL NULL when disabled.
+func nullDocument(enabled bool, doc string) Option {
+	return func(c *config) {
+		c.nullDocument = nil
+		if enabled {
+			c.nullDocument = []byte(doc)
+		}
+	}
+}
+
+// nullValue returns the driver value stored for an invalid Nullable according to c.
+func (c *config) nullValue() driver.Value {
+	if c.nullDocument == nil {
+		return nil
+	}
+	return c.driverValue(bytes.Clone(c.nullDocument))
+}
+
+// Map converts n to a Nullable[B] by calling f with the value. NULL stays NULL without calling f.
+func Map[A, B any](n Nullable[A], f func(A) B) Nullable[B] {
+	if !n.Valid {
+		return Null[B]()
+	}
+	return NullableFrom(f(n.V))
+}
+
+// MapErr converts n to a Nullable[B] by calling f with the value, for conversions that can fail
+// such as parsing or validation. NULL stays NULL without calling f. When f fails, MapErr returns
+// Null[B]() and the error of f unchanged, so steps compose:
+//
+//	price, err := jsonsql.MapErr(row.Price, decimal.NewFromString)
+func MapErr[A, B any](n Nullable[A], f func(A) (B, error)) (Nullable[B], error) {
+	if !n.Valid {
+		return Null[B](), nil
+	}
+	v, err := f(n.V)
+	if err != nil {
+		return Null[B](), err
+	}
+	return NullableFrom(v), nil
 }
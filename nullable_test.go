@@ -1,7 +1,10 @@
 package jsonsql
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"strconv"
 	"testing"
 )
 
@@ -595,3 +598,134 @@ func TestNullable_Get_Invalid(t *testing.T) {
 		t.Errorf("expected zero value, got %+v", v)
 	}
 }
+
+func TestNullable_SetReplace(t *testing.T) {
+	n := Null[testProfile]()
+
+	n.Set(testProfile{Name: "Alice"})
+	if !n.Valid || n.V.Name != "Alice" {
+		t.Errorf("expected valid Alice, got %+v", n)
+	}
+
+	n.SetNull()
+	if n.Valid || n.V.Name != "" {
+		t.Errorf("expected NULL, got %+v", n)
+	}
+
+	n.Replace(func(p testProfile) testProfile {
+		if p.Name != "" {
+			t.Errorf("expected zero value for NULL, got %+v", p)
+		}
+		p.Name = "Bob"
+		return p
+	})
+	if !n.Valid || n.V.Name != "Bob" {
+		t.Errorf("expected valid Bob, got %+v", n)
+	}
+}
+
+func TestNullable_NullAsJSON(t *testing.T) {
+	type settings struct {
+		Theme string `json:"theme"`
+	}
+	resetOptions[settings](t)
+	Configure[settings](NullAsJSON(true))
+
+	result, err := Null[settings]().Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != "null" {
+		t.Fatalf("expected JSON null, got %v", result)
+	}
+
+	n := NullableFrom(settings{Theme: "dark"})
+	if err := n.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.Valid {
+		t.Errorf("expected Valid=false after scanning JSON null, got %+v", n)
+	}
+
+	batch, err := BatchNullables([]Nullable[settings]{Null[settings]()})
+	if err != nil {
+		t.Fatalf("BatchNullables failed: %v", err)
+	}
+	if string(batch[0].([]byte)) != "null" {
+		t.Errorf("expected JSON null from BatchNullables, got %v", batch[0])
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder[settings](&buf, CopyText).EncodeNullable(Null[settings]()); err != nil {
+		t.Fatalf("EncodeNullable failed: %v", err)
+	}
+	if buf.String() != "null\n" {
+		t.Errorf("expected JSON null row, got %q", buf.String())
+	}
+
+	Configure[settings]()
+	result, err = Null[settings]().Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected SQL NULL by default, got %v", result)
+	}
+}
+
+func TestNullable_TryGet(t *testing.T) {
+	if v, err := NullableFrom(3).TryGet(); err != nil || v != 3 {
+		t.Errorf("TryGet = %d, %v", v, err)
+	}
+	if _, err := Null[int]().TryGet(); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	if n := Map(NullableFrom(2), strconv.Itoa); !n.Valid || n.V != "2" {
+		t.Errorf("unexpected result %+v", n)
+	}
+	called := false
+	n := Map(Null[int](), func(int) string { called = true; return "" })
+	if n.Valid || called {
+		t.Errorf("expected NULL without calling f, got %+v (called=%v)", n, called)
+	}
+}
+
+func TestMapErr(t *testing.T) {
+	n, err := MapErr(NullableFrom("42"), strconv.Atoi)
+	if err != nil || !n.Valid || n.V != 42 {
+		t.Errorf("MapErr = %+v, %v", n, err)
+	}
+
+	n, err = MapErr(NullableFrom("x"), strconv.Atoi)
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || n.Valid {
+		t.Errorf("expected NumError and NULL, got %+v, %v", n, err)
+	}
+
+	n, err = MapErr(Null[string](), func(string) (int, error) {
+		t.Fatal("f must not be called for NULL")
+		return 0, nil
+	})
+	if err != nil || n.Valid {
+		t.Errorf("expected NULL, got %+v, %v", n, err)
+	}
+
+	// Steps compose.
+	half := func(i int) (float64, error) {
+		if i%2 != 0 {
+			return 0, errors.New("odd")
+		}
+		return float64(i) / 2, nil
+	}
+	f, err := MapErr(NullableFrom("8"), strconv.Atoi)
+	if err == nil {
+		var h Nullable[float64]
+		h, err = MapErr(f, half)
+		if err != nil || h.V != 4 {
+			t.Errorf("unexpected result %+v, %v", h, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"reflect"
+)
+
+// StringNumber encodes values of the numeric type N as JSON strings and decodes them
+// from JSON strings or bare JSON numbers, so they never pass through float64.
+// It is the registration hook for custom numeric types such as decimal types:
+// *N must implement encoding.TextMarshaler and encoding.TextUnmarshaler, which the type
+// parameter PN enforces at compile time; it is inferred, as in StringNumber[Decimal]().
+// The hook applies to N wherever it appears inside a document, including behind pointers.
+func StringNumber[N any, PN interface {
+	*N
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}]() Option {
+	t := reflect.TypeFor[N]()
+
+	hook := typeHook{
+		encode: func(rv reflect.Value) ([]byte, error) {
+			text, err := PN(addressable(rv).Addr().Interface().(*N)).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(string(text))
+		},
+		decode: func(data []byte, rv reflect.Value) error {
+			text, err := numberText(data, t)
+			if err != nil {
+				return err
+			}
+			return PN(rv.Addr().Interface().(*N)).UnmarshalText(text)
+		},
+	}
+
+	return func(c *config) {
+		c.setHook(t, hook)
+	}
+}
+
+// BigNumbersAsStrings encodes big.Int, big.Float and big.Rat values as JSON strings
+// and decodes them losslessly from JSON strings or bare JSON numbers.
+// By default encoding/json writes big.Int as a bare number, which other readers often parse as float64.
+func BigNumbersAsStrings() Option {
+	opts := []Option{
+		StringNumber[big.Int](),
+		StringNumber[big.Float](),
+		StringNumber[big.Rat](),
+	}
+	return func(c *config) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
+// numberText returns the textual number held by the JSON string or number data.
+func numberText(data []byte, t reflect.Type) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return nil, &json.UnmarshalTypeError{Value: jsonKind(data), Type: t}
+	}
+	return []byte(num), nil
+}
+
+// addressable returns rv itself if it is addressable, otherwise an addressable copy.
+func addressable(rv reflect.Value) reflect.Value {
+	if rv.CanAddr() {
+		return rv
+	}
+	tmp := reflect.New(rv.Type()).Elem()
+	tmp.Set(rv)
+	return tmp
+}
@@ -0,0 +1,139 @@
+package jsonsql
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+type testInvoice struct {
+	Amount *big.Int  `json:"amount"`
+	Rate   big.Rat   `json:"rate"`
+	Total  big.Float `json:"total"`
+}
+
+func TestBigNumbersAsStrings_Value(t *testing.T) {
+	resetOptions[testInvoice](t)
+	Configure[testInvoice](BigNumbersAsStrings())
+
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	v := NewValue(testInvoice{Amount: amount})
+	v.V.Rate.SetFrac64(1, 3)
+	v.V.Total.SetFloat64(1.5)
+
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := `{"amount":"123456789012345678901234567890","rate":"1/3","total":"1.5"}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestBigNumbersAsStrings_Scan(t *testing.T) {
+	resetOptions[testInvoice](t)
+	Configure[testInvoice](BigNumbersAsStrings())
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"strings", `{"amount":"123456789012345678901234567890","rate":"1/3","total":"1.5"}`},
+		{"bare numbers", `{"amount":123456789012345678901234567890,"rate":"1/3","total":1.5}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testInvoice]
+			if err := v.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			if v.V.Amount.String() != "123456789012345678901234567890" {
+				t.Errorf("unexpected amount: %s", v.V.Amount)
+			}
+			if v.V.Rate.String() != "1/3" {
+				t.Errorf("unexpected rate: %s", v.V.Rate.String())
+			}
+			if v.V.Total.String() != "1.5" {
+				t.Errorf("unexpected total: %s", v.V.Total.String())
+			}
+		})
+	}
+}
+
+func TestBigNumbersAsStrings_Null(t *testing.T) {
+	resetOptions[testInvoice](t)
+	Configure[testInvoice](BigNumbersAsStrings())
+
+	v := NewValue(testInvoice{})
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"amount":null,"rate":"0","total":"0"}` {
+		t.Errorf("unexpected result: %s", result)
+	}
+
+	var scanned Value[testInvoice]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V.Amount != nil {
+		t.Errorf("expected nil amount, got %s", scanned.V.Amount)
+	}
+}
+
+func TestBigNumbersAsStrings_InvalidInput(t *testing.T) {
+	resetOptions[testInvoice](t)
+	Configure[testInvoice](BigNumbersAsStrings())
+
+	var v Value[testInvoice]
+	if err := v.Scan(`{"amount":true}`); err == nil {
+		t.Fatal("expected error for bool amount")
+	}
+	if err := v.Scan(`{"amount":"abc"}`); err == nil {
+		t.Fatal("expected error for non-numeric amount")
+	}
+}
+
+type testCents int64
+
+func (c testCents) MarshalText() ([]byte, error) {
+	return []byte(big.NewRat(int64(c), 100).FloatString(2)), nil
+}
+
+func (c *testCents) UnmarshalText(text []byte) error {
+	r, ok := new(big.Rat).SetString(string(text))
+	if !ok {
+		return errTestCents
+	}
+	*c = testCents(new(big.Int).Quo(r.Num(), new(big.Int).Quo(r.Denom(), big.NewInt(100))).Int64())
+	return nil
+}
+
+var errTestCents = errors.New("invalid cents")
+
+func TestStringNumber_CustomType(t *testing.T) {
+	resetOptions[map[string]testCents](t)
+	Configure[map[string]testCents](StringNumber[testCents]())
+
+	v := NewValue(map[string]testCents{"price": 1999})
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"price":"19.99"}` {
+		t.Errorf("unexpected result: %s", result)
+	}
+
+	var scanned Value[map[string]testCents]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V["price"] != 1999 {
+		t.Errorf("expected 1999, got %d", scanned.V["price"])
+	}
+}
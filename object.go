@@ -0,0 +1,45 @@
+package jsonsql
+
+import "reflect"
+
+// ObjectMap is the type parameter of Object and NullObject: a JSON object decoded without a schema.
+// It is a distinct type from map[string]any so that its decode options can differ from the
+// defaults of Value[map[string]any].
+type ObjectMap map[string]any
+
+// ArrayList is the type parameter of Array: a JSON array decoded without a schema.
+type ArrayList []any
+
+// Object is a Value for NOT NULL JSON columns holding objects without a schema.
+// Numbers are decoded as json.Number, so integers beyond 2^53 keep their precision.
+type Object = Value[ObjectMap]
+
+// NullObject is a Nullable for nullable JSON columns holding objects without a schema.
+// It decodes numbers like Object.
+type NullObject = Nullable[ObjectMap]
+
+// Array is a Value for NOT NULL JSON columns holding arrays without a schema.
+// It decodes numbers like Object.
+type Array = Value[ArrayList]
+
+// NewObject creates a new Object holding m.
+func NewObject(m ObjectMap) Object {
+	return NewValue(m)
+}
+
+// NewNullObject creates a new NullObject holding m, which is NULL if m is nil.
+func NewNullObject(m ObjectMap) NullObject {
+	return NewNullable(m, m != nil)
+}
+
+// NewArray creates a new Array with the given elements.
+func NewArray(v ...any) Array {
+	return NewValue(ArrayList(v))
+}
+
+// typeDefaults holds the options of the predeclared types, applied after the global defaults
+// and before the options registered with Configure, which can override them.
+var typeDefaults = map[reflect.Type][]Option{
+	reflect.TypeFor[ObjectMap](): {UseNumber(true)},
+	reflect.TypeFor[ArrayList](): {UseNumber(true)},
+}
@@ -0,0 +1,65 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestObject_UseNumber(t *testing.T) {
+	var o Object
+	if err := o.Scan([]byte(`{"id":9007199254740993,"tags":[1]}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if id, ok := o.V["id"].(json.Number); !ok || id != "9007199254740993" {
+		t.Errorf("expected json.Number, got %T %v", o.V["id"], o.V["id"])
+	}
+
+	var a Array
+	if err := a.Scan(`[1.5,"x"]`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n, ok := a.V[0].(json.Number); !ok || n != "1.5" {
+		t.Errorf("expected json.Number, got %T %v", a.V[0], a.V[0])
+	}
+
+	var m Value[map[string]any]
+	if err := m.Scan([]byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := m.V["id"].(float64); !ok {
+		t.Errorf("expected float64 for Value[map[string]any], got %T", m.V["id"])
+	}
+}
+
+func TestObject_ConfigureOverrides(t *testing.T) {
+	resetOptions[ObjectMap](t)
+	Configure[ObjectMap](UseNumber(false))
+
+	var o Object
+	if err := o.Scan([]byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := o.V["id"].(float64); !ok {
+		t.Errorf("expected float64, got %T", o.V["id"])
+	}
+}
+
+func TestNullObject(t *testing.T) {
+	var n NullObject
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Fatalf("expected NULL, got %+v, err: %v", n, err)
+	}
+	if v, err := NewNullObject(nil).Value(); err != nil || v != nil {
+		t.Errorf("expected nil, got %v, err: %v", v, err)
+	}
+	v, err := NewNullObject(ObjectMap{"a": 1}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(v.([]byte)) != `{"a":1}` {
+		t.Errorf("unexpected value %s", v)
+	}
+	if v, err := NewArray(1, "x").Value(); err != nil || string(v.([]byte)) != `[1,"x"]` {
+		t.Errorf("unexpected array %s, err: %v", v, err)
+	}
+}
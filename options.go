@@ -0,0 +1,238 @@
+package jsonsql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hash"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// Option configures how the wrappers encode and decode JSON.
+// Options can be applied globally with SetDefaults or per type parameter with Configure.
+type Option func(*config)
+
+// config holds the resolved encode/decode settings for a type parameter.
+type config struct {
+	// Scan settings.
+	useNumber        bool
+	utf8Policy       UTF8Policy
+	lenient          bool
+	prefixPolicy     PrefixPolicy
+	coerceScalars    bool
+	extractMode      ExtractMode
+	allowTrailing    bool
+	emptyPolicy      EmptyPolicy
+	resetOnScan      bool
+	collectErrors    bool
+	localKeys        KeyStyle
+	emptyObjectNull  bool
+	emptyArrayNull   bool
+	rejectDuplicates bool
+	skipInvalid      bool
+
+	// Value settings.
+	noEscapeHTML  bool
+	indent        string
+	nulMode       NULMode
+	sanitizeUTF8  bool
+	onSanitize    func(SanitizeReport)
+	storedKeys    KeyStyle
+	persistPolicy PersistPolicy
+	nullDocument  []byte
+	rawMessage    bool
+	compact       bool
+
+	// Settings applied in both directions.
+	maxSize      int
+	validate     bool
+	checksumHash func() hash.Hash
+	signer       Signer
+	keyResolver  KeyResolver
+	trustRaw     bool
+
+	// Codec settings.
+	documentCodec Codec
+	cborCodec     Codec
+	msgpackCodec  Codec
+
+	// hooks are custom encoders/decoders per Go type, applied by the walker in both directions.
+	hooks map[reflect.Type]typeHook
+	// aliases maps struct types to the old keys accepted on Scan per current key.
+	aliases map[reflect.Type]map[string][]string
+	// fieldHooks are custom encoders/decoders for struct fields tagged `jsonsql:"hook=name"`, by name.
+	fieldHooks map[string]fieldHook
+
+	// observer holds the observability hooks.
+	observer Hooks
+
+	// debugLogger and debug configure the logging of failed scans.
+	debugLogger *slog.Logger
+	debug       DebugOptions
+
+	// typeErr is the error of checkTypeParam for the type parameter.
+	typeErr error
+
+	// walkCache caches needsWalk results per type.
+	walkCache sync.Map
+}
+
+// UseNumber makes Scan decode JSON numbers inside interface values (such as map[string]any)
+// as json.Number instead of float64, so integers beyond 2^53 keep their precision.
+func UseNumber(enabled bool) Option {
+	return func(c *config) {
+		c.useNumber = enabled
+	}
+}
+
+// MergeOnScan controls whether Scan decodes into the existing V, following the encoding/json
+// merge rules: struct fields and map entries absent from the stored document keep their current
+// values. Merging is enabled by default, so programmatic defaults survive for documents written
+// before a field was added:
+//
+//	v := jsonsql.NewValue(DefaultSettings())
+//	err := row.Scan(&v)
+//
+// Disabling it resets V to the zero value before decoding, so reusing a variable across rows
+// never leaks fields from a previous row.
+func MergeOnScan(enabled bool) Option {
+	return func(c *config) {
+		c.resetOnScan = !enabled
+	}
+}
+
+// CollectErrors makes Scan decode on a best-effort basis: values that fail to decode
+// (wrong types, overflow) are skipped and reported as *FieldError values joined into one error,
+// while everything else is still populated. This lets data-repair jobs report every problem
+// in a row in one pass. Note that Scan still returns an error and leaves Valid of a Nullable[T]
+// unchanged, so check the error rather than Valid.
+func CollectErrors(enabled bool) Option {
+	return func(c *config) {
+		c.collectErrors = enabled
+	}
+}
+
+// AllowTrailingData makes Scan ignore data after the first JSON value instead of failing
+// with ErrTrailingData.
+func AllowTrailingData(enabled bool) Option {
+	return func(c *config) {
+		c.allowTrailing = enabled
+	}
+}
+
+// EscapeHTML controls whether Value escapes <, > and & inside JSON strings as \u003c, \u003e and \u0026.
+// Escaping is enabled by default to match json.Marshal; disabling it produces the same documents
+// as most non-Go writers and keeps stored values readable.
+func EscapeHTML(enabled bool) Option {
+	return func(c *config) {
+		c.noEscapeHTML = !enabled
+	}
+}
+
+// Indent makes Value store indented, human-readable JSON using indent for each nesting level.
+// An empty indent keeps the default compact output, so the setting can be passed straight from
+// environment-specific configuration, e.g. SetDefaults(Indent(cfg.JSONIndent)) with "  " in
+// development and "" in production.
+func Indent(indent string) Option {
+	return func(c *config) {
+		c.indent = indent
+	}
+}
+
+// ValueAsRawMessage makes Value return documents as json.RawMessage instead of []byte.
+// Drivers that check argument types, such as pgx through database/sql or sqlmock with a custom
+// converter, can then bind the argument as JSON rather than bytea/blob. Drivers that only accept
+// the standard driver.Value types receive plain []byte from database/sql's default converter.
+// It applies to Value, Nullable, Sensitive, TrackedNullable, Immutable, ValueJSON and the Batch
+// helpers; it has no effect when a document Codec is configured, and wrappers storing text
+// formats other than a single JSON document are unaffected.
+func ValueAsRawMessage(enabled bool) Option {
+	return func(c *config) {
+		c.rawMessage = enabled
+	}
+}
+
+// driverValue returns the encoded document data as the driver value configured by c.
+func (c *config) driverValue(data []byte) driver.Value {
+	if c.rawMessage && c.documentCodec == nil {
+		return json.RawMessage(data)
+	}
+	return data
+}
+
+// registry stores the global and per-type options and caches the resolved configs.
+var registry = struct {
+	mu       sync.RWMutex
+	global   []Option
+	types    map[reflect.Type][]Option
+	resolved map[reflect.Type]*config
+}{
+	types:    map[reflect.Type][]Option{},
+	resolved: map[reflect.Type]*config{},
+}
+
+// SetDefaults replaces the global options applied to every wrapper type.
+// Calling it without options restores the built-in defaults.
+// It is intended to be called during program initialization.
+func SetDefaults(opts ...Option) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.global = append([]Option(nil), opts...)
+	clear(registry.resolved)
+}
+
+// Configure replaces the options used by wrappers whose type parameter is T
+// (for example Value[T], Nullable[T] and Sensitive[T]).
+// Per-type options are applied on top of the global defaults.
+// Calling it without options removes the per-type configuration.
+func Configure[T any](opts ...Option) {
+	typ := reflect.TypeFor[T]()
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if len(opts) == 0 {
+		delete(registry.types, typ)
+	} else {
+		registry.types[typ] = append([]Option(nil), opts...)
+	}
+	clear(registry.resolved)
+}
+
+// configFor returns the resolved config for type parameter T.
+// The returned config is shared and must not be modified.
+func configFor[T any]() *config {
+	typ := reflect.TypeFor[T]()
+
+	registry.mu.RLock()
+	cfg, ok := registry.resolved[typ]
+	registry.mu.RUnlock()
+	if ok {
+		return cfg
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	cfg = newConfig(typ, registry.global, typeDefaults[typ], registry.types[typ])
+	registry.resolved[typ] = cfg
+	return cfg
+}
+
+// newConfig resolves a config for type parameter typ by applying the option lists in order.
+func newConfig(typ reflect.Type, opts ...[]Option) *config {
+	cfg := &config{}
+	for _, list := range opts {
+		for _, opt := range list {
+			opt(cfg)
+		}
+	}
+	cfg.typeErr = checkTypeParam(typ, cfg)
+	return cfg
+}
+
+// setHook registers a type hook for t on c.
+func (c *config) setHook(t reflect.Type, h typeHook) {
+	if c.hooks == nil {
+		c.hooks = map[reflect.Type]typeHook{}
+	}
+	c.hooks[t] = h
+}
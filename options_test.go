@@ -0,0 +1,279 @@
+package jsonsql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+)
+
+// resetOptions restores the built-in defaults after a test changes the option registry.
+func resetOptions[T any](t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		SetDefaults()
+		Configure[T]()
+	})
+}
+
+func TestUseNumber_Default_Float64(t *testing.T) {
+	var v Value[map[string]any]
+
+	if err := v.Scan([]byte(`{"id":9007199254740993}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if _, ok := v.V["id"].(float64); !ok {
+		t.Errorf("expected float64, got %T", v.V["id"])
+	}
+}
+
+func TestUseNumber_Global(t *testing.T) {
+	resetOptions[map[string]any](t)
+	SetDefaults(UseNumber(true))
+
+	var v Value[map[string]any]
+	if err := v.Scan([]byte(`{"id":9007199254740993}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	num, ok := v.V["id"].(json.Number)
+	if !ok {
+		t.Fatalf("expected json.Number, got %T", v.V["id"])
+	}
+	if num.String() != "9007199254740993" {
+		t.Errorf("expected 9007199254740993, got %s", num)
+	}
+}
+
+func TestUseNumber_PerType(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](UseNumber(true))
+
+	var n Nullable[map[string]any]
+	if err := n.Scan([]byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := n.V["id"].(json.Number); !ok {
+		t.Errorf("expected json.Number, got %T", n.V["id"])
+	}
+
+	var other Value[[]any]
+	if err := other.Scan([]byte(`[1]`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := other.V[0].(float64); !ok {
+		t.Errorf("expected float64 for unconfigured type, got %T", other.V[0])
+	}
+}
+
+func TestUseNumber_PerTypeOverridesGlobal(t *testing.T) {
+	resetOptions[map[string]any](t)
+	SetDefaults(UseNumber(true))
+	Configure[map[string]any](UseNumber(false))
+
+	var v Value[map[string]any]
+	if err := v.Scan([]byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if _, ok := v.V["id"].(float64); !ok {
+		t.Errorf("expected float64, got %T", v.V["id"])
+	}
+}
+
+func TestConfigure_Reset(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](UseNumber(true))
+	Configure[map[string]any]()
+
+	var v Value[map[string]any]
+	if err := v.Scan([]byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if _, ok := v.V["id"].(float64); !ok {
+		t.Errorf("expected float64 after reset, got %T", v.V["id"])
+	}
+}
+
+func TestEscapeHTML_Default(t *testing.T) {
+	result, err := NewValue(map[string]string{"html": "<a>&</a>"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := `{"html":"\u003ca\u003e\u0026\u003c/a\u003e"}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestEscapeHTML_Disabled(t *testing.T) {
+	resetOptions[map[string]string](t)
+	SetDefaults(EscapeHTML(false))
+
+	result, err := NewNullable(map[string]string{"html": "<a>&</a>"}, true).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := `{"html":"<a>&</a>"}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestEscapeHTML_Disabled_Walker(t *testing.T) {
+	resetOptions[map[string]testInvoice](t)
+	Configure[map[string]testInvoice](EscapeHTML(false), BigNumbersAsStrings())
+
+	result, err := NewValue(map[string]testInvoice{"<key>": {}}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := `{"<key>":{"amount":null,"rate":"0","total":"0"}}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestIndent(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](Indent("  "))
+
+	result, err := NewValue(testProfile{Name: "Alice", Email: "alice@example.com"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := "{\n  \"name\": \"Alice\",\n  \"email\": \"alice@example.com\"\n}"
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var scanned Value[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V.Name != "Alice" {
+		t.Errorf("expected Name=Alice, got %s", scanned.V.Name)
+	}
+}
+
+func TestIndent_EmptyIsCompact(t *testing.T) {
+	resetOptions[testProfile](t)
+	SetDefaults(Indent("  "))
+	Configure[testProfile](Indent(""))
+
+	result, err := NewValue(testProfile{Name: "Alice"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	expected := `{"name":"Alice","email":""}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+type testSettings struct {
+	Theme    string `json:"theme"`
+	PageSize int    `json:"page_size"`
+}
+
+func TestMergeOnScan_DefaultMerges(t *testing.T) {
+	v := NewValue(testSettings{Theme: "dark", PageSize: 50})
+	if err := v.Scan(`{"theme":"light"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Theme != "light" || v.V.PageSize != 50 {
+		t.Errorf("expected PageSize default to be kept, got %+v", v.V)
+	}
+
+	n := NullableFrom(testSettings{PageSize: 20})
+	if err := n.Scan(`{"theme":"light"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V.PageSize != 20 {
+		t.Errorf("expected PageSize default to be kept, got %+v", n)
+	}
+}
+
+func TestMergeOnScan_Disabled_Resets(t *testing.T) {
+	resetOptions[testSettings](t)
+	Configure[testSettings](MergeOnScan(false))
+
+	v := NewValue(testSettings{Theme: "dark", PageSize: 50})
+	if err := v.Scan(`{"theme":"light"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Theme != "light" || v.V.PageSize != 0 {
+		t.Errorf("expected fields from previous value to be reset, got %+v", v.V)
+	}
+}
+
+func TestMergeOnScan_Map(t *testing.T) {
+	resetOptions[map[string]int](t)
+	Configure[map[string]int](MergeOnScan(true))
+
+	v := NewValue(map[string]int{"a": 1})
+	if err := v.Scan(`{"b":2}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V["a"] != 1 || v.V["b"] != 2 {
+		t.Errorf("expected merged map, got %v", v.V)
+	}
+}
+
+func TestValueAsRawMessage(t *testing.T) {
+	resetOptions[testSettings](t)
+	Configure[testSettings](ValueAsRawMessage(true), NullAsJSON(true))
+
+	valuers := map[string]interface {
+		Value() (driver.Value, error)
+	}{
+		"Value":           NewValue(testSettings{Theme: "dark"}),
+		"Nullable":        NullableFrom(testSettings{Theme: "dark"}),
+		"Sensitive":       NewSensitive(testSettings{Theme: "dark"}),
+		"TrackedNullable": TrackedNullable[testSettings]{V: testSettings{Theme: "dark"}, Valid: true},
+	}
+	for name, v := range valuers {
+		result, err := v.Value()
+		if err != nil {
+			t.Fatalf("%s: Value failed: %v", name, err)
+		}
+		raw, ok := result.(json.RawMessage)
+		if !ok {
+			t.Fatalf("%s: expected json.RawMessage, got %T", name, result)
+		}
+		if string(raw) != `{"theme":"dark","page_size":0}` {
+			t.Errorf("%s: unexpected document %s", name, raw)
+		}
+	}
+
+	result, err := Null[testSettings]().Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if raw, ok := result.(json.RawMessage); !ok || string(raw) != "null" {
+		t.Errorf("expected json.RawMessage null, got %#v", result)
+	}
+
+	batch, err := BatchValues([]Value[testSettings]{NewValue(testSettings{})})
+	if err != nil {
+		t.Fatalf("BatchValues failed: %v", err)
+	}
+	if _, ok := batch[0].(json.RawMessage); !ok {
+		t.Errorf("expected json.RawMessage from BatchValues, got %T", batch[0])
+	}
+
+	Configure[testSettings]()
+	result, err = NewValue(testSettings{}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if _, ok := result.([]byte); !ok {
+		t.Errorf("expected []byte by default, got %T", result)
+	}
+}
@@ -0,0 +1,332 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is returned when a path does not match any value of a document.
+var ErrPathNotFound = permanent("jsonsql: path not found")
+
+// ErrInvalidPath is returned for malformed paths.
+var ErrInvalidPath = permanent("jsonsql: invalid path")
+
+// pathToken is one step of a parsed path: an object key, an array index or a wildcard.
+type pathToken struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// String returns the token in bracket notation.
+func (t pathToken) String() string {
+	switch {
+	case t.wildcard:
+		return "[*]"
+	case t.isIndex:
+		return "[" + strconv.Itoa(t.index) + "]"
+	}
+	return "[" + strconv.Quote(t.key) + "]"
+}
+
+// parsePath parses the JSONPath subset supported by Document:
+//
+//	$                   the root (may be omitted)
+//	.name  or  name     an object member
+//	['name'] ["name"]   an object member with arbitrary characters
+//	[2]  [-1]           an array element, counted from the end when negative
+//	.*  [*]             every member or element
+func parsePath(path string) ([]pathToken, error) {
+	invalid := func(pos int, msg string) error {
+		return fmt.Errorf("%w %q at offset %d: %s", ErrInvalidPath, path, pos, msg)
+	}
+
+	s, rooted := strings.CutPrefix(path, "$")
+	var tokens []pathToken
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '[':
+			end := i + 1
+			switch {
+			case end < len(s) && (s[end] == '\'' || s[end] == '"'):
+				key, n, err := parseQuotedKey(s[end:])
+				if err != nil {
+					return nil, invalid(len(path)-len(s)+end, err.Error())
+				}
+				tokens = append(tokens, pathToken{key: key})
+				end += n
+			case end < len(s) && s[end] == '*':
+				tokens = append(tokens, pathToken{wildcard: true})
+				end++
+			default:
+				j := strings.IndexByte(s[end:], ']')
+				if j < 0 {
+					return nil, invalid(len(path)-len(s)+i, "unterminated bracket")
+				}
+				index, err := strconv.Atoi(s[end : end+j])
+				if err != nil {
+					return nil, invalid(len(path)-len(s)+end, "expected index, quoted name or *")
+				}
+				tokens = append(tokens, pathToken{index: index, isIndex: true})
+				end += j
+			}
+			if end >= len(s) || s[end] != ']' {
+				return nil, invalid(len(path)-len(s)+end, "expected ]")
+			}
+			i = end + 1
+
+		default:
+			if s[i] == '.' {
+				i++
+			} else if i > 0 || rooted {
+				return nil, invalid(len(path)-len(s)+i, "expected . or [")
+			}
+			j := i
+			for j < len(s) && s[j] != '.' && s[j] != '[' && s[j] != ']' {
+				j++
+			}
+			if j == i {
+				return nil, invalid(len(path)-len(s)+i, "empty member name")
+			}
+			if s[i:j] == "*" {
+				tokens = append(tokens, pathToken{wildcard: true})
+			} else {
+				tokens = append(tokens, pathToken{key: s[i:j]})
+			}
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// parseQuotedKey parses a single- or double-quoted member name at the start of s, where a
+// backslash escapes the next character. It returns the name and the number of bytes consumed.
+func parseQuotedKey(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", 0, fmt.Errorf("unterminated name")
+			}
+			b.WriteByte(s[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated name")
+}
+
+// hasWildcard reports whether tokens contain a wildcard.
+func hasWildcard(tokens []pathToken) bool {
+	for _, t := range tokens {
+		if t.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// arrayIndex resolves index, which counts from the end when negative, against an array of length n.
+func arrayIndex(index, n int) (int, bool) {
+	if index < 0 {
+		index += n
+	}
+	return index, index >= 0 && index < n
+}
+
+// lookupPath appends the values of v matching tokens to out.
+func lookupPath(v any, tokens []pathToken, out []any) []any {
+	if len(tokens) == 0 {
+		return append(out, v)
+	}
+	t, rest := tokens[0], tokens[1:]
+	switch node := v.(type) {
+	case map[string]any:
+		if t.wildcard {
+			for _, k := range slices.Sorted(maps.Keys(node)) {
+				out = lookupPath(node[k], rest, out)
+			}
+		} else if child, ok := node[t.key]; ok && !t.isIndex {
+			out = lookupPath(child, rest, out)
+		}
+	case []any:
+		if t.wildcard {
+			for _, child := range node {
+				out = lookupPath(child, rest, out)
+			}
+		} else if i, ok := arrayIndex(t.index, len(node)); ok && t.isIndex {
+			out = lookupPath(node[i], rest, out)
+		}
+	}
+	return out
+}
+
+// valueKind describes the kind of a decoded document value like jsonKind.
+func valueKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// setPath sets the value of node at tokens to value, creating missing objects along the way,
+// and returns the updated node. An array index equal to the length of the array appends.
+// Wildcards set every existing match. It fails with ErrPathNotFound if a token does not
+// apply to the node it addresses. Without wildcards node is left unchanged on failure; a failed
+// wildcard set may have updated earlier matches, so callers apply those to a copy.
+func setPath(node any, tokens []pathToken, value any) (any, error) {
+	if len(tokens) == 0 {
+		// Each match gets its own copy, so matches of a wildcard do not share maps and slices.
+		return cloneValue(value), nil
+	}
+	t, rest := tokens[0], tokens[1:]
+	if node == nil && !t.isIndex && !t.wildcard {
+		node = map[string]any{}
+	}
+	var err error
+	switch node := node.(type) {
+	case map[string]any:
+		switch {
+		case t.wildcard:
+			for k, child := range node {
+				if node[k], err = setPath(child, rest, value); err != nil {
+					return nil, err
+				}
+			}
+		case !t.isIndex:
+			child, err := setPath(node[t.key], rest, value)
+			if err != nil {
+				return nil, err
+			}
+			node[t.key] = child
+		default:
+			return nil, fmt.Errorf("%w: %v applied to object", ErrPathNotFound, t)
+		}
+		return node, nil
+	case []any:
+		switch {
+		case t.wildcard:
+			for i, child := range node {
+				if node[i], err = setPath(child, rest, value); err != nil {
+					return nil, err
+				}
+			}
+		case !t.isIndex:
+			return nil, fmt.Errorf("%w: %v applied to array", ErrPathNotFound, t)
+		case t.index == len(node):
+			child, err := setPath(nil, rest, value)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, child)
+		default:
+			i, ok := arrayIndex(t.index, len(node))
+			if !ok {
+				return nil, fmt.Errorf("%w: %v out of range for %d elements", ErrPathNotFound, t, len(node))
+			}
+			child, err := setPath(node[i], rest, value)
+			if err != nil {
+				return nil, err
+			}
+			node[i] = child
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("%w: %v applied to %s", ErrPathNotFound, t, valueKind(node))
+}
+
+// deletePath removes the values of node matching tokens and returns the updated node and
+// the number of values removed. Missing values are skipped.
+func deletePath(node any, tokens []pathToken) (any, int) {
+	t, rest := tokens[0], tokens[1:]
+	removed := 0
+	switch node := node.(type) {
+	case map[string]any:
+		switch {
+		case t.isIndex:
+		case len(rest) == 0 && t.wildcard:
+			removed = len(node)
+			clear(node)
+		case len(rest) == 0:
+			if _, ok := node[t.key]; ok {
+				delete(node, t.key)
+				removed = 1
+			}
+		case t.wildcard:
+			for k, child := range node {
+				var n int
+				node[k], n = deletePath(child, rest)
+				removed += n
+			}
+		default:
+			if child, ok := node[t.key]; ok {
+				node[t.key], removed = deletePath(child, rest)
+			}
+		}
+		return node, removed
+	case []any:
+		switch {
+		case !t.isIndex && !t.wildcard:
+		case len(rest) == 0 && t.wildcard:
+			removed = len(node)
+			node = node[:0]
+		case len(rest) == 0:
+			if i, ok := arrayIndex(t.index, len(node)); ok {
+				node = slices.Delete(node, i, i+1)
+				removed = 1
+			}
+		case t.wildcard:
+			for i, child := range node {
+				var n int
+				node[i], n = deletePath(child, rest)
+				removed += n
+			}
+		default:
+			if i, ok := arrayIndex(t.index, len(node)); ok {
+				node[i], removed = deletePath(node[i], rest)
+			}
+		}
+		return node, removed
+	}
+	return node, 0
+}
+
+// cloneValue returns a deep copy of a decoded document value.
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = cloneValue(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = cloneValue(child)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package jsonsql
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"$", nil},
+		{"", nil},
+		{"$.a.b", []string{`["a"]`, `["b"]`}},
+		{"a.b", []string{`["a"]`, `["b"]`}},
+		{"$['a.b'][\"c d\"]", []string{`["a.b"]`, `["c d"]`}},
+		{`$['it\'s']`, []string{`["it's"]`}},
+		{"$.items[0].sku", []string{`["items"]`, `[0]`, `["sku"]`}},
+		{"$.items[-1]", []string{`["items"]`, `[-1]`}},
+		{"$.items[*].tags.*", []string{`["items"]`, `[*]`, `["tags"]`, `[*]`}},
+		{"[0][1]", []string{`[0]`, `[1]`}},
+	}
+	for _, tt := range tests {
+		tokens, err := parsePath(tt.path)
+		if err != nil {
+			t.Errorf("parsePath(%q) failed: %v", tt.path, err)
+			continue
+		}
+		var got []string
+		for _, token := range tokens {
+			got = append(got, token.String())
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("parsePath(%q) = %v, want %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestParsePath_Invalid(t *testing.T) {
+	for _, path := range []string{"$.", "$..a", "$[", "$[x]", "$['a]", "$['a'", "$.a[0", "$a", "$.a]"} {
+		if _, err := parsePath(path); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("parsePath(%q): expected ErrInvalidPath, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package jsonsql
+
+import "reflect"
+
+// PersistPolicy selects which struct fields Value writes to the database, based on the
+// jsonsql struct tag. Scan is not affected and decodes every field present in the document.
+type PersistPolicy int
+
+const (
+	// PersistAll writes every JSON-visible field (default). The jsonsql tag is ignored.
+	PersistAll PersistPolicy = iota
+	// PersistUnlessExcluded drops fields tagged `jsonsql:"-"`, even if they have a json tag:
+	//
+	//	type User struct {
+	//		Name  string `json:"name"`
+	//		Token string `json:"token" jsonsql:"-"` // API only
+	//	}
+	PersistUnlessExcluded
+	// PersistTaggedOnly writes only fields tagged `jsonsql:"persist"` (which may be combined with
+	// other options, as in `jsonsql:"persist,required"`). It applies to every
+	// struct in the document, so fields of nested structs need the tag as well.
+	PersistTaggedOnly
+)
+
+// PersistFields sets which struct fields Value writes, so API-only fields never leak into
+// the database document. See PersistPolicy.
+func PersistFields(policy PersistPolicy) Option {
+	return func(c *config) {
+		c.persistPolicy = policy
+	}
+}
+
+// persists reports whether Value writes field f under the policy of c.
+func (c *config) persists(f field) bool {
+	switch c.persistPolicy {
+	case PersistUnlessExcluded:
+		return f.tag.Get("jsonsql") != "-"
+	case PersistTaggedOnly:
+		return hasTagOption(f.tag.Get("jsonsql"), "persist")
+	}
+	return true
+}
+
+// filtersFields reports whether the persist policy of c drops any field of struct type t.
+func (c *config) filtersFields(t reflect.Type) bool {
+	if c.persistPolicy == PersistAll {
+		return false
+	}
+	for _, f := range typeFields(t) {
+		if !c.persists(f) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package jsonsql
+
+import "testing"
+
+type testAPIUser struct {
+	Name    string          `json:"name" jsonsql:"persist"`
+	Token   string          `json:"token" jsonsql:"-"`
+	Display string          `json:"display"`
+	Address *testAPIAddress `json:"address,omitempty" jsonsql:"persist"`
+}
+
+type testAPIAddress struct {
+	City    string `json:"city" jsonsql:"persist"`
+	Preview string `json:"preview"`
+}
+
+func TestPersistFields(t *testing.T) {
+	user := testAPIUser{
+		Name:    "Ann",
+		Token:   "secret",
+		Display: "Ann A.",
+		Address: &testAPIAddress{City: "Oslo", Preview: "map.png"},
+	}
+	tests := []struct {
+		policy   PersistPolicy
+		expected string
+	}{
+		{PersistAll, `{"name":"Ann","token":"secret","display":"Ann A.","address":{"city":"Oslo","preview":"map.png"}}`},
+		{PersistUnlessExcluded, `{"name":"Ann","display":"Ann A.","address":{"city":"Oslo","preview":"map.png"}}`},
+		{PersistTaggedOnly, `{"name":"Ann","address":{"city":"Oslo"}}`},
+	}
+	for _, tt := range tests {
+		resetOptions[testAPIUser](t)
+		Configure[testAPIUser](PersistFields(tt.policy))
+
+		result, err := NewValue(user).Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if string(result.([]byte)) != tt.expected {
+			t.Errorf("policy %d: expected %s, got %s", tt.policy, tt.expected, result)
+		}
+	}
+}
+
+func TestPersistFields_ScanReadsAllFields(t *testing.T) {
+	resetOptions[testAPIUser](t)
+	Configure[testAPIUser](PersistFields(PersistTaggedOnly))
+
+	var v Value[testAPIUser]
+	if err := v.Scan(`{"name":"Ann","token":"legacy","display":"Ann A."}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Token != "legacy" || v.V.Display != "Ann A." {
+		t.Errorf("unexpected result: %+v", v.V)
+	}
+}
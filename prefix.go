@@ -0,0 +1,75 @@
+package jsonsql
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PrefixPolicy selects how Scan handles non-standard prefixes before the JSON value:
+// a UTF-8 byte order mark, Unicode whitespace that JSON does not allow (such as U+00A0)
+// and the )]}' anti-XSSI guard.
+type PrefixPolicy int
+
+const (
+	// PrefixSkip skips non-standard prefixes before decoding (default).
+	PrefixSkip PrefixPolicy = iota
+	// PrefixError makes Scan fail with ErrUnexpectedPrefix.
+	PrefixError
+)
+
+// ErrUnexpectedPrefix is returned by Scan when the data starts with a non-standard prefix
+// and the PrefixError policy is set.
+var ErrUnexpectedPrefix = permanent("jsonsql: unexpected prefix before JSON value")
+
+// Prefixes sets the policy Scan applies to non-standard prefixes, commonly found in data
+// imported from Windows tooling.
+func Prefixes(policy PrefixPolicy) Option {
+	return func(c *config) {
+		c.prefixPolicy = policy
+	}
+}
+
+var (
+	utf8BOM   = []byte("\xef\xbb\xbf")
+	xssiGuard = []byte(")]}'")
+)
+
+// trimPrefix applies policy to non-standard prefixes in data.
+func trimPrefix(data []byte, policy PrefixPolicy) ([]byte, error) {
+	rest, found := skipPrefix(data)
+	if !found {
+		return data, nil
+	}
+	if policy == PrefixError {
+		return nil, ErrUnexpectedPrefix
+	}
+	return rest, nil
+}
+
+// skipPrefix returns data without leading byte order marks, whitespace and anti-XSSI guards.
+// found reports whether anything other than standard JSON whitespace was skipped.
+func skipPrefix(data []byte) (rest []byte, found bool) {
+	for {
+		data = bytes.TrimLeft(data, " \t\r\n")
+		rest = bytes.TrimLeftFunc(data, unicode.IsSpace)
+		rest = bytes.TrimPrefix(rest, utf8BOM)
+		rest = bytes.TrimPrefix(rest, xssiGuard)
+		if len(rest) == len(data) {
+			return data, found
+		}
+		data, found = rest, true
+	}
+}
+
+// hasNonASCIIPrefix reports whether data may start with a non-standard prefix,
+// so the common case of plain JSON skips the prefix scan entirely.
+func hasNonASCIIPrefix(data []byte) bool {
+	for _, c := range data {
+		if isJSONSpace(c) {
+			continue
+		}
+		return c >= utf8.RuneSelf || c == ')'
+	}
+	return false
+}
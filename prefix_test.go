@@ -0,0 +1,64 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrefixes_Skip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{"BOM", []byte("\xef\xbb\xbf{\"name\":\"Alice\"}")},
+		{"BOM string", "\xef\xbb\xbf{\"name\":\"Alice\"}"},
+		{"whitespace then BOM", []byte(" \n\xef\xbb\xbf{\"name\":\"Alice\"}")},
+		{"no-break space", []byte("\u00a0{\"name\":\"Alice\"}")},
+		{"XSSI guard", []byte(")]}'\n{\"name\":\"Alice\"}")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testProfile]
+			if err := v.Scan(tt.input); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if v.V.Name != "Alice" {
+				t.Errorf("expected Name=Alice, got %s", v.V.Name)
+			}
+		})
+	}
+}
+
+func TestPrefixes_BOMOnly_IsNull(t *testing.T) {
+	n := NullableFrom(testProfile{Name: "Previous"})
+
+	if err := n.Scan([]byte("\xef\xbb\xbf")); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.Valid {
+		t.Error("expected Valid=false for BOM-only input")
+	}
+}
+
+func TestPrefixes_BOMThenNull(t *testing.T) {
+	var v Value[testProfile]
+
+	if err := v.Scan([]byte("\xef\xbb\xbfnull")); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestPrefixes_Error(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](Prefixes(PrefixError))
+
+	var v Value[testProfile]
+	if err := v.Scan([]byte("\xef\xbb\xbf{}")); !errors.Is(err, ErrUnexpectedPrefix) {
+		t.Errorf("expected ErrUnexpectedPrefix, got %v", err)
+	}
+
+	if err := v.Scan([]byte(" \n{\"name\":\"Alice\"}")); err != nil {
+		t.Errorf("expected standard whitespace to be accepted, got %v", err)
+	}
+}
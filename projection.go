@@ -0,0 +1,258 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Lazy[struct{}])(nil)
+	_ ContextScanner = (*Lazy[struct{}])(nil)
+	_ driver.Valuer  = Lazy[struct{}]{}
+	_ sql.Scanner    = (*Projection[struct{}, struct{}])(nil)
+	_ ContextScanner = (*Projection[struct{}, struct{}])(nil)
+	_ driver.Valuer  = Projection[struct{}, struct{}]{}
+)
+
+// Lazy[T] is a generic type for NOT NULL JSON columns that retains the raw document on Scan
+// and decodes it only on demand, either fully with Get or partially with DecodeAs.
+// Endpoints that need a few fields of a wide document can skip decoding the rest.
+// Value writes the retained document back unchanged unless Set was called.
+type Lazy[T any] struct {
+	raw     []byte
+	v       T
+	decoded bool
+}
+
+// NewLazy creates a new Lazy[T] holding v.
+func NewLazy[T any](v T) Lazy[T] {
+	return Lazy[T]{v: v, decoded: true}
+}
+
+// Raw returns the retained document, or nil if l was not scanned or Set was called since.
+func (l Lazy[T]) Raw() json.RawMessage {
+	return l.raw
+}
+
+// Get decodes the retained document into a T with the options registered for T,
+// caching the result for later calls.
+func (l *Lazy[T]) Get() (T, error) {
+	if l.retained() {
+		var v T
+		if err := decodeRaw(l.raw, &v); err != nil {
+			return v, fmt.Errorf("jsonsql.Lazy.Get: %w", err)
+		}
+		l.v = v
+		l.decoded = true
+	}
+	return l.v, nil
+}
+
+// retained reports whether l holds a scanned document that has not been decoded.
+// A zero Lazy[T] holds the zero value of T.
+func (l Lazy[T]) retained() bool {
+	return !l.decoded && l.raw != nil
+}
+
+// Set replaces the value with x; Value encodes x instead of the retained document.
+func (l *Lazy[T]) Set(x T) {
+	l.v = x
+	l.decoded = true
+	l.raw = nil
+}
+
+// Scan implements sql.Scanner interface.
+// It copies the document without decoding it, so malformed documents are only reported by Get
+// and DecodeAs. Returns ErrNullNotAllowed if src is nil or JSON literal "null".
+func (l *Lazy[T]) Scan(src any) error {
+	return l.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (l *Lazy[T]) ScanContext(ctx context.Context, src any) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("jsonsql.Lazy.Scan: %w", err)
+	}
+	src, err := resolveSource(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Lazy.Scan: %w", err)
+	}
+	if src == nil {
+		return ErrNullNotAllowed
+	}
+	var data []byte
+	switch s := src.(type) {
+	case json.RawMessage:
+		data = s
+	default:
+		if data, err = sourceBytes(src); err != nil {
+			return fmt.Errorf("jsonsql.Lazy.Scan: %w", err)
+		}
+	}
+	if isJSONNull(data) {
+		return ErrNullNotAllowed
+	}
+	var zero T
+	l.raw = bytes.Clone(data)
+	l.v = zero
+	l.decoded = false
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It returns the retained document unchanged, compacted with CompactOnWrite, or marshals
+// the value passed to NewLazy or Set.
+func (l Lazy[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	if l.retained() {
+		if !cfg.compact {
+			return cfg.driverValue(bytes.Clone(l.raw)), nil
+		}
+		data, err := compactDocument(l.raw)
+		if err != nil {
+			return nil, fmt.Errorf("jsonsql.Lazy.Value: %w", err)
+		}
+		return cfg.driverValue(data), nil
+	}
+	data, err := encodeValue(l.v, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Lazy.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+// DecodeAs decodes the document retained by l into a U, typically a struct declaring a subset
+// of the fields of T, with the options registered for U. Fields of the document that U does not
+// declare are skipped without being decoded:
+//
+//	type OrderSummary struct {
+//		ID     string `json:"id"`
+//		Status string `json:"status"`
+//	}
+//	summary, err := jsonsql.DecodeAs[OrderSummary](order) // order is a Lazy[Order]
+//
+// When l holds a value instead of a retained document, the value is encoded first.
+func DecodeAs[U, T any](l Lazy[T]) (U, error) {
+	var u U
+	data := l.raw
+	if !l.retained() {
+		var err error
+		if data, err = encodeDocument(l.v, configFor[T]()); err != nil {
+			return u, fmt.Errorf("jsonsql.DecodeAs: %w", err)
+		}
+	}
+	if err := decodeRaw(data, &u); err != nil {
+		return u, fmt.Errorf("jsonsql.DecodeAs: %w", err)
+	}
+	return u, nil
+}
+
+// decodeRaw decodes data into dst like Value[T].Scan.
+func decodeRaw[T any](data []byte, dst *T) error {
+	null, err := decodeSource(data, dst, ErrorOnEmpty)
+	if err != nil {
+		return err
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Projection[T, U] is a generic type for reading NOT NULL JSON columns holding documents of
+// type T into U, a struct declaring a subset of the fields of T. Only the fields of U are
+// decoded, so endpoints can read a few fields of a wide document cheaply.
+// The fields of U are checked against T on first use: Scan and Value fail with a
+// *TypeParamError if U declares a field that T does not have.
+// Documents are decoded and encoded with the options registered for U.
+type Projection[T, U any] struct {
+	V U
+}
+
+// Get returns the projected value.
+func (p Projection[T, U]) Get() U {
+	return p.V
+}
+
+// Scan implements sql.Scanner interface.
+// It behaves like Value[U].Scan.
+func (p *Projection[T, U]) Scan(src any) error {
+	return p.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (p *Projection[T, U]) ScanContext(ctx context.Context, src any) error {
+	if err := checkProjection[T, U](); err != nil {
+		return fmt.Errorf("jsonsql.Projection.Scan: %w", err)
+	}
+	null, err := decodeSourceContext(ctx, src, &p.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Projection.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V, which holds only the projected fields, so it is mainly useful for writing
+// partial documents, for example as the argument of a JSON merge update.
+func (p Projection[T, U]) Value() (driver.Value, error) {
+	if err := checkProjection[T, U](); err != nil {
+		return nil, fmt.Errorf("jsonsql.Projection.Value: %w", err)
+	}
+	cfg := configFor[U]()
+	data, err := encodeValue(p.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Projection.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+// projectionCache caches checkProjection results per pair of types.
+var projectionCache sync.Map // map[[2]reflect.Type]error
+
+// checkProjection reports fields of U, after dereferencing pointers, that struct type T
+// does not declare. Names are compared case-insensitively like encoding/json.
+// Non-struct types are not checked.
+func checkProjection[T, U any]() error {
+	key := [2]reflect.Type{reflect.TypeFor[T](), reflect.TypeFor[U]()}
+	if cached, ok := projectionCache.Load(key); ok {
+		err, _ := cached.(error)
+		return err
+	}
+
+	var err error
+	t, u := key[0], key[1]
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for u.Kind() == reflect.Pointer {
+		u = u.Elem()
+	}
+	if t.Kind() == reflect.Struct && u.Kind() == reflect.Struct {
+		names := map[string]bool{}
+		for _, f := range typeFields(t) {
+			names[strings.ToLower(f.name)] = true
+		}
+		for _, f := range typeFields(u) {
+			if !names[strings.ToLower(f.name)] {
+				err = &TypeParamError{Type: key[1], Reason: fmt.Sprintf("field %q is not a field of %v", f.name, key[0])}
+				break
+			}
+		}
+	}
+	projectionCache.Store(key, err)
+	return err
+}
@@ -0,0 +1,155 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type testWideOrder struct {
+	ID      string            `json:"id"`
+	Status  string            `json:"status"`
+	Items   []testOrderItem   `json:"items"`
+	Details map[string]string `json:"details"`
+}
+
+type testOrderSummary struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+const testWideOrderJSON = `{"id":"o1","status":"paid","items":[{"sku":"a","price":1}],"details":{"note":"x"}}`
+
+func TestLazy(t *testing.T) {
+	var l Lazy[testWideOrder]
+	src := []byte(testWideOrderJSON)
+	if err := l.Scan(src); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	src[2] = 'X' // drivers may reuse the buffer
+	if string(l.Raw()) != testWideOrderJSON {
+		t.Fatalf("expected raw document to be retained, got %s", l.Raw())
+	}
+
+	summary, err := DecodeAs[testOrderSummary](l)
+	if err != nil {
+		t.Fatalf("DecodeAs failed: %v", err)
+	}
+	if summary != (testOrderSummary{ID: "o1", Status: "paid"}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	order, err := l.Get()
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if order.ID != "o1" || len(order.Items) != 1 || order.Details["note"] != "x" {
+		t.Errorf("unexpected order %+v", order)
+	}
+
+	result, err := l.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != testWideOrderJSON {
+		t.Errorf("expected retained document, got %s", result)
+	}
+
+	order.Status = "shipped"
+	l.Set(order)
+	if l.Raw() != nil {
+		t.Errorf("expected Raw to be cleared by Set")
+	}
+	summary, err = DecodeAs[testOrderSummary](l)
+	if err != nil {
+		t.Fatalf("DecodeAs failed: %v", err)
+	}
+	if summary.Status != "shipped" {
+		t.Errorf("expected DecodeAs to see the value set, got %+v", summary)
+	}
+	result, err = l.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var stored testWideOrder
+	if err := json.Unmarshal(result.([]byte), &stored); err != nil || stored.Status != "shipped" {
+		t.Errorf("unexpected stored document %s, %v", result, err)
+	}
+}
+
+func TestLazy_Errors(t *testing.T) {
+	var l Lazy[testWideOrder]
+	if err := l.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for nil, got %v", err)
+	}
+	if err := l.Scan("null"); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed for null, got %v", err)
+	}
+	if err := l.Scan(42); !errors.Is(err, ErrUnsupportedSrcType) {
+		t.Errorf("expected ErrUnsupportedSrcType, got %v", err)
+	}
+
+	if err := l.Scan(`{"id":`); err != nil {
+		t.Fatalf("Scan should not decode: %v", err)
+	}
+	if _, err := l.Get(); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON from Get, got %v", err)
+	}
+	if _, err := DecodeAs[testOrderSummary](l); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON from DecodeAs, got %v", err)
+	}
+}
+
+func TestLazy_Zero(t *testing.T) {
+	var l Lazy[testOrderSummary]
+	result, err := l.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"id":"","status":""}` {
+		t.Errorf("expected zero value document, got %s", result)
+	}
+	if v, err := l.Get(); err != nil || v != (testOrderSummary{}) {
+		t.Errorf("expected zero value, got %+v, %v", v, err)
+	}
+	if _, err := DecodeAs[testOrderSummary](NewLazy(testOrderSummary{ID: "x"})); err != nil {
+		t.Errorf("DecodeAs failed: %v", err)
+	}
+}
+
+func TestProjection(t *testing.T) {
+	var p Projection[testWideOrder, testOrderSummary]
+	if err := p.Scan(testWideOrderJSON); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if p.Get() != (testOrderSummary{ID: "o1", Status: "paid"}) {
+		t.Errorf("unexpected projection %+v", p.V)
+	}
+	if err := p.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+
+	result, err := p.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"id":"o1","status":"paid"}` {
+		t.Errorf("unexpected result %s", result)
+	}
+}
+
+func TestProjection_UnknownField(t *testing.T) {
+	type bad struct {
+		ID    string `json:"id"`
+		Total int    `json:"total"`
+	}
+	var p Projection[testWideOrder, bad]
+	err := p.Scan(testWideOrderJSON)
+	var typeErr *TypeParamError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected *TypeParamError, got %v", err)
+	}
+	if _, err := p.Value(); !errors.As(err, &typeErr) {
+		t.Errorf("expected *TypeParamError from Value, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// rawMessageType is the reflect.Type of json.RawMessage.
+var rawMessageType = reflect.TypeFor[json.RawMessage]()
+
+// TrustRawJSON makes Scan and Value skip the well-formedness check of json.RawMessage documents,
+// for pass-through services whose data is already known to be valid JSON, such as documents
+// copied between tables. Invalid documents are then stored and scanned as is, and trailing data
+// after the first value is not detected on Scan.
+//
+// Documents of type parameter json.RawMessage never go through json.Marshal and json.Unmarshal:
+// Scan copies the document into the existing buffer of V, and Value stores a copy of V, without
+// the compaction and HTML escaping json.Marshal would apply. Options transforming documents, such
+// as StoredKeys, SanitizeUTF8, Indent and CompactOnWrite, still apply.
+func TrustRawJSON(enabled bool) Option {
+	return func(c *config) {
+		c.trustRaw = enabled
+	}
+}
+
+// CompactOnWrite makes Value strip insignificant whitespace from documents it passes through
+// unchanged, keeping stored documents minimal when they come from pretty-printed sources:
+// json.RawMessage documents, CheckedRaw and the retained documents of Lazy[T]. Encoded
+// documents are always compact unless Indent is configured, which takes precedence.
+// Compaction checks the document, so it is never skipped by TrustRawJSON.
+func CompactOnWrite(enabled bool) Option {
+	return func(c *config) {
+		c.compact = enabled
+	}
+}
+
+// compactDocument returns the JSON document data without insignificant whitespace.
+func compactDocument(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, invalidJSON(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scanRaw stores the JSON document data in *raw without decoding it, reusing its buffer like
+// json.RawMessage.UnmarshalJSON. It reports false, leaving *raw untouched, for documents that
+// are not valid JSON so that the regular decoding reports the error.
+func scanRaw(data []byte, raw *json.RawMessage, cfg *config) bool {
+	data = bytes.TrimSpace(data)
+	if !cfg.trustRaw && !json.Valid(data) {
+		return false
+	}
+	*raw = append((*raw)[:0], data...)
+	return true
+}
+
+// encodeRaw returns the stored form of the json.RawMessage document raw without encoding it.
+// A nil document is stored as JSON null, like json.Marshal, and invalid documents are left
+// to encoding/json so that it reports the error.
+func encodeRaw(raw json.RawMessage, cfg *config) ([]byte, error) {
+	if raw == nil {
+		return []byte("null"), nil
+	}
+	if cfg.compact {
+		if data, err := compactDocument(raw); err == nil {
+			return data, nil
+		}
+		return encodeJSON(raw, cfg)
+	}
+	if !cfg.trustRaw && !json.Valid(raw) {
+		return encodeJSON(raw, cfg)
+	}
+	// Copy, as the next Scan into the same json.RawMessage reuses its buffer.
+	return bytes.Clone(raw), nil
+}
@@ -0,0 +1,150 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRawMessage_PassThrough(t *testing.T) {
+	doc := json.RawMessage(`{"b": 1, "a": "<tag>"}`)
+	result, err := NewValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != string(doc) {
+		t.Errorf("expected document stored as is, got %s", result)
+	}
+
+	var v Value[json.RawMessage]
+	if err := v.Scan([]byte(" [1, 2] ")); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if string(v.V) != "[1, 2]" {
+		t.Errorf("unexpected value %s", v.V)
+	}
+
+	// Scan copies the source, which drivers may reuse.
+	src := []byte(`{"id":1}`)
+	if err := v.Scan(src); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	src[2] = 'x'
+	if string(v.V) != `{"id":1}` {
+		t.Errorf("Scan retained the source buffer: %s", v.V)
+	}
+
+	if err := v.Scan(`{"id":`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+	if err := v.Scan(`{} {}`); !errors.Is(err, ErrTrailingData) {
+		t.Errorf("expected ErrTrailingData, got %v", err)
+	}
+	if _, err := NewValue(json.RawMessage(`{"id":`)).Value(); err == nil {
+		t.Error("expected error for invalid document on Value")
+	}
+	if result, err := NewValue(json.RawMessage(nil)).Value(); err != nil || string(result.([]byte)) != "null" {
+		t.Errorf("expected null, got %s, %v", result, err)
+	}
+}
+
+func TestRawMessage_ValueDoesNotAliasScanBuffer(t *testing.T) {
+	var v Value[json.RawMessage]
+	var stored []string
+	var results []any
+	for _, doc := range []string{`{"row":1}`, `{"row":2}`} {
+		if err := v.Scan([]byte(doc)); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		result, err := v.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		stored = append(stored, doc)
+		results = append(results, result)
+	}
+	for i, result := range results {
+		if string(result.([]byte)) != stored[i] {
+			t.Errorf("row %d: expected %s, got %s", i, stored[i], result)
+		}
+	}
+}
+
+func TestTrustRawJSON(t *testing.T) {
+	resetOptions[json.RawMessage](t)
+	Configure[json.RawMessage](TrustRawJSON(true))
+
+	var v Value[json.RawMessage]
+	if err := v.Scan(`{"id":`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if string(v.V) != `{"id":` {
+		t.Errorf("unexpected value %s", v.V)
+	}
+	if _, err := NewValue(v.V).Value(); err != nil {
+		t.Errorf("Value failed: %v", err)
+	}
+}
+
+func BenchmarkRawMessage_Scan(b *testing.B) {
+	src := []byte(`{"id":1,"name":"Alice","tags":["a","b","c"],"address":{"city":"Tokyo"}}`)
+	var v Value[json.RawMessage]
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := v.Scan(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCompactOnWrite(t *testing.T) {
+	resetOptions[json.RawMessage](t)
+	resetOptions[CheckedRaw](t)
+	resetOptions[testProfile](t)
+	SetDefaults(CompactOnWrite(true))
+
+	pretty := "{\n  \"name\": \"Alice\",\n  \"tags\": [ 1, 2 ]\n}"
+	expected := `{"name":"Alice","tags":[1,2]}`
+
+	result, err := NewValue(json.RawMessage(pretty)).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	result, err = CheckedRaw(pretty).Value()
+	if err != nil {
+		t.Fatalf("CheckedRaw.Value failed: %v", err)
+	}
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var l Lazy[testProfile]
+	if err := l.Scan("{\n  \"name\": \"Alice\"\n}"); err != nil {
+		t.Fatalf("Lazy.Scan failed: %v", err)
+	}
+	result, err = l.Value()
+	if err != nil {
+		t.Fatalf("Lazy.Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"name":"Alice"}` {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	if _, err := NewValue(json.RawMessage(`{"name":`)).Value(); err == nil {
+		t.Error("expected error for invalid document")
+	}
+
+	// Indent takes precedence.
+	Configure[json.RawMessage](Indent("  "))
+	result, err = NewValue(json.RawMessage(`{"a": 1}`)).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != "{\n  \"a\": 1\n}" {
+		t.Errorf("unexpected document %q", result)
+	}
+}
@@ -0,0 +1,194 @@
+package jsonsql
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner              = (*RawNumber)(nil)
+	_ driver.Valuer            = RawNumber("")
+	_ json.Marshaler           = RawNumber("")
+	_ json.Unmarshaler         = (*RawNumber)(nil)
+	_ encoding.TextMarshaler   = RawNumber("")
+	_ encoding.TextUnmarshaler = (*RawNumber)(nil)
+)
+
+// rawNumberType is the reflect.Type of RawNumber.
+var rawNumberType = reflect.TypeFor[RawNumber]()
+
+// RawNumber is a number kept as its exact decimal text, for money and other precision-critical
+// fields that must never pass through float64. Inside documents it is written as a bare JSON
+// number and read from a JSON number or a string holding one; the empty RawNumber is JSON null.
+//
+// Register StringNumber[RawNumber]() to store it as a JSON string instead, for readers that
+// parse all numbers as float64 and for the Cbor and Msgpack formats, which store non-integer
+// numbers as float64.
+//
+// RawNumber is also a Scanner and Valuer for NUMERIC columns, scanning the driver's text
+// representation and storing the empty RawNumber as NULL.
+type RawNumber string
+
+// String returns the number text.
+func (n RawNumber) String() string {
+	return string(n)
+}
+
+// Int64 returns the number as an int64.
+func (n RawNumber) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Float64 returns the number as a float64, which may lose precision.
+func (n RawNumber) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Rat returns the exact value of the number as a big.Rat.
+func (n RawNumber) Rat() (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok || !isJSONNumber(string(n)) {
+		return nil, fmt.Errorf("jsonsql: invalid number %q", string(n))
+	}
+	return r, nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (n RawNumber) MarshalJSON() ([]byte, error) {
+	if n == "" {
+		return []byte("null"), nil
+	}
+	if !isJSONNumber(string(n)) {
+		return nil, fmt.Errorf("jsonsql: invalid number %q", string(n))
+	}
+	return []byte(n), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (n *RawNumber) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		*n = ""
+		return nil
+	}
+	text, err := numberText(data, rawNumberType)
+	if err != nil {
+		return err
+	}
+	return n.UnmarshalText(text)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (n RawNumber) MarshalText() ([]byte, error) {
+	if n != "" && !isJSONNumber(string(n)) {
+		return nil, fmt.Errorf("jsonsql: invalid number %q", string(n))
+	}
+	return []byte(n), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+// It accepts the JSON number syntax and the empty string.
+func (n *RawNumber) UnmarshalText(text []byte) error {
+	s := string(bytes.TrimSpace(text))
+	if s != "" && !isJSONNumber(s) {
+		return fmt.Errorf("jsonsql: invalid number %q", s)
+	}
+	*n = RawNumber(s)
+	return nil
+}
+
+// Scan implements sql.Scanner interface.
+// It accepts the textual and numeric values drivers return for NUMERIC columns;
+// float64 values are converted with the shortest exact representation. NULL scans as "".
+func (n *RawNumber) Scan(src any) error {
+	src, err := resolveSource(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.RawNumber.Scan: %w", err)
+	}
+	switch s := src.(type) {
+	case nil:
+		*n = ""
+		return nil
+	case int64:
+		*n = RawNumber(strconv.FormatInt(s, 10))
+		return nil
+	case float64:
+		*n = RawNumber(strconv.FormatFloat(s, 'g', -1, 64))
+		return nil
+	case []byte:
+		return n.scanText(s)
+	case json.RawMessage:
+		return n.scanText(s)
+	case string:
+		return n.scanText([]byte(s))
+	}
+	return fmt.Errorf("jsonsql.RawNumber.Scan: %w", unsupportedSrcType(src))
+}
+
+func (n *RawNumber) scanText(text []byte) error {
+	if err := n.UnmarshalText(text); err != nil {
+		return fmt.Errorf("jsonsql.RawNumber.Scan: %w", err)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It returns the number text, or nil (NULL) for the empty RawNumber.
+func (n RawNumber) Value() (driver.Value, error) {
+	if n == "" {
+		return nil, nil
+	}
+	if !isJSONNumber(string(n)) {
+		return nil, fmt.Errorf("jsonsql.RawNumber.Value: invalid number %q", string(n))
+	}
+	return string(n), nil
+}
+
+// isJSONNumber reports whether s is a number in JSON syntax.
+func isJSONNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(s) && s[i] == '0':
+		i++
+	case i < len(s) && s[i] >= '1' && s[i] <= '9':
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == len(s)
+}
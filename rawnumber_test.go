@@ -0,0 +1,111 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+type testPrice struct {
+	Total    RawNumber `json:"total"`
+	Discount RawNumber `json:"discount"`
+}
+
+func TestRawNumber_Document(t *testing.T) {
+	const doc = `{"total":12345678901234567890.123456789,"discount":"0.10"}`
+	var v Value[testPrice]
+	if err := v.Scan(doc); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Total != "12345678901234567890.123456789" || v.V.Discount != "0.10" {
+		t.Errorf("unexpected value %+v", v.V)
+	}
+	result, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"total":12345678901234567890.123456789,"discount":0.10}` {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	if err := v.Scan(`{"total":null}`); err != nil || v.V.Total != "" {
+		t.Errorf("expected null to scan as empty, got %+v, %v", v.V, err)
+	}
+	for _, bad := range []string{`{"total":"abc"}`, `{"total":true}`, `{"total":"1e"}`} {
+		if err := v.Scan(bad); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	if _, err := NewValue(testPrice{Total: "1,5"}).Value(); err == nil {
+		t.Error("expected error for invalid number")
+	}
+	if data, err := json.Marshal(testPrice{}); err != nil || string(data) != `{"total":null,"discount":null}` {
+		t.Errorf("unexpected empty document %s, %v", data, err)
+	}
+}
+
+func TestRawNumber_StringNumber(t *testing.T) {
+	resetOptions[testPrice](t)
+	Configure[testPrice](StringNumber[RawNumber]())
+
+	result, err := NewValue(testPrice{Total: "19.99", Discount: "1e-2"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"total":"19.99","discount":"1e-2"}` {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	var c Cbor[testPrice]
+	c.V = testPrice{Total: "0.1"}
+	stored, err := c.Value()
+	if err != nil {
+		t.Fatalf("Cbor.Value failed: %v", err)
+	}
+	if err := c.Scan(stored); err != nil || c.V.Total != "0.1" {
+		t.Errorf("expected exact round trip through CBOR, got %+v, %v", c.V, err)
+	}
+}
+
+func TestRawNumber_Column(t *testing.T) {
+	var n RawNumber
+	for src, expected := range map[any]RawNumber{
+		"12.50":        "12.50",
+		int64(-7):      "-7",
+		float64(0.1):   "0.1",
+		" 3 ":          "3",
+		nil:            "",
+		RawNumber("9"): "9",
+	} {
+		if err := n.Scan(src); err != nil || n != expected {
+			t.Errorf("Scan(%#v): expected %q, got %q, %v", src, expected, n, err)
+		}
+	}
+	if err := n.Scan([]byte("1.2.3")); err == nil {
+		t.Error("expected error for invalid number")
+	}
+	if err := n.Scan(true); err == nil {
+		t.Error("expected error for bool")
+	}
+
+	if v, err := RawNumber("").Value(); err != nil || v != nil {
+		t.Errorf("expected NULL, got %v, %v", v, err)
+	}
+	if v, err := RawNumber("1.10").Value(); err != nil || v != "1.10" {
+		t.Errorf("unexpected value %v, %v", v, err)
+	}
+
+	r, err := RawNumber("0.10").Rat()
+	if err != nil || r.Cmp(big.NewRat(1, 10)) != 0 {
+		t.Errorf("unexpected Rat %v, %v", r, err)
+	}
+	if _, err := RawNumber("1/3").Rat(); err == nil {
+		t.Error("expected error for fraction syntax")
+	}
+	if i, err := RawNumber("42").Int64(); err != nil || i != 42 {
+		t.Errorf("unexpected Int64 %d, %v", i, err)
+	}
+	if f, err := RawNumber("2.5").Float64(); err != nil || f != 2.5 {
+		t.Errorf("unexpected Float64 %v, %v", f, err)
+	}
+}
@@ -0,0 +1,60 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*ReadOnly[struct{}])(nil)
+	_ ContextScanner = (*ReadOnly[struct{}])(nil)
+	_ driver.Valuer  = ReadOnly[struct{}]{}
+)
+
+// ErrReadOnly is returned by ReadOnly.Value.
+var ErrReadOnly = permanent("jsonsql: read-only value cannot be written")
+
+// ReadOnly[T] is a generic type for JSON results that must never be written back, such as
+// computed or aggregated SELECT expressions. It scans like Nullable[T], since aggregates such as
+// json_agg return NULL for empty inputs, but Value always fails with ErrReadOnly, so passing it
+// to a generic save path by accident is caught instead of overwriting a column.
+type ReadOnly[T any] struct {
+	V     T
+	Valid bool
+}
+
+// Get returns the value and a boolean indicating whether it is valid.
+func (r ReadOnly[T]) Get() (T, bool) {
+	return r.V, r.Valid
+}
+
+// Scan implements sql.Scanner interface.
+// Sets Valid=false for nil or JSON literal "null".
+func (r *ReadOnly[T]) Scan(src any) error {
+	return r.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (r *ReadOnly[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeSourceContext(ctx, src, &r.V, NullOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.ReadOnly.Scan: %w", err)
+	}
+	if null {
+		var zero T
+		r.V, r.Valid = zero, false
+		return nil
+	}
+	r.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It always returns ErrReadOnly.
+func (r ReadOnly[T]) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("jsonsql.ReadOnly.Value: %w", ErrReadOnly)
+}
@@ -0,0 +1,30 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadOnly_Scan(t *testing.T) {
+	var r ReadOnly[[]testProfile]
+	if err := r.Scan(`[{"name":"Ann","email":"ann@example.com"}]`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v, ok := r.Get(); !ok || len(v) != 1 || v[0].Name != "Ann" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+
+	if err := r.Scan(nil); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if r.Valid || r.V != nil {
+		t.Errorf("expected NULL, got %+v", r)
+	}
+}
+
+func TestReadOnly_Value(t *testing.T) {
+	r := ReadOnly[testProfile]{V: testProfile{Name: "Ann"}, Valid: true}
+	if _, err := r.Value(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ErrMissingField is reported by Scan for a struct field tagged `jsonsql:"required"` that is
+// absent from the stored document. Presence is what counts, so an explicit zero value or null
+// satisfies the check:
+//
+//	type Order struct {
+//		ID       string `json:"id" jsonsql:"required"`
+//		Currency string `json:"currency" jsonsql:"required"`
+//		Note     string `json:"note"`
+//	}
+//
+// Every missing field is reported as a *FieldError with the JSON Pointer of the field,
+// joined into one error. Required fields of nested structs are checked where the enclosing
+// value is present. Keys are matched like encoding/json (case-insensitively, honoring
+// KeyAlias aliases), and types with their own JSON decoding or a type hook are not inspected.
+var ErrMissingField = permanent("jsonsql: required field missing")
+
+// requiredCache caches hasRequired results per type.
+var requiredCache sync.Map // map[reflect.Type]bool
+
+// hasRequired reports whether values of type t can contain fields tagged required.
+func hasRequired(t reflect.Type) bool {
+	if r, ok := requiredCache.Load(t); ok {
+		return r.(bool)
+	}
+	r := computeHasRequired(t, map[reflect.Type]bool{})
+	requiredCache.Store(t, r)
+	return r
+}
+
+// computeHasRequired implements hasRequired; visiting breaks cycles of recursive types.
+func computeHasRequired(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] || implementsJSON(t) {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return computeHasRequired(t.Elem(), visiting)
+	case reflect.Struct:
+		for _, f := range typeFields(t) {
+			if isRequired(f) || computeHasRequired(f.typ, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRequired reports whether field f is tagged `jsonsql:"required"`.
+func isRequired(f field) bool {
+	return hasTagOption(f.tag.Get("jsonsql"), "required")
+}
+
+// checkRequired reports the required fields of type t missing from the JSON document data.
+func checkRequired(data []byte, t reflect.Type, cfg *config) error {
+	if !hasRequired(t) {
+		return nil
+	}
+	w := &walker{cfg: cfg}
+	w.checkRequired(bytes.TrimSpace(data), t)
+	return errors.Join(w.errs...)
+}
+
+// checkRequired records a *FieldError in w.errs for every required field of t missing from data.
+// Data that does not match the shape of t is skipped, as decoding has already succeeded.
+func (w *walker) checkRequired(data []byte, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if _, ok := w.cfg.hooks[t]; ok || !hasRequired(t) || isJSONNull(data) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		aliases := w.cfg.aliases[t]
+		for _, f := range typeFields(t) {
+			raw, ok := lookupKey(obj, f.name)
+			for _, alias := range aliases[f.name] {
+				if ok {
+					break
+				}
+				raw, ok = lookupKey(obj, alias)
+			}
+			w.path = append(w.path, f.name)
+			if ok {
+				w.checkRequired(raw, f.typ)
+			} else if isRequired(f) {
+				w.errs = append(w.errs, &FieldError{Path: w.pointer(), Err: ErrMissingField})
+			}
+			w.path = w.path[:len(w.path)-1]
+		}
+
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return
+		}
+		for i, raw := range elems {
+			w.path = append(w.path, strconv.Itoa(i))
+			w.checkRequired(raw, t.Elem())
+			w.path = w.path[:len(w.path)-1]
+		}
+
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		for _, k := range slices.Sorted(maps.Keys(obj)) {
+			w.path = append(w.path, k)
+			w.checkRequired(obj[k], t.Elem())
+			w.path = w.path[:len(w.path)-1]
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package jsonsql
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type testRequiredOrder struct {
+	ID       string                      `json:"id" jsonsql:"required"`
+	Currency string                      `json:"currency" jsonsql:"required,persist"`
+	Note     string                      `json:"note"`
+	Customer *testRequiredCustomer       `json:"customer"`
+	Lines    []testRequiredLine          `json:"lines"`
+	ByCode   map[string]testRequiredLine `json:"by_code"`
+}
+
+type testRequiredCustomer struct {
+	Email string `json:"email" jsonsql:"required"`
+}
+
+type testRequiredLine struct {
+	SKU string `json:"sku" jsonsql:"required"`
+	Qty int    `json:"qty"`
+}
+
+func missingPaths(t *testing.T, err error) []string {
+	t.Helper()
+	var paths []string
+	for _, e := range fieldErrorsOf(err) {
+		if !errors.Is(e, ErrMissingField) {
+			t.Fatalf("unexpected error %v", e)
+		}
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+func fieldErrorsOf(err error) []*FieldError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *FieldError:
+		return []*FieldError{e}
+	case interface{ Unwrap() []error }:
+		var out []*FieldError
+		for _, inner := range e.Unwrap() {
+			out = append(out, fieldErrorsOf(inner)...)
+		}
+		return out
+	}
+	return fieldErrorsOf(errors.Unwrap(err))
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		missing []string
+	}{
+		{"all present", `{"id":"1","currency":"EUR"}`, nil},
+		{"zero values count as present", `{"id":"","currency":null}`, nil},
+		{"case-insensitive", `{"ID":"1","Currency":"EUR"}`, nil},
+		{"missing", `{"note":"x"}`, []string{"/id", "/currency"}},
+		{"nested", `{"id":"1","currency":"EUR","customer":{},"lines":[{"sku":"a"},{"qty":1}],"by_code":{"z":{},"a":{}}}`,
+			[]string{"/customer/email", "/lines/1/sku", "/by_code/a/sku", "/by_code/z/sku"}},
+		{"null nested", `{"id":"1","currency":"EUR","customer":null}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testRequiredOrder]
+			err := v.Scan(tt.doc)
+			if tt.missing == nil {
+				if err != nil {
+					t.Fatalf("Scan failed: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMissingField) {
+				t.Fatalf("expected ErrMissingField, got %v", err)
+			}
+			if got := missingPaths(t, err); !slices.Equal(got, tt.missing) {
+				t.Errorf("expected %v, got %v", tt.missing, got)
+			}
+		})
+	}
+}
+
+func TestRequired_Nullable(t *testing.T) {
+	var n Nullable[testRequiredOrder]
+	if err := n.Scan(`{"id":"1"}`); !errors.Is(err, ErrMissingField) {
+		t.Fatalf("expected ErrMissingField, got %v", err)
+	}
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("expected NULL to be allowed, got %+v, %v", n, err)
+	}
+}
+
+func TestRequired_Alias(t *testing.T) {
+	resetOptions[testRequiredOrder](t)
+	Configure[testRequiredOrder](KeyAlias[testRequiredOrder]("currency", "ccy"))
+
+	var v Value[testRequiredOrder]
+	if err := v.Scan(`{"id":"1","ccy":"EUR"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V.Currency != "EUR" {
+		t.Errorf("expected alias to be decoded, got %+v", v.V)
+	}
+}
+
+func TestRequired_CollectErrors(t *testing.T) {
+	resetOptions[testRequiredOrder](t)
+	Configure[testRequiredOrder](CollectErrors(true))
+
+	var v Value[testRequiredOrder]
+	err := v.Scan(`{"currency":"EUR","lines":[{"sku":1}]}`)
+	paths := []string{}
+	for _, fe := range fieldErrorsOf(err) {
+		paths = append(paths, fe.Path)
+	}
+	if !slices.Equal(paths, []string{"/lines/0/sku", "/id"}) {
+		t.Errorf("unexpected errors %v", err)
+	}
+}
+
+func TestRequired_PersistTag(t *testing.T) {
+	resetOptions[testRequiredOrder](t)
+	Configure[testRequiredOrder](PersistFields(PersistTaggedOnly))
+
+	result, err := NewValue(testRequiredOrder{ID: "1", Currency: "EUR"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"currency":"EUR"}` {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
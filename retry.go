@@ -0,0 +1,86 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// permanentError is the type of the jsonsql sentinel errors. Each describes a problem with the
+// stored data or the Go value, so retrying the same operation fails the same way.
+type permanentError struct {
+	msg string
+}
+
+// permanent returns a new sentinel error classified as permanent.
+func permanent(msg string) error {
+	return &permanentError{msg: msg}
+}
+
+// Error implements the error interface.
+func (e *permanentError) Error() string {
+	return e.msg
+}
+
+// Temporary reports false: the error does not go away on retry.
+func (e *permanentError) Temporary() bool {
+	return false
+}
+
+// Permanent reports true: the row or value should be dead-lettered or fixed instead of retried.
+func (e *permanentError) Permanent() bool {
+	return true
+}
+
+// Temporary reports false; see permanentError.
+func (e *TypeParamError) Temporary() bool {
+	return false
+}
+
+// Permanent reports true: the type parameter can never be encoded or decoded.
+func (e *TypeParamError) Permanent() bool {
+	return true
+}
+
+// IsPermanent reports whether err, or an error it wraps, is a failure that retrying cannot fix:
+// a jsonsql sentinel error such as ErrNullNotAllowed or ErrInvalidJSON, a *TypeParamError,
+// or a JSON decode error from encoding/json. Generic repository layers can use it to dead-letter
+// a row instead of retrying the query.
+func IsPermanent(err error) bool {
+	if err == nil || IsTemporary(err) {
+		return false
+	}
+	var p interface{ Permanent() bool }
+	if errors.As(err, &p) && p.Permanent() {
+		return true
+	}
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		fieldErr  *FieldError
+	)
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.As(err, &fieldErr)
+}
+
+// IsTemporary reports whether err, or an error it wraps, is a failure that may succeed on retry:
+// an error reporting Temporary() == true such as context.DeadlineExceeded from ScanContext,
+// or driver.ErrBadConn. It is suitable as the retry predicate of database/sql retry wrappers,
+// which usually retry on driver.ErrBadConn only:
+//
+//	if jsonsql.IsTemporary(err) {
+//		continue // retry the query
+//	}
+//
+// Errors that are neither temporary nor permanent, such as unknown driver errors,
+// are left to the caller's policy.
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
@@ -0,0 +1,58 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsPermanent(t *testing.T) {
+	var v Value[testProfile]
+	scanErr := func(src any) error {
+		return v.Scan(src)
+	}
+	var nested Value[struct{ C chan int }]
+
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+		temporary bool
+	}{
+		{"nil", nil, false, false},
+		{"null", scanErr(nil), true, false},
+		{"syntax", scanErr(`{`), true, false},
+		{"type", scanErr(`{"name":1}`), true, false},
+		{"unsupported", scanErr(42), true, false},
+		{"type parameter", nested.Scan(`{}`), true, false},
+		{"wrapped sentinel", fmt.Errorf("repo: %w", ErrNullNotAllowed), true, false},
+		{"bad conn", fmt.Errorf("query: %w", driver.ErrBadConn), false, true},
+		{"deadline", fmt.Errorf("scan: %w", context.DeadlineExceeded), false, true},
+		{"unknown", errors.New("connection refused"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.permanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", tt.err, got, tt.permanent)
+			}
+			if got := IsTemporary(tt.err); got != tt.temporary {
+				t.Errorf("IsTemporary(%v) = %v, want %v", tt.err, got, tt.temporary)
+			}
+		})
+	}
+}
+
+func TestSentinels_Classification(t *testing.T) {
+	var p interface {
+		Temporary() bool
+		Permanent() bool
+	}
+	if !errors.As(ErrNullNotAllowed, &p) || p.Temporary() || !p.Permanent() {
+		t.Error("expected ErrNullNotAllowed to be classified as permanent")
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", ErrNullNotAllowed), ErrNullNotAllowed) {
+		t.Error("expected errors.Is to match the sentinel")
+	}
+}
@@ -0,0 +1,169 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Revisioned[struct{}])(nil)
+	_ ContextScanner = (*Revisioned[struct{}])(nil)
+	_ driver.Valuer  = Revisioned[struct{}]{}
+)
+
+// RevisionKey is the key of the revision in documents stored by Revisioned[T].
+const RevisionKey = "_rev"
+
+// ErrRevisionConflict is returned by CheckRevision when an update matched no row because
+// the document was changed concurrently.
+var ErrRevisionConflict = permanent("jsonsql: document revision changed concurrently")
+
+// Revisioned[T] is a generic type for NOT NULL JSON columns updated with optimistic locking,
+// without an extra version column. Value stores V, which must encode as a JSON object, with
+// the next revision added under RevisionKey:
+//
+//	{"_rev":4,"name":"Alice"}
+//
+// Rev is the revision read by Scan, 0 for new documents, and updates are made conditional on it:
+//
+//	res, err := db.ExecContext(ctx, jsonsql.UpdateRevisioned(jsonsql.Postgres, "users", "doc", "id"),
+//		doc, id, doc.Rev)
+//	if err := jsonsql.CheckRevision(res, err); err != nil {
+//		return err // ErrRevisionConflict: reload and retry
+//	}
+//	doc.Advance()
+//
+// T must not have a field of its own stored under RevisionKey.
+type Revisioned[T any] struct {
+	V T
+	// Rev is the revision of the stored document V was read from.
+	Rev int64
+}
+
+// NewRevisioned creates a new Revisioned[T] with the given value for its first write.
+func NewRevisioned[T any](v T) Revisioned[T] {
+	return Revisioned[T]{V: v}
+}
+
+// Get returns the value.
+func (r Revisioned[T]) Get() T {
+	return r.V
+}
+
+// NextRev returns the revision stored by Value.
+func (r Revisioned[T]) NextRev() int64 {
+	return r.Rev + 1
+}
+
+// Advance sets Rev to the revision stored by Value, after the write succeeded,
+// so that r can be written again.
+func (r *Revisioned[T]) Advance() {
+	r.Rev++
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals JSON data from the database into V and reads the revision, which is 0 for
+// documents written without one.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (r *Revisioned[T]) Scan(src any) error {
+	return r.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (r *Revisioned[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeSourceContext(ctx, src, &r.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Revisioned.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	var doc struct {
+		Rev *int64 `json:"_rev"`
+	}
+	if err := scanMetadata[T](src, &doc); err != nil {
+		return fmt.Errorf("jsonsql.Revisioned.Scan: %s: %w", RevisionKey, err)
+	}
+	r.Rev = 0
+	if doc.Rev != nil {
+		r.Rev = *doc.Rev
+	}
+	dropMetadataKey(&r.V, RevisionKey)
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V to a JSON object with the next revision for database storage.
+func (r Revisioned[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := r.encode(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Revisioned.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+func (r Revisioned[T]) encode(cfg *config) ([]byte, error) {
+	if cfg.documentCodec != nil {
+		return nil, errors.New("jsonsql: Revisioned does not support document codecs")
+	}
+	data, err := encodeValue(r.V, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return withLeadingKey(data, RevisionKey, strconv.AppendInt(nil, r.NextRev(), 10), "Revisioned")
+}
+
+// RevisionMatches returns an SQL condition matching rows whose JSON column stores a
+// Revisioned document with the revision given by the query parameter param. Documents
+// without a revision match revision 0. column and param are inserted as is and must be trusted.
+func RevisionMatches(d Dialect, column, param string) string {
+	switch d {
+	case MySQL:
+		return "COALESCE(JSON_EXTRACT(" + column + ", '$." + RevisionKey + "'), 0) = " + param
+	case SQLite:
+		return "COALESCE(json_extract(" + column + ", '$." + RevisionKey + "'), 0) = " + param
+	default:
+		return "COALESCE((" + column + " ->> '" + RevisionKey + "')::bigint, 0) = " + param
+	}
+}
+
+// UpdateRevisioned returns an UPDATE statement writing a Revisioned document to column of
+// the row of table identified by keyColumn, only if the stored revision is unchanged.
+// Its parameters are the document, the key and the expected revision (Rev), in that order:
+//
+//	UPDATE users SET doc = $1 WHERE id = $2 AND COALESCE((doc ->> '_rev')::bigint, 0) = $3
+//
+// Identifiers are inserted as is and must be trusted.
+func UpdateRevisioned(d Dialect, table, column, keyColumn string) string {
+	if d == Postgres {
+		return "UPDATE " + table + " SET " + column + " = $1 WHERE " + keyColumn + " = $2 AND " +
+			RevisionMatches(d, column, "$3")
+	}
+	return "UPDATE " + table + " SET " + column + " = ? WHERE " + keyColumn + " = ? AND " +
+		RevisionMatches(d, column, "?")
+}
+
+// CheckRevision returns err if the update failed, and ErrRevisionConflict if it matched no row.
+// It takes the results of Exec directly:
+//
+//	err := jsonsql.CheckRevision(db.ExecContext(ctx, query, doc, id, doc.Rev))
+func CheckRevision(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrRevisionConflict
+	}
+	return nil
+}
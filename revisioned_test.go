@@ -0,0 +1,96 @@
+package jsonsql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testResult int64
+
+func (r testResult) LastInsertId() (int64, error) { return 0, nil }
+func (r testResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestRevisioned(t *testing.T) {
+	r := NewRevisioned(testProfile{Name: "Alice"})
+	result, err := r.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"_rev":1,"name":"Alice","email":""}` {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	var scanned Revisioned[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.Rev != 1 || scanned.V.Name != "Alice" || scanned.NextRev() != 2 {
+		t.Errorf("unexpected value %+v", scanned)
+	}
+	scanned.Advance()
+	result, err = scanned.Value()
+	if err != nil || !strings.HasPrefix(string(result.([]byte)), `{"_rev":3,`) {
+		t.Errorf("unexpected document %s, %v", result, err)
+	}
+
+	if err := scanned.Scan(`{"name":"Legacy"}`); err != nil || scanned.Rev != 0 {
+		t.Errorf("expected documents without revision to scan as 0, got %+v, %v", scanned, err)
+	}
+	if err := scanned.Scan(`{"_rev":"x"}`); err == nil || !strings.Contains(err.Error(), "_rev") {
+		t.Errorf("expected _rev error, got %v", err)
+	}
+	if err := scanned.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if _, err := NewRevisioned("text").Value(); err == nil || !strings.Contains(err.Error(), "requires a JSON object") {
+		t.Errorf("expected object error, got %v", err)
+	}
+}
+
+func TestUpdateRevisioned(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, `UPDATE users SET doc = $1 WHERE id = $2 AND COALESCE((doc ->> '_rev')::bigint, 0) = $3`},
+		{MySQL, `UPDATE users SET doc = ? WHERE id = ? AND COALESCE(JSON_EXTRACT(doc, '$._rev'), 0) = ?`},
+		{SQLite, `UPDATE users SET doc = ? WHERE id = ? AND COALESCE(json_extract(doc, '$._rev'), 0) = ?`},
+	}
+	for _, tt := range tests {
+		if got := UpdateRevisioned(tt.dialect, "users", "doc", "id"); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.dialect, tt.expected, got)
+		}
+	}
+}
+
+func TestCheckRevision(t *testing.T) {
+	if err := CheckRevision(testResult(1), nil); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	if err := CheckRevision(testResult(0), nil); !errors.Is(err, ErrRevisionConflict) || !IsPermanent(err) {
+		t.Errorf("expected permanent ErrRevisionConflict, got %v", err)
+	}
+	execErr := errors.New("connection reset")
+	if err := CheckRevision(nil, execErr); err != execErr {
+		t.Errorf("expected Exec error, got %v", err)
+	}
+}
+
+func TestRevisioned_Map(t *testing.T) {
+	var r Revisioned[map[string]any]
+	if err := r.Scan("\xef\xbb\xbf{\"_rev\":4,\"name\":\"Alice\"}"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := r.V[RevisionKey]; ok || r.Rev != 4 {
+		t.Errorf("expected the revision outside V, got %v at revision %d", r.V, r.Rev)
+	}
+
+	data, err := r.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(data.([]byte)) != `{"_rev":5,"name":"Alice"}` {
+		t.Errorf("unexpected document %s", data)
+	}
+}
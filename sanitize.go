@@ -0,0 +1,176 @@
+package jsonsql
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+)
+
+// NULMode selects how Value handles NUL characters (\u0000) inside JSON strings.
+// Postgres jsonb rejects them, so documents containing user-supplied text may fail to insert.
+type NULMode int
+
+const (
+	// NULKeep writes NUL characters unchanged (default).
+	NULKeep NULMode = iota
+	// NULStrip removes NUL characters.
+	NULStrip
+	// NULReplace replaces NUL characters with U+FFFD.
+	NULReplace
+)
+
+// UTF8Policy selects how Scan handles invalid UTF-8 in the scanned data.
+type UTF8Policy int
+
+const (
+	// UTF8Allow passes the data to encoding/json unchanged (default).
+	UTF8Allow UTF8Policy = iota
+	// UTF8Error makes Scan fail with ErrInvalidUTF8.
+	UTF8Error
+	// UTF8Replace replaces invalid bytes with U+FFFD before decoding.
+	UTF8Replace
+	// UTF8Strip removes invalid bytes before decoding.
+	UTF8Strip
+)
+
+// ErrInvalidUTF8 is returned by Scan when the data contains invalid UTF-8 and the UTF8Error policy is set.
+var ErrInvalidUTF8 = permanent("jsonsql: invalid UTF-8 in JSON data")
+
+// InvalidUTF8 sets the policy Scan applies to invalid UTF-8 in the scanned data,
+// typically found in legacy columns with broken encodings.
+func InvalidUTF8(policy UTF8Policy) Option {
+	return func(c *config) {
+		c.utf8Policy = policy
+	}
+}
+
+// SanitizeReport describes the changes made by the sanitize step of Value.
+type SanitizeReport struct {
+	// Type is the Go type of the value that was marshaled.
+	Type string
+	// NUL is the number of NUL characters stripped or replaced.
+	NUL int
+	// InvalidUTF8 is the number of invalid UTF-8 bytes replaced with U+FFFD.
+	InvalidUTF8 int
+}
+
+// SanitizeNUL makes Value strip or replace NUL characters in the marshaled document.
+func SanitizeNUL(mode NULMode) Option {
+	return func(c *config) {
+		c.nulMode = mode
+	}
+}
+
+// SanitizeUTF8 makes Value replace invalid UTF-8 in the marshaled document with U+FFFD.
+// encoding/json already does this for Go strings, but pre-encoded values such as
+// json.RawMessage are written as-is.
+func SanitizeUTF8(enabled bool) Option {
+	return func(c *config) {
+		c.sanitizeUTF8 = enabled
+	}
+}
+
+// OnSanitize sets the function called whenever the sanitize step modifies a document.
+// By default a warning is logged with the default slog logger.
+func OnSanitize(fn func(SanitizeReport)) Option {
+	return func(c *config) {
+		c.onSanitize = fn
+	}
+}
+
+// sanitize applies the NUL and UTF-8 sanitize steps configured in cfg to the encoded document data.
+func sanitize(data []byte, v any, cfg *config) []byte {
+	if cfg.nulMode == NULKeep && !cfg.sanitizeUTF8 {
+		return data
+	}
+
+	var report SanitizeReport
+	if cfg.nulMode != NULKeep && bytes.Contains(data, []byte(`\u0000`)) {
+		data, report.NUL = sanitizeNUL(data, cfg.nulMode)
+	}
+	if cfg.sanitizeUTF8 && !utf8.Valid(data) {
+		data, report.InvalidUTF8 = sanitizeUTF8(data, []byte("\ufffd"))
+	}
+	if report.NUL == 0 && report.InvalidUTF8 == 0 {
+		return data
+	}
+
+	report.Type = typeName(v)
+	if cfg.onSanitize != nil {
+		cfg.onSanitize(report)
+	} else {
+		slog.Warn("jsonsql: sanitized document before writing",
+			"type", report.Type, "nul", report.NUL, "invalid_utf8", report.InvalidUTF8)
+	}
+	return data
+}
+
+// sanitizeNUL strips or replaces \u0000 escapes in data, returning the number of escapes handled.
+// Escapes always come in pairs in valid JSON, so an escaped backslash followed by "u0000" is left alone.
+func sanitizeNUL(data []byte, mode NULMode) ([]byte, int) {
+	out := make([]byte, 0, len(data))
+	count := 0
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' || i+1 >= len(data) {
+			out = append(out, data[i])
+			continue
+		}
+		if bytes.HasPrefix(data[i+1:], []byte("u0000")) {
+			count++
+			if mode == NULReplace {
+				out = append(out, "\ufffd"...)
+			}
+			i += len(`\u0000`) - 1
+			continue
+		}
+		out = append(out, data[i], data[i+1])
+		i++
+	}
+	return out, count
+}
+
+// checkUTF8 applies policy to invalid UTF-8 in data.
+func checkUTF8(data []byte, policy UTF8Policy) ([]byte, error) {
+	if policy == UTF8Allow || utf8.Valid(data) {
+		return data, nil
+	}
+	switch policy {
+	case UTF8Replace:
+		data, _ = sanitizeUTF8(data, []byte("\ufffd"))
+	case UTF8Strip:
+		data, _ = sanitizeUTF8(data, nil)
+	default:
+		return nil, fmt.Errorf("%w at offset %d", ErrInvalidUTF8, invalidUTF8Offset(data))
+	}
+	return data, nil
+}
+
+// invalidUTF8Offset returns the offset of the first invalid UTF-8 byte in data.
+func invalidUTF8Offset(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// sanitizeUTF8 replaces invalid UTF-8 bytes in data with replacement, returning the number of bytes replaced.
+func sanitizeUTF8(data, replacement []byte) ([]byte, int) {
+	out := make([]byte, 0, len(data))
+	count := 0
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			count++
+			out = append(out, replacement...)
+		} else {
+			out = append(out, data[:size]...)
+		}
+		data = data[size:]
+	}
+	return out, count
+}
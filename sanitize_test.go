@@ -0,0 +1,129 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeNUL(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     NULMode
+		expected string
+		count    int
+	}{
+		{"keep", NULKeep, `{"a":"x\u0000y","b":"\\u0000"}`, 0},
+		{"strip", NULStrip, `{"a":"xy","b":"\\u0000"}`, 1},
+		{"replace", NULReplace, "{\"a\":\"x\ufffdy\",\"b\":\"\\\\u0000\"}", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetOptions[map[string]string](t)
+			var reports []SanitizeReport
+			Configure[map[string]string](SanitizeNUL(tt.mode), OnSanitize(func(r SanitizeReport) {
+				reports = append(reports, r)
+			}))
+
+			result, err := NewValue(map[string]string{"a": "x\x00y", "b": `\u0000`}).Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+
+			if string(result.([]byte)) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+			if tt.count == 0 {
+				if len(reports) != 0 {
+					t.Errorf("expected no report, got %+v", reports)
+				}
+				return
+			}
+			if len(reports) != 1 || reports[0].NUL != tt.count || reports[0].Type != "map[string]string" {
+				t.Errorf("unexpected reports: %+v", reports)
+			}
+		})
+	}
+}
+
+func TestSanitizeUTF8(t *testing.T) {
+	resetOptions[json.RawMessage](t)
+	var report SanitizeReport
+	Configure[json.RawMessage](SanitizeUTF8(true), OnSanitize(func(r SanitizeReport) {
+		report = r
+	}))
+
+	result, err := NewValue(json.RawMessage("\"a\xffb\"")).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	if string(result.([]byte)) != "\"a\ufffdb\"" {
+		t.Errorf("unexpected result: %q", result)
+	}
+	if report.InvalidUTF8 != 1 {
+		t.Errorf("expected InvalidUTF8=1, got %+v", report)
+	}
+}
+
+func TestSanitize_Disabled(t *testing.T) {
+	result, err := NewValue(map[string]string{"a": "x\x00y"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	if string(result.([]byte)) != `{"a":"x\u0000y"}` {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestInvalidUTF8_Policies(t *testing.T) {
+	input := []byte("{\"name\":\"a\xffb\",\"email\":\"\"}")
+
+	tests := []struct {
+		name     string
+		policy   UTF8Policy
+		expected string
+		wantErr  bool
+	}{
+		{"allow", UTF8Allow, "a\ufffdb", false},
+		{"error", UTF8Error, "", true},
+		{"replace", UTF8Replace, "a\ufffdb", false},
+		{"strip", UTF8Strip, "ab", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetOptions[testProfile](t)
+			Configure[testProfile](InvalidUTF8(tt.policy))
+
+			var n Nullable[testProfile]
+			err := n.Scan(input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidUTF8) {
+					t.Fatalf("expected ErrInvalidUTF8, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if n.V.Name != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, n.V.Name)
+			}
+		})
+	}
+}
+
+func TestInvalidUTF8_Error_ReportsOffset(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](InvalidUTF8(UTF8Error))
+
+	var v Value[testProfile]
+	err := v.Scan([]byte("{\"name\":\"\xc3\"}"))
+	if err == nil || !strings.Contains(err.Error(), "offset 9") {
+		t.Errorf("expected offset 9 in error, got %v", err)
+	}
+}
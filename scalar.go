@@ -0,0 +1,182 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// CoerceScalars makes Scan accept native driver scalars (int64, float64, bool and time.Time)
+// in addition to JSON text. Some drivers return the result of json_extract(...) and similar
+// functions as such values instead of JSON. The scalar is converted to its JSON representation;
+// when the type parameter is a string type it is converted to a JSON string instead,
+// so Nullable[string] can scan a numeric result.
+func CoerceScalars(enabled bool) Option {
+	return func(c *config) {
+		c.coerceScalars = enabled
+	}
+}
+
+// coerceScalar converts a native driver scalar to JSON suitable for decoding into target.
+func coerceScalar(src any, target reflect.Type) ([]byte, bool) {
+	var text []byte
+	switch s := src.(type) {
+	case int64:
+		text = strconv.AppendInt(nil, s, 10)
+	case float64:
+		data, err := json.Marshal(s)
+		if err != nil {
+			return nil, false
+		}
+		text = data
+	case bool:
+		text = strconv.AppendBool(nil, s)
+	case time.Time:
+		data, err := json.Marshal(s)
+		if err != nil {
+			return nil, false
+		}
+		return data, true
+	default:
+		return nil, false
+	}
+
+	if target.Kind() == reflect.String {
+		data, _ := json.Marshal(string(text))
+		return data, true
+	}
+	return text, true
+}
+
+// ExtractMode describes the text drivers return for values extracted from JSON documents by
+// SQL functions, for ScanExtracted.
+type ExtractMode int
+
+const (
+	// ExtractJSON expects JSON text, as returned by JSON_QUERY, JSON_EXTRACT and -> (default).
+	ExtractJSON ExtractMode = iota
+	// ExtractUnquoted expects scalars as plain text, as returned by MySQL's JSON_VALUE and
+	// ->> and by JSON_UNQUOTE: strings without quotes or escapes, numbers and booleans as is.
+	ExtractUnquoted
+	// ExtractAuto accepts both. Text scanned into a string type is taken as JSON only if it
+	// is a JSON string or null, so it cannot tell a JSON_VALUE result that itself looks like
+	// a quoted JSON string from JSON text; use ExtractUnquoted when the query is known.
+	ExtractAuto
+)
+
+// ScanExtracted makes Scan accept the results of JSON path extraction functions in mode,
+// so scalar wrappers such as Nullable[string] or Value[time.Time] can scan them directly:
+//
+//	jsonsql.Configure[string](jsonsql.ScanExtracted(jsonsql.ExtractAuto))
+//	err := db.QueryRowContext(ctx,
+//		`SELECT JSON_VALUE(doc, '$.email') FROM users WHERE id = ?`, id).Scan(&email) // Nullable[string]
+//
+// Unquoted text is quoted for types decoded from JSON strings: string types and types
+// implementing encoding.TextUnmarshaler, such as time.Time. Empty text then scans as the
+// empty string. Other types decode the text as JSON, which covers unquoted numbers and booleans.
+func ScanExtracted(mode ExtractMode) Option {
+	return func(c *config) {
+		c.extractMode = mode
+	}
+}
+
+// extracted converts data, a value extracted in mode, to JSON text for decoding into target.
+func extracted(data []byte, target reflect.Type, mode ExtractMode) []byte {
+	if mode == ExtractJSON || !decodesFromString(target) {
+		return data
+	}
+	if mode == ExtractAuto {
+		trimmed := bytes.TrimSpace(data)
+		if isJSONNull(trimmed) || len(trimmed) > 0 && trimmed[0] == '"' && json.Valid(trimmed) {
+			return data
+		}
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
+
+// decodesFromString reports whether values of type t are decoded from JSON strings.
+func decodesFromString(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// decodeScalar decodes the JSON document data into v without reflection when v points to
+// one of the common scalar types and data is a plain literal of that type. It reports false,
+// leaving v untouched, for everything else, which is left to encoding/json along with the
+// errors.
+func decodeScalar(data []byte, v any) bool {
+	data = bytes.TrimSpace(data)
+	switch v := v.(type) {
+	case *string:
+		s, ok := plainString(data)
+		if ok {
+			*v = s
+		}
+		return ok
+	case *bool:
+		switch string(data) {
+		case "true":
+			*v = true
+		case "false":
+			*v = false
+		default:
+			return false
+		}
+		return true
+	case *int64:
+		if !isJSONNumber(string(data)) {
+			return false
+		}
+		n, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return false
+		}
+		*v = n
+		return true
+	case *int:
+		if !isJSONNumber(string(data)) {
+			return false
+		}
+		n, err := strconv.ParseInt(string(data), 10, strconv.IntSize)
+		if err != nil {
+			return false
+		}
+		*v = int(n)
+		return true
+	case *float64:
+		if !isJSONNumber(string(data)) {
+			return false
+		}
+		f, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return false
+		}
+		*v = f
+		return true
+	}
+	return false
+}
+
+// plainString returns the content of the JSON string literal data if it has no escapes,
+// control characters or invalid UTF-8, which encoding/json would have to process.
+func plainString(data []byte) (string, bool) {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return "", false
+	}
+	content := data[1 : len(data)-1]
+	for _, c := range content {
+		if c < 0x20 || c == '"' || c == '\\' {
+			return "", false
+		}
+	}
+	if !utf8.Valid(content) {
+		return "", false
+	}
+	return string(content), true
+}
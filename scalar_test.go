@@ -0,0 +1,231 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCoerceScalars_Disabled(t *testing.T) {
+	var v Value[int]
+
+	if err := v.Scan(int64(1)); err == nil {
+		t.Fatal("expected error for int64 source without coercion")
+	}
+}
+
+func TestCoerceScalars_Value(t *testing.T) {
+	resetOptions[int](t)
+	Configure[int](CoerceScalars(true))
+
+	var v Value[int]
+	if err := v.Scan(int64(42)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if v.V != 42 {
+		t.Errorf("expected 42, got %d", v.V)
+	}
+}
+
+func TestCoerceScalars_Float(t *testing.T) {
+	resetOptions[float64](t)
+	Configure[float64](CoerceScalars(true))
+
+	var n Nullable[float64]
+	if err := n.Scan(1.5); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !n.Valid || n.V != 1.5 {
+		t.Errorf("expected 1.5, got %+v", n)
+	}
+}
+
+func TestCoerceScalars_Bool(t *testing.T) {
+	resetOptions[bool](t)
+	Configure[bool](CoerceScalars(true))
+
+	var v Value[bool]
+	if err := v.Scan(true); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !v.V {
+		t.Error("expected true")
+	}
+}
+
+func TestCoerceScalars_String(t *testing.T) {
+	resetOptions[string](t)
+	Configure[string](CoerceScalars(true))
+
+	tests := []struct {
+		input    any
+		expected string
+	}{
+		{int64(7), "7"},
+		{1.25, "1.25"},
+		{false, "false"},
+	}
+
+	for _, tt := range tests {
+		var n Nullable[string]
+		if err := n.Scan(tt.input); err != nil {
+			t.Fatalf("Scan(%v) failed: %v", tt.input, err)
+		}
+		if n.V != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, n.V)
+		}
+	}
+}
+
+func TestCoerceScalars_Time(t *testing.T) {
+	resetOptions[time.Time](t)
+	Configure[time.Time](CoerceScalars(true))
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var v Value[time.Time]
+	if err := v.Scan(at); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !v.V.Equal(at) {
+		t.Errorf("expected %v, got %v", at, v.V)
+	}
+}
+
+func TestCoerceScalars_TypeMismatch(t *testing.T) {
+	resetOptions[int](t)
+	Configure[int](CoerceScalars(true))
+
+	var v Value[int]
+	if err := v.Scan(true); err == nil {
+		t.Fatal("expected error for bool into int")
+	}
+}
+
+func TestScanExtracted_Unquoted(t *testing.T) {
+	resetOptions[string](t)
+	resetOptions[time.Time](t)
+	resetOptions[int](t)
+	SetDefaults(ScanExtracted(ExtractUnquoted))
+
+	var s Nullable[string]
+	for src, expected := range map[any]string{
+		"alice@example.com": "alice@example.com",
+		`say "hi"`:          `say "hi"`,
+		"123":               "123",
+		"":                  "",
+		`"quoted"`:          `"quoted"`,
+	} {
+		if err := s.Scan(src); err != nil {
+			t.Fatalf("Scan(%q) failed: %v", src, err)
+		}
+		if !s.Valid || s.V != expected {
+			t.Errorf("Scan(%q): expected %q, got %+v", src, expected, s)
+		}
+	}
+	if err := s.Scan([]byte("bytes")); err != nil || s.V != "bytes" {
+		t.Errorf("unexpected result %+v, %v", s, err)
+	}
+	if err := s.Scan(nil); err != nil || s.Valid {
+		t.Errorf("expected NULL, got %+v, %v", s, err)
+	}
+
+	var ts Value[time.Time]
+	if err := ts.Scan("2024-05-01T12:00:00Z"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !ts.V.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time %v", ts.V)
+	}
+
+	var n Value[int]
+	if err := n.Scan("42"); err != nil || n.V != 42 {
+		t.Errorf("unexpected result %d, %v", n.V, err)
+	}
+}
+
+func TestScanExtracted_Auto(t *testing.T) {
+	resetOptions[string](t)
+	Configure[string](ScanExtracted(ExtractAuto), CoerceScalars(true))
+
+	var s Nullable[string]
+	for src, expected := range map[any]string{
+		`"Alice"`:     "Alice",
+		`"caf\u00e9"`: "café",
+		"Alice":       "Alice",
+		"123":         "123",
+		`{"a":1}`:     `{"a":1}`,
+		int64(7):      "7",
+		`"unclosed`:   `"unclosed`,
+		"true":        "true",
+		" \"pad\" ":   "pad",
+		"two words":   "two words",
+		`["a", "b"]`:  `["a", "b"]`,
+	} {
+		if err := s.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) failed: %v", src, err)
+		}
+		if !s.Valid || s.V != expected {
+			t.Errorf("Scan(%v): expected %q, got %+v", src, expected, s)
+		}
+	}
+	if err := s.Scan("null"); err != nil || s.Valid {
+		t.Errorf("expected JSON null to scan as NULL, got %+v, %v", s, err)
+	}
+}
+
+func TestScanExtracted_Default(t *testing.T) {
+	var s Value[string]
+	if err := s.Scan("Alice"); err == nil {
+		t.Error("expected unquoted text to be rejected by default")
+	}
+}
+
+func TestDecodeScalar(t *testing.T) {
+	var b Value[bool]
+	if err := b.Scan([]byte(" true ")); err != nil || !b.V {
+		t.Errorf("unexpected bool %v, err: %v", b.V, err)
+	}
+	var i Value[int64]
+	if err := i.Scan("-9007199254740993"); err != nil || i.V != -9007199254740993 {
+		t.Errorf("unexpected int64 %d, err: %v", i.V, err)
+	}
+	var f Value[float64]
+	if err := f.Scan([]byte("1.5e3")); err != nil || f.V != 1500 {
+		t.Errorf("unexpected float64 %v, err: %v", f.V, err)
+	}
+	var s Value[string]
+	if err := s.Scan([]byte(`"héllo"`)); err != nil || s.V != "héllo" {
+		t.Errorf("unexpected string %q, err: %v", s.V, err)
+	}
+	if err := s.Scan([]byte(`"a\"bé"`)); err != nil || s.V != `a"bé` {
+		t.Errorf("unexpected escaped string %q, err: %v", s.V, err)
+	}
+}
+
+func TestDecodeScalar_Invalid(t *testing.T) {
+	var i Value[int64]
+	for _, src := range []string{"1.5", "01", "+1", "9223372036854775808", `"1"`, "1 2"} {
+		if err := i.Scan(src); err == nil {
+			t.Errorf("expected error for %s, got %d", src, i.V)
+		}
+	}
+	var b Value[bool]
+	if err := b.Scan("TRUE"); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+	var s Value[string]
+	if err := s.Scan("\"a\tb\""); err == nil {
+		t.Errorf("expected error for control character, got %q", s.V)
+	}
+}
+
+func BenchmarkDecodeScalar(b *testing.B) {
+	src := []byte("1234567890")
+	var v Value[int64]
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := v.Scan(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package jsonsql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Scanner decodes database source values into T with options bound once, for row loops
+// processing millions of rows:
+//
+//	s := jsonsql.NewScanner[Event](jsonsql.UseNumber(true))
+//	for rows.Next() {
+//		var raw []byte
+//		if err := rows.Scan(&raw); err != nil {
+//			return err
+//		}
+//		event, err := s.ScanRow(raw)
+//		...
+//	}
+//
+// A Scanner is safe for concurrent use.
+type Scanner[T any] struct {
+	cfg *config
+	// bufs pools the buffers string sources are copied into.
+	bufs sync.Pool
+}
+
+// NewScanner creates a new Scanner[T] decoding with the global defaults, the options
+// registered for T and opts, applied in that order. The options are resolved once:
+// later calls to SetDefaults and Configure do not affect the Scanner.
+func NewScanner[T any](opts ...Option) *Scanner[T] {
+	typ := reflect.TypeFor[T]()
+	registry.mu.RLock()
+	cfg := newConfig(typ, registry.global, typeDefaults[typ], registry.types[typ], opts)
+	registry.mu.RUnlock()
+	return &Scanner[T]{
+		cfg:  cfg,
+		bufs: sync.Pool{New: func() any { return new([]byte) }},
+	}
+}
+
+// ScanRow decodes src into a T using the same rules as Value[T].Scan.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null".
+func (s *Scanner[T]) ScanRow(src any) (T, error) {
+	return s.ScanRowContext(context.Background(), src)
+}
+
+// ScanRowContext is like ScanRow, failing with the context error if ctx is already done
+// and passing ctx to the observability hooks.
+func (s *Scanner[T]) ScanRowContext(ctx context.Context, src any) (T, error) {
+	var v T
+	if str, ok := src.(string); ok {
+		// Decoding never retains the source, so the copy can be reused for the next row.
+		buf := s.bufs.Get().(*[]byte)
+		*buf = append((*buf)[:0], str...)
+		defer s.bufs.Put(buf)
+		src = *buf
+	}
+	null, err := decodeSourceConfig(ctx, src, &v, s.cfg, ErrorOnEmpty)
+	if err != nil {
+		return v, fmt.Errorf("jsonsql.Scanner.ScanRow: %w", err)
+	}
+	if null {
+		return v, ErrNullNotAllowed
+	}
+	return v, nil
+}
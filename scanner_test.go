@@ -0,0 +1,89 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	s := NewScanner[testProfile]()
+	p, err := s.ScanRow([]byte(`{"name":"Alice","email":"alice@example.com"}`))
+	if err != nil {
+		t.Fatalf("ScanRow failed: %v", err)
+	}
+	if p.Name != "Alice" || p.Email != "alice@example.com" {
+		t.Errorf("unexpected value %+v", p)
+	}
+	if _, err := s.ScanRow(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if _, err := s.ScanRow("null"); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if _, err := s.ScanRow(`{"name":`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+}
+
+func TestScanner_Options(t *testing.T) {
+	resetOptions[map[string]any](t)
+	Configure[map[string]any](MaxDocumentSize(100))
+
+	s := NewScanner[map[string]any](UseNumber(true))
+	// Later registrations do not affect the Scanner.
+	Configure[map[string]any](MaxDocumentSize(1))
+
+	m, err := s.ScanRow(`{"id":9007199254740993}`)
+	if err != nil {
+		t.Fatalf("ScanRow failed: %v", err)
+	}
+	if m["id"] != json.Number("9007199254740993") {
+		t.Errorf("expected json.Number, got %#v", m["id"])
+	}
+	if _, err := s.ScanRow(`{"id":"` + string(make([]byte, 100)) + `"}`); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge from the bound options, got %v", err)
+	}
+}
+
+func TestScanner_Concurrent(t *testing.T) {
+	s := NewScanner[testProfile]()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := string(rune('a' + i))
+			for range 100 {
+				p, err := s.ScanRow(`{"name":"` + name + `"}`)
+				if err != nil || p.Name != name {
+					t.Errorf("unexpected result %+v, %v", p, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkScanner(b *testing.B) {
+	src := `{"name":"Alice","email":"alice@example.com"}`
+	b.Run("ScanJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := ScanJSON[testProfile](src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Scanner", func(b *testing.B) {
+		s := NewScanner[testProfile]()
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := s.ScanRow(src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// SchemaProvider is implemented by types that describe their own JSON Schema.
+// Schema uses it instead of reflecting on the type, which is how the wrappers
+// present the schema of their contents rather than their Go fields.
+type SchemaProvider interface {
+	JSONSchema() map[string]any
+}
+
+// Compile-time interface satisfaction checks
+var (
+	_ SchemaProvider = Value[struct{}]{}
+	_ SchemaProvider = Nullable[struct{}]{}
+)
+
+// Schema returns a JSON Schema (OpenAPI 3.0 dialect) describing T, for API documentation generators.
+// Struct fields are resolved like encoding/json. Value[T] is described by the schema of T and
+// Nullable[T] by the schema of T with "nullable": true, so documents show the column contents
+// instead of the V and Valid fields.
+//
+// Types implementing SchemaProvider describe themselves. Other types implementing json.Marshaler
+// are described by an empty schema, and encoding.TextMarshaler types as strings.
+// Options registered with Configure are not reflected in the schema.
+func Schema[T any]() map[string]any {
+	return schemaOf(reflect.TypeFor[T](), map[reflect.Type]bool{})
+}
+
+// JSONSchema implements SchemaProvider. It returns the schema of T.
+func (v Value[T]) JSONSchema() map[string]any {
+	return Schema[T]()
+}
+
+// JSONSchema implements SchemaProvider. It returns the schema of T marked as nullable.
+func (n Nullable[T]) JSONSchema() map[string]any {
+	s := Schema[T]()
+	s["nullable"] = true
+	return s
+}
+
+var schemaProviderType = reflect.TypeFor[SchemaProvider]()
+
+// schemaOf returns the schema of t. seen holds the struct types being described,
+// so recursive types end in a plain object schema instead of looping.
+func schemaOf(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		if t.Implements(schemaProviderType) {
+			break
+		}
+		t = t.Elem()
+		nullable = true
+	}
+	s := schemaOfElem(t, seen)
+	if nullable {
+		s["nullable"] = true
+	}
+	return s
+}
+
+func schemaOfElem(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	switch {
+	case t.Implements(schemaProviderType):
+		return reflect.Zero(t).Interface().(SchemaProvider).JSONSchema()
+	case reflect.PointerTo(t).Implements(schemaProviderType):
+		return reflect.New(t).Interface().(SchemaProvider).JSONSchema()
+	case t == reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == reflect.TypeFor[json.Number]():
+		return map[string]any{"type": "number"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]any{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s := map[string]any{"type": "integer"}
+		switch t.Kind() {
+		case reflect.Int32:
+			s["format"] = "int32"
+		case reflect.Int64:
+			s["format"] = "int64"
+		}
+		return s
+	case reflect.Float32:
+		return map[string]any{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !reflect.PointerTo(t.Elem()).Implements(textMarshalerType) {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), seen), "nullable": true}
+	case reflect.Array:
+		return map[string]any{
+			"type":     "array",
+			"items":    schemaOf(t.Elem(), seen),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaOf(t.Elem(), seen),
+			"nullable":             true,
+		}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		props := map[string]any{}
+		for _, f := range typeFields(t) {
+			fs := schemaOf(f.typ, seen)
+			if f.quoted {
+				fs = map[string]any{"type": "string"}
+			}
+			props[f.name] = fs
+		}
+		return map[string]any{"type": "object", "properties": props}
+	}
+	// Interfaces accept any value; channels and functions cannot be encoded.
+	return map[string]any{}
+}
@@ -0,0 +1,73 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testSchemaNode struct {
+	Name     string                 `json:"name"`
+	Count    int64                  `json:"count,string"`
+	Created  time.Time              `json:"created"`
+	Parent   *testSchemaNode        `json:"parent,omitempty"`
+	Profile  Value[testProfile]     `json:"profile"`
+	Settings Nullable[testSettings] `json:"settings"`
+	Secret   Sensitive[string]      `json:"secret"`
+	Data     []byte                 `json:"data"`
+	Skipped  string                 `json:"-"`
+}
+
+func schemaJSON(t *testing.T, s map[string]any) string {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	return string(data)
+}
+
+func TestSchema_Wrappers(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]any
+		expected string
+	}{
+		{"value", Schema[Value[testProfile]](), `{"properties":{"email":{"type":"string"},"name":{"type":"string"}},"type":"object"}`},
+		{"nullable", Schema[Nullable[[]string]](), `{"items":{"type":"string"},"nullable":true,"type":"array"}`},
+		{"nullable scalar", Schema[Nullable[int32]](), `{"format":"int32","nullable":true,"type":"integer"}`},
+		{"map", Schema[Value[map[string]float64]](), `{"additionalProperties":{"format":"double","type":"number"},"nullable":true,"type":"object"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaJSON(t, tt.schema); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSchema_Struct(t *testing.T) {
+	s := Schema[testSchemaNode]()
+	props := s["properties"].(map[string]any)
+
+	expected := map[string]string{
+		"name":     `{"type":"string"}`,
+		"count":    `{"type":"string"}`,
+		"created":  `{"format":"date-time","type":"string"}`,
+		"parent":   `{"nullable":true,"type":"object"}`,
+		"profile":  `{"properties":{"email":{"type":"string"},"name":{"type":"string"}},"type":"object"}`,
+		"settings": `{"nullable":true,"properties":{"page_size":{"type":"integer"},"theme":{"type":"string"}},"type":"object"}`,
+		"secret":   `{"type":"string"}`,
+		"data":     `{"format":"byte","type":"string"}`,
+	}
+	if len(props) != len(expected) {
+		t.Errorf("expected %d properties, got %d: %v", len(expected), len(props), props)
+	}
+	for name, want := range expected {
+		if got := schemaJSON(t, props[name].(map[string]any)); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
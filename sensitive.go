@@ -0,0 +1,121 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"log/slog"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner            = (*Sensitive[struct{}])(nil)
+	_ ContextScanner         = (*Sensitive[struct{}])(nil)
+	_ driver.Valuer          = Sensitive[struct{}]{}
+	_ fmt.Stringer           = Sensitive[struct{}]{}
+	_ fmt.GoStringer         = Sensitive[struct{}]{}
+	_ fmt.Formatter          = Sensitive[struct{}]{}
+	_ slog.LogValuer         = Sensitive[struct{}]{}
+	_ encoding.TextMarshaler = Sensitive[struct{}]{}
+)
+
+// sensitivePlaceholder is printed in place of the contents of a Sensitive[T].
+const sensitivePlaceholder = "[REDACTED]"
+
+// Sensitive[T] is a generic type for NOT NULL JSON columns holding secrets such as tokens or credentials.
+// It scans and stores V exactly like Value[T], but String, GoString, Format, LogValue and
+// MarshalText always return a placeholder so the contents never end up in logs or API responses.
+//
+// Because MarshalText is redacted, Sensitive[T] must be used as the column type itself
+// and not nested inside another wrapper such as Nullable[Sensitive[T]].
+type Sensitive[T any] struct {
+	V T
+}
+
+// NewSensitive creates a new Sensitive[T] with the given value.
+func NewSensitive[T any](v T) Sensitive[T] {
+	return Sensitive[T]{V: v}
+}
+
+// Get returns the value.
+func (s Sensitive[T]) Get() T {
+	return s.V
+}
+
+// Set replaces the value with x.
+func (s *Sensitive[T]) Set(x T) {
+	s.V = x
+}
+
+// Replace replaces the value with the result of calling f with the current value.
+func (s *Sensitive[T]) Replace(f func(T) T) {
+	s.V = f(s.V)
+}
+
+// String implements fmt.Stringer interface.
+// It always returns a placeholder.
+func (s Sensitive[T]) String() string {
+	return sensitivePlaceholder
+}
+
+// GoString implements fmt.GoStringer interface.
+// It always returns a placeholder, so %#v does not reveal V either.
+func (s Sensitive[T]) GoString() string {
+	return "jsonsql.Sensitive" + sensitivePlaceholder
+}
+
+// Format implements fmt.Formatter interface.
+// It prints the placeholder for every verb, so verbs that bypass String, such as %d or %x,
+// do not reveal V either. %#v prints the GoString placeholder.
+func (s Sensitive[T]) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+	fmt.Fprint(f, sensitivePlaceholder)
+}
+
+// LogValue implements slog.LogValuer interface.
+// It always returns the placeholder, so handlers do not log V through reflection.
+func (s Sensitive[T]) LogValue() slog.Value {
+	return slog.StringValue(sensitivePlaceholder)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+// It always returns a placeholder, which also makes encoding/json emit the placeholder string.
+func (s Sensitive[T]) MarshalText() ([]byte, error) {
+	return []byte(sensitivePlaceholder), nil
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals JSON data from the database into V.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (s *Sensitive[T]) Scan(src any) error {
+	return s.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (s *Sensitive[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeSourceLogged(ctx, src, &s.V, configFor[T](), ErrorOnEmpty, decodePayload[T], false)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Sensitive.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V to JSON bytes for database storage; the placeholder is never written.
+func (s Sensitive[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := encodeValue(s.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Sensitive.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
@@ -0,0 +1,164 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type testCredentials struct {
+	Token string `json:"token"`
+}
+
+func TestSensitive_Scan(t *testing.T) {
+	var s Sensitive[testCredentials]
+
+	if err := s.Scan([]byte(`{"token":"secret-token"}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if s.Get().Token != "secret-token" {
+		t.Errorf("expected Token=secret-token, got %s", s.Get().Token)
+	}
+}
+
+func TestSensitive_Scan_Null_ReturnsError(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{"nil", nil},
+		{"null bytes", []byte("null")},
+		{"null string", " null "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Sensitive[testCredentials]
+
+			if err := s.Scan(tt.input); !errors.Is(err, ErrNullNotAllowed) {
+				t.Errorf("expected ErrNullNotAllowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSensitive_Scan_UnsupportedType(t *testing.T) {
+	var s Sensitive[testCredentials]
+
+	if err := s.Scan(123); err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
+
+func TestSensitive_Value(t *testing.T) {
+	s := NewSensitive(testCredentials{Token: "secret-token"})
+
+	result, err := s.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	data, ok := result.([]byte)
+	if !ok {
+		t.Fatalf("expected []byte, got %T", result)
+	}
+	if string(data) != `{"token":"secret-token"}` {
+		t.Errorf("unexpected result: %s", data)
+	}
+}
+
+func TestSensitive_NeverPrintsContents(t *testing.T) {
+	s := NewSensitive(testCredentials{Token: "secret-token"})
+	wrapper := struct {
+		ID     int
+		Secret Sensitive[testCredentials]
+	}{ID: 1, Secret: s}
+
+	formats := []string{"%v", "%+v", "%#v", "%s"}
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			out := fmt.Sprintf(format, s)
+			if strings.Contains(out, "secret-token") {
+				t.Errorf("%s leaked contents: %s", format, out)
+			}
+			out = fmt.Sprintf(format, wrapper)
+			if strings.Contains(out, "secret-token") {
+				t.Errorf("%s leaked contents of nested field: %s", format, out)
+			}
+		})
+	}
+}
+
+func TestSensitive_MarshalJSON_Redacted(t *testing.T) {
+	data, err := json.Marshal(map[string]any{"secret": NewSensitive("secret-token")})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	if string(data) != `{"secret":"[REDACTED]"}` {
+		t.Errorf("unexpected result: %s", data)
+	}
+}
+
+func TestSensitive_SetReplace(t *testing.T) {
+	var s Sensitive[string]
+
+	s.Set("token")
+	s.Replace(func(v string) string { return v + "-rotated" })
+	if s.V != "token-rotated" {
+		t.Errorf("expected token-rotated, got %s", s.V)
+	}
+}
+
+func TestSensitive_FormatVerbs(t *testing.T) {
+	pin := NewSensitive(987654)
+	token := NewSensitive("secret-token")
+	wrapper := struct {
+		Pin *Sensitive[int]
+	}{Pin: &pin}
+
+	for _, format := range []string{"%d", "%x", "%X", "%q", "%08d", "%v", "%+v"} {
+		if out := fmt.Sprintf(format, pin); strings.Contains(out, "987654") || strings.Contains(out, "f1206") || strings.Contains(out, "F1206") {
+			t.Errorf("%s leaked contents: %s", format, out)
+		}
+		if out := fmt.Sprintf(format, token); strings.Contains(out, "secret") || strings.Contains(out, "736563726574") {
+			t.Errorf("%s leaked contents: %s", format, out)
+		}
+	}
+	if out := fmt.Sprintf("%+v", *wrapper.Pin); out != "[REDACTED]" {
+		t.Errorf("unexpected output %s", out)
+	}
+	if out := fmt.Sprintf("%#v", token); out != "jsonsql.Sensitive[REDACTED]" {
+		t.Errorf("unexpected %%#v output %s", out)
+	}
+}
+
+func TestSensitive_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("login", "credentials", NewSensitive(testCredentials{Token: "secret-token"}), "pin", NewSensitive(987654))
+
+	if out := buf.String(); strings.Contains(out, "secret-token") || strings.Contains(out, "987654") ||
+		!strings.Contains(out, `"credentials":"[REDACTED]"`) {
+		t.Errorf("unexpected log output: %s", out)
+	}
+}
+
+func TestSensitive_DebugLog_OmitsPayload(t *testing.T) {
+	resetOptions[testCredentials](t)
+	var buf bytes.Buffer
+	SetDefaults(DebugLog(slog.New(slog.NewJSONHandler(&buf, nil)), DebugOptions{}))
+
+	var s Sensitive[testCredentials]
+	if err := s.ScanContext(t.Context(), `{"token":"sk_live_SECRET","extra":`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+	if out := buf.String(); !strings.Contains(out, `"payload":"[REDACTED]"`) || strings.Contains(out, "SECRET") {
+		t.Errorf("unexpected log output: %s", out)
+	}
+}
@@ -0,0 +1,346 @@
+package jsonsql
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Signed[struct{}])(nil)
+	_ ContextScanner = (*Signed[struct{}])(nil)
+	_ driver.Valuer  = Signed[struct{}]{}
+	_ ContextValuer  = Signed[struct{}]{}
+)
+
+var (
+	// ErrInvalidSignature is returned by Signed.Scan when a document is not signed or its
+	// signature does not verify.
+	ErrInvalidSignature = permanent("jsonsql: invalid document signature")
+	// ErrNoSigner is returned by Signed when no Signer or KeyResolver is configured for its
+	// type parameter.
+	ErrNoSigner = permanent("jsonsql: no Signer configured")
+)
+
+// Signer signs and verifies the documents stored by Signed[T].
+type Signer interface {
+	// Sign returns the signature of payload.
+	Sign(payload []byte) ([]byte, error)
+	// Verify returns an error if signature is not a valid signature of payload.
+	Verify(payload, signature []byte) error
+}
+
+// SignWith sets the Signer used by Signed[T].
+func SignWith(s Signer) Option {
+	return func(c *config) {
+		c.signer = s
+	}
+}
+
+// KeyResolver selects the keys of Signed[T] per operation, for per-tenant keys and key rotation
+// without constructing wrappers per request. It receives the context passed to ScanContext or
+// ValueContext, from which it can read the tenant, and the key ID recorded in stored documents.
+type KeyResolver interface {
+	// SigningKey returns the ID of the current key for ctx and its Signer.
+	// The ID is stored with the document.
+	SigningKey(ctx context.Context) (keyID string, s Signer, err error)
+	// VerificationKey returns the Signer for the key with the given ID, which is empty for
+	// documents written without a KeyResolver.
+	VerificationKey(ctx context.Context, keyID string) (Signer, error)
+}
+
+// ResolveKeys sets the KeyResolver used by Signed[T]. It takes precedence over SignWith:
+//
+//	jsonsql.Configure[License](jsonsql.ResolveKeys(tenantKeys))
+//	_, err := db.ExecContext(ctx, `INSERT INTO licenses (doc) VALUES ($1)`,
+//		jsonsql.ValuerWithContext(ctx, jsonsql.NewSigned(license)))
+//	err = db.QueryRowContext(ctx, `SELECT doc FROM licenses WHERE id = $1`, id).
+//		Scan(jsonsql.WithContext(ctx, &signed))
+func ResolveKeys(r KeyResolver) Option {
+	return func(c *config) {
+		c.keyResolver = r
+	}
+}
+
+// signingKey returns the key ID and Signer to sign new documents with according to cfg.
+func signingKey(ctx context.Context, cfg *config) (string, Signer, error) {
+	if cfg.keyResolver != nil {
+		return cfg.keyResolver.SigningKey(ctx)
+	}
+	return "", cfg.signer, nil
+}
+
+// verificationKey returns the Signer to verify a document signed with keyID according to cfg.
+// A KeyResolver returning no Signer for keyID, which comes from the stored document, fails
+// with ErrInvalidSignature.
+func verificationKey(ctx context.Context, cfg *config, keyID string) (Signer, error) {
+	if cfg.keyResolver == nil {
+		return cfg.signer, nil
+	}
+	signer, err := cfg.keyResolver.VerificationKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if signer == nil {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidSignature, keyID)
+	}
+	return signer, nil
+}
+
+// HMACSigner returns a Signer using HMAC-SHA256 with the given secret key.
+func HMACSigner(key []byte) Signer {
+	return hmacSigner{key: key}
+}
+
+type hmacSigner struct {
+	key []byte
+}
+
+func (s hmacSigner) Sign(payload []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
+
+func (s hmacSigner) Verify(payload, signature []byte) error {
+	expected, _ := s.Sign(payload)
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Ed25519Signer returns a Signer signing with the given Ed25519 private key.
+func Ed25519Signer(key ed25519.PrivateKey) Signer {
+	return ed25519Signer{priv: key, pub: key.Public().(ed25519.PublicKey)}
+}
+
+// Ed25519Verifier returns a Signer that only verifies with the given Ed25519 public key,
+// for readers that must not be able to write documents. Its Sign method always fails.
+func Ed25519Verifier(key ed25519.PublicKey) Signer {
+	return ed25519Signer{pub: key}
+}
+
+type ed25519Signer struct {
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+func (s ed25519Signer) Sign(payload []byte) ([]byte, error) {
+	if s.priv == nil {
+		return nil, errors.New("jsonsql: Ed25519Verifier cannot sign")
+	}
+	return ed25519.Sign(s.priv, payload), nil
+}
+
+func (s ed25519Signer) Verify(payload, signature []byte) error {
+	if !ed25519.Verify(s.pub, payload, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// signedEnvelope is the stored form of a Signed[T]. The payload is kept as a JSON string
+// so that jsonb columns, which reorder keys and drop whitespace, preserve the signed bytes.
+type signedEnvelope struct {
+	Payload   *string `json:"payload"`
+	KeyID     string  `json:"kid,omitempty"`
+	Signature []byte  `json:"signature"`
+}
+
+// Signed[T] is a generic type for NOT NULL JSON columns holding tamper-evident documents in
+// databases shared with other writers. Value stores V together with a detached signature in
+// an envelope, and Scan fails with ErrInvalidSignature unless the signature verifies:
+//
+//	{"payload":"{\"plan\":\"pro\"}","signature":"<base64>"}
+//
+// The Signer is configured per type parameter, or selected per operation with ResolveKeys:
+//
+//	jsonsql.Configure[License](jsonsql.SignWith(jsonsql.HMACSigner(key)))
+//
+// All other options for T apply to the payload, except document codecs, which are not supported,
+// and MaxDocumentSize, which applies to the whole envelope.
+type Signed[T any] struct {
+	V T
+}
+
+// NewSigned creates a new Signed[T] with the given value.
+func NewSigned[T any](v T) Signed[T] {
+	return Signed[T]{V: v}
+}
+
+// Get returns the value.
+func (s Signed[T]) Get() T {
+	return s.V
+}
+
+// Set replaces the value with x.
+func (s *Signed[T]) Set(x T) {
+	s.V = x
+}
+
+// Replace replaces the value with the result of calling f with the current value.
+func (s *Signed[T]) Replace(f func(T) T) {
+	s.V = f(s.V)
+}
+
+// Scan implements sql.Scanner interface.
+// It verifies the signature of the stored document and unmarshals the payload into V.
+// Returns ErrNullNotAllowed if src is nil or the payload is JSON literal "null".
+func (s *Signed[T]) Scan(src any) error {
+	return s.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (s *Signed[T]) ScanContext(ctx context.Context, src any) error {
+	if err := s.scan(ctx, src); err != nil {
+		return fmt.Errorf("jsonsql.Signed.Scan: %w", err)
+	}
+	return nil
+}
+
+func (s *Signed[T]) scan(ctx context.Context, src any) error {
+	cfg := configFor[T]()
+	null, err := decodeSourceWith(ctx, src, &s.V, cfg, ErrorOnEmpty, func(src any, v *T, cfg *config, emptyDefault EmptyPolicy) (bool, error) {
+		if src == nil {
+			return true, nil
+		}
+		payload, err := openEnvelope[T](ctx, cfg, src)
+		if err != nil {
+			return false, err
+		}
+		return decodePayload(payload, v, cfg, emptyDefault)
+	})
+	if err != nil {
+		return err
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V to JSON, signs it and returns the envelope for database storage.
+func (s Signed[T]) Value() (driver.Value, error) {
+	return s.ValueContext(context.Background())
+}
+
+// ValueContext implements ContextValuer interface.
+// It behaves like Value, passing ctx to the KeyResolver.
+func (s Signed[T]) ValueContext(ctx context.Context) (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := s.envelope(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Signed.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+func (s Signed[T]) envelope(ctx context.Context, cfg *config) ([]byte, error) {
+	return encodeValueWith(ctx, s.V, cfg, func(v any, cfg *config) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := checkSigned(cfg); err != nil {
+			return nil, err
+		}
+		payload, err := encodePayload(v, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return sealEnvelope(ctx, cfg, payload)
+	})
+}
+
+// openEnvelope verifies the signed envelope src according to cfg and returns its payload.
+func openEnvelope[T any](ctx context.Context, cfg *config, src any) ([]byte, error) {
+	if err := checkSigned(cfg); err != nil {
+		return nil, err
+	}
+	data, err := jsonBytes[T](src, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var env signedEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, invalidJSON(err)
+	}
+	if env.Payload == nil || env.Signature == nil {
+		return nil, fmt.Errorf("%w: missing payload or signature", ErrInvalidSignature)
+	}
+	signer, err := verificationKey(ctx, cfg, env.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	payload := []byte(*env.Payload)
+	if err := signer.Verify(payload, env.Signature); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// sealEnvelope signs payload with the current key according to cfg and returns the envelope.
+func sealEnvelope(ctx context.Context, cfg *config, payload []byte) ([]byte, error) {
+	keyID, signer, err := signingKey(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+	text := string(payload)
+	return json.Marshal(signedEnvelope{Payload: &text, KeyID: keyID, Signature: sig})
+}
+
+// Resign verifies the stored Signed[T] document src and signs its payload again with the
+// current key, for rotating keys without rewriting rows by hand. The payload bytes are kept
+// as they are, so fields unknown to T survive. Documents already signed with the current key
+// come back unchanged, as both built-in signers are deterministic.
+//
+// The jsonsqlcli package wraps it as a migration for the jsonsql migrate command.
+func Resign[T any](ctx context.Context, src any) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	src, err := resolveSource(src)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Resign: %w", err)
+	}
+	if src == nil {
+		return nil, ErrNullNotAllowed
+	}
+	cfg := configFor[T]()
+	if cfg.typeErr != nil {
+		return nil, fmt.Errorf("jsonsql.Resign: %w", cfg.typeErr)
+	}
+	payload, err := openEnvelope[T](ctx, cfg, src)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Resign: %w", err)
+	}
+	data, err := sealEnvelope(ctx, cfg, payload)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Resign: %w", err)
+	}
+	return data, nil
+}
+
+// checkSigned reports whether cfg can be used by Signed.
+func checkSigned(cfg *config) error {
+	if cfg.signer == nil && cfg.keyResolver == nil {
+		return ErrNoSigner
+	}
+	if cfg.documentCodec != nil {
+		return errors.New("jsonsql: Signed does not support document codecs")
+	}
+	return nil
+}
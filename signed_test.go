@@ -0,0 +1,270 @@
+package jsonsql
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSigned_HMAC(t *testing.T) {
+	resetOptions[testProfile](t)
+
+	if _, err := NewSigned(testProfile{Name: "Alice"}).Value(); !errors.Is(err, ErrNoSigner) {
+		t.Errorf("expected ErrNoSigner, got %v", err)
+	}
+
+	Configure[testProfile](SignWith(HMACSigner([]byte("secret"))))
+	result, err := NewSigned(testProfile{Name: "Alice", Email: "alice@example.com"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	stored := result.([]byte)
+	if !strings.HasPrefix(string(stored), `{"payload":"{\"name\":\"Alice\",\"email\":\"alice@example.com\"}","signature":"`) {
+		t.Errorf("unexpected envelope %s", stored)
+	}
+
+	var s Signed[testProfile]
+	if err := s.Scan(stored); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.V.Name != "Alice" || s.V.Email != "alice@example.com" {
+		t.Errorf("unexpected value %+v", s.V)
+	}
+
+	// jsonb reformats the envelope but keeps the payload string intact.
+	var env map[string]any
+	if err := json.Unmarshal(stored, &env); err != nil {
+		t.Fatal(err)
+	}
+	reordered, _ := json.MarshalIndent(map[string]any{"signature": env["signature"], "payload": env["payload"]}, "", "  ")
+	if err := s.Scan(string(reordered)); err != nil {
+		t.Errorf("Scan of reformatted envelope failed: %v", err)
+	}
+
+	tampered := strings.Replace(string(stored), "Alice", "Mallory", 1)
+	if err := s.Scan(tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+	if err := s.Scan(`{"name":"Alice"}`); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for unsigned document, got %v", err)
+	}
+	if err := s.Scan(`{"payload":`); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+	if err := s.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+
+	Configure[testProfile](SignWith(HMACSigner([]byte("other"))))
+	if err := s.Scan(stored); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature with another key, got %v", err)
+	}
+}
+
+func TestSigned_Ed25519(t *testing.T) {
+	resetOptions[testProfile](t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Configure[testProfile](SignWith(Ed25519Signer(priv)))
+	result, err := NewSigned(testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	Configure[testProfile](SignWith(Ed25519Verifier(pub)))
+	var s Signed[testProfile]
+	if err := s.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.V.Name != "Bob" {
+		t.Errorf("unexpected value %+v", s.V)
+	}
+	if _, err := s.Value(); err == nil {
+		t.Error("expected Ed25519Verifier to refuse signing")
+	}
+}
+
+// testTenantKeys signs with version 2 of the tenant key and verifies any known version.
+type testTenantKeys map[string]Signer
+
+func (k testTenantKeys) SigningKey(ctx context.Context) (string, Signer, error) {
+	keyID := ctx.Value(testTenantKey{}).(string) + "/v2"
+	return keyID, k[keyID], nil
+}
+
+func (k testTenantKeys) VerificationKey(ctx context.Context, keyID string) (Signer, error) {
+	tenant := ctx.Value(testTenantKey{}).(string)
+	if !strings.HasPrefix(keyID, tenant+"/") || k[keyID] == nil {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	return k[keyID], nil
+}
+
+func TestSigned_KeyResolver(t *testing.T) {
+	resetOptions[testProfile](t)
+	keys := testTenantKeys{
+		"acme/v1":   HMACSigner([]byte("acme-old")),
+		"acme/v2":   HMACSigner([]byte("acme-new")),
+		"globex/v2": HMACSigner([]byte("globex")),
+	}
+	Configure[testProfile](ResolveKeys(keys))
+	acme := context.WithValue(context.Background(), testTenantKey{}, "acme")
+	globex := context.WithValue(context.Background(), testTenantKey{}, "globex")
+
+	result, err := ValuerWithContext(acme, NewSigned(testProfile{Name: "Alice"})).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !strings.Contains(string(result.([]byte)), `"kid":"acme/v2"`) {
+		t.Errorf("expected key ID in envelope, got %s", result)
+	}
+
+	var s Signed[testProfile]
+	if err := WithContext(acme, &s).Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.V.Name != "Alice" {
+		t.Errorf("unexpected value %+v", s.V)
+	}
+	if err := s.ScanContext(globex, result); err == nil || !strings.Contains(err.Error(), `unknown key "acme/v2"`) {
+		t.Errorf("expected another tenant to be rejected, got %v", err)
+	}
+
+	// Documents signed with an older key version still verify.
+	Configure[testProfile](SignWith(keys["acme/v1"]))
+	old, err := NewSigned(testProfile{Name: "Old"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	Configure[testProfile](ResolveKeys(keys))
+	if err := s.ScanContext(acme, old); err == nil {
+		t.Error("expected a document without key ID to be rejected by the resolver")
+	}
+	withKID := strings.Replace(string(old.([]byte)), `"signature"`, `"kid":"acme/v1","signature"`, 1)
+	if err := s.ScanContext(acme, withKID); err != nil || s.V.Name != "Old" {
+		t.Errorf("expected old key version to verify, got %+v, %v", s.V, err)
+	}
+}
+
+// testMapKeys resolves keys by ID, returning no Signer for unknown IDs.
+type testMapKeys map[string]Signer
+
+func (k testMapKeys) SigningKey(ctx context.Context) (string, Signer, error) {
+	return "current", k["current"], nil
+}
+
+func (k testMapKeys) VerificationKey(ctx context.Context, keyID string) (Signer, error) {
+	return k[keyID], nil
+}
+
+func TestSigned_UnknownKeyID(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](ResolveKeys(testMapKeys{"current": HMACSigner([]byte("secret"))}))
+
+	result, err := NewSigned(testProfile{Name: "Alice"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	forged := strings.Replace(string(result.([]byte)), `"kid":"current"`, `"kid":"retired"`, 1)
+
+	var s Signed[testProfile]
+	err = s.Scan(forged)
+	if !errors.Is(err, ErrInvalidSignature) || !strings.Contains(err.Error(), `unknown key id "retired"`) {
+		t.Errorf("expected ErrInvalidSignature for unknown key ID, got %v", err)
+	}
+}
+
+func TestResign(t *testing.T) {
+	resetOptions[testProfile](t)
+	keys := testTenantKeys{
+		"acme/v1": HMACSigner([]byte("acme-old")),
+		"acme/v2": HMACSigner([]byte("acme-new")),
+	}
+	acme := context.WithValue(context.Background(), testTenantKey{}, "acme")
+
+	old, err := sealEnvelope(acme, &config{signer: keys["acme/v1"]}, []byte(`{"name":"Alice","legacy":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old = []byte(strings.Replace(string(old), `"signature"`, `"kid":"acme/v1","signature"`, 1))
+
+	Configure[testProfile](ResolveKeys(keys))
+	resigned, err := Resign[testProfile](acme, old)
+	if err != nil {
+		t.Fatalf("Resign failed: %v", err)
+	}
+	if !strings.Contains(string(resigned), `"kid":"acme/v2"`) || !strings.Contains(string(resigned), `\"legacy\":true`) {
+		t.Errorf("unexpected envelope %s", resigned)
+	}
+	var s Signed[testProfile]
+	if err := s.ScanContext(acme, resigned); err != nil || s.V.Name != "Alice" {
+		t.Errorf("expected re-signed document to scan, got %+v, %v", s.V, err)
+	}
+
+	again, err := Resign[testProfile](acme, resigned)
+	if err != nil || string(again) != string(resigned) {
+		t.Errorf("expected current key to leave the document unchanged, got %s, %v", again, err)
+	}
+
+	tampered := strings.Replace(string(old), "Alice", "Mallory", 1)
+	if _, err := Resign[testProfile](acme, tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+	if _, err := Resign[testProfile](acme, nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestSigned_Hooks(t *testing.T) {
+	resetOptions[testProfile](t)
+
+	var scans int
+	var errs []error
+	Configure[testProfile](SignWith(HMACSigner([]byte("secret"))), Observe(Hooks{
+		OnScan:  func(HookEvent) { scans++ },
+		OnError: func(e HookEvent) { errs = append(errs, e.Err) },
+	}))
+	stored, err := NewSigned(testProfile{Name: "Alice"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var s Signed[testProfile]
+	if err := s.Scan(stored); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	tampered := strings.Replace(string(stored.([]byte)), "Alice", "Mallory", 1)
+	if err := s.Scan(tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if scans != 2 {
+		t.Errorf("expected 2 reported scans, got %d", scans)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature to be reported, got %v", errs)
+	}
+}
+
+func TestSigned_Replace(t *testing.T) {
+	s := NewSigned(testProfile{Name: "Alice"})
+	s.Replace(func(p testProfile) testProfile {
+		p.Name += " Smith"
+		return p
+	})
+	if s.Get().Name != "Alice Smith" {
+		t.Errorf("unexpected value %+v", s.V)
+	}
+}
+
+func TestResign_UnsupportedType(t *testing.T) {
+	var typeErr *TypeParamError
+	if _, err := Resign[chan int](context.Background(), `{}`); !errors.As(err, &typeErr) {
+		t.Errorf("expected TypeParamError, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SkipInvalidElements makes Scan decode documents of slice type parameters, such as
+// Value[[]Event] and Nullable[[]Event], element by element, dropping the elements that fail
+// to decode instead of failing the whole document. Ingestion jobs over historical arrays
+// with a few dirty entries can then keep the good ones:
+//
+//	jsonsql.Configure[[]Event](jsonsql.SkipInvalidElements(true))
+//
+//	err := row.Scan(&events)
+//	var skipped *jsonsql.SkippedElementsError
+//	if errors.As(err, &skipped) {
+//		log.Printf("skipped elements %v: %v", skipped.Indexes(), err)
+//	}
+//
+// Scan then still returns an error, a *SkippedElementsError, but V holds the remaining
+// elements in order and Nullable[T] is valid. Required fields are checked per element.
+// Documents that are not arrays fail as usual, and nested slices are not affected.
+func SkipInvalidElements(enabled bool) Option {
+	return func(c *config) {
+		c.skipInvalid = enabled
+	}
+}
+
+// SkippedElementsError is returned by Scan with SkipInvalidElements when elements were dropped.
+type SkippedElementsError struct {
+	// Errs holds one error per skipped element, each a *FieldError whose path starts with
+	// the index of the element in the stored array.
+	Errs []*FieldError
+}
+
+// Error implements the error interface.
+func (e *SkippedElementsError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("jsonsql: skipped %d invalid elements: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the skipped elements.
+func (e *SkippedElementsError) Unwrap() []error {
+	errs := make([]error, len(e.Errs))
+	for i, err := range e.Errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Indexes returns the indexes of the skipped elements in the stored array.
+func (e *SkippedElementsError) Indexes() []int {
+	indexes := make([]int, len(e.Errs))
+	for i, err := range e.Errs {
+		token, _, _ := strings.Cut(strings.TrimPrefix(err.Path, "/"), "/")
+		indexes[i], _ = strconv.Atoi(token)
+	}
+	return indexes
+}
+
+// skipsElements reports whether cfg decodes documents of type t element by element.
+func (c *config) skipsElements(t reflect.Type) bool {
+	return c.skipInvalid && t.Kind() == reflect.Slice && !implementsJSON(t)
+}
+
+// unmarshalElements decodes the JSON array data into the slice v element by element,
+// keeping the elements that decode and reporting the others in a *SkippedElementsError.
+func unmarshalElements(data []byte, v any, cfg *config) error {
+	data, err := trimTrailing(data, cfg)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v).Elem()
+	t := rv.Type()
+	var elems []json.RawMessage
+	if err := decodeComposite(data, '[', &elems, t); err != nil {
+		return invalidJSON(err)
+	}
+
+	s := reflect.MakeSlice(t, 0, len(elems))
+	skipped := &SkippedElementsError{}
+	for i, raw := range elems {
+		elem := reflect.New(t.Elem())
+		err := unmarshal(raw, elem.Interface(), cfg)
+		if err == nil {
+			err = checkRequired(raw, t.Elem(), cfg)
+		}
+		if err != nil {
+			skipped.Errs = append(skipped.Errs, elementError(i, err))
+			continue
+		}
+		s = reflect.Append(s, elem.Elem())
+	}
+	rv.Set(s)
+	if len(skipped.Errs) > 0 {
+		return skipped
+	}
+	return nil
+}
+
+// elementError returns err, a decode error of the array element at index i, as a *FieldError
+// whose path starts with i. A single error already located inside the element keeps its path.
+func elementError(i int, err error) *FieldError {
+	prefix := "/" + strconv.Itoa(i)
+	if joined, ok := err.(interface{ Unwrap() []error }); ok && len(joined.Unwrap()) == 1 {
+		err = joined.Unwrap()[0]
+	}
+	if fieldErr, ok := err.(*FieldError); ok {
+		return &FieldError{Path: prefix + fieldErr.Path, Err: fieldErr.Err}
+	}
+	return &FieldError{Path: prefix, Err: err}
+}
@@ -0,0 +1,64 @@
+package jsonsql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testReading struct {
+	Sensor string  `json:"sensor" jsonsql:"required"`
+	Value  float64 `json:"value"`
+}
+
+func TestSkipInvalidElements(t *testing.T) {
+	resetOptions[[]testReading](t)
+	const doc = `[{"sensor":"a","value":1},{"sensor":"b","value":"x"},{"value":3},7,{"sensor":"e","value":5}]`
+
+	var v Value[[]testReading]
+	if err := v.Scan(doc); err == nil {
+		t.Fatal("expected error without SkipInvalidElements")
+	}
+
+	Configure[[]testReading](SkipInvalidElements(true))
+	err := v.Scan(doc)
+	var skipped *SkippedElementsError
+	if !errors.As(err, &skipped) {
+		t.Fatalf("expected SkippedElementsError, got %v", err)
+	}
+	expected := []testReading{{Sensor: "a", Value: 1}, {Sensor: "e", Value: 5}}
+	if !reflect.DeepEqual(v.V, expected) {
+		t.Errorf("expected %+v, got %+v", expected, v.V)
+	}
+	if indexes := skipped.Indexes(); !reflect.DeepEqual(indexes, []int{1, 2, 3}) {
+		t.Errorf("unexpected indexes %v", indexes)
+	}
+	paths := make([]string, len(skipped.Errs))
+	for i, e := range skipped.Errs {
+		paths[i] = e.Path
+	}
+	if !reflect.DeepEqual(paths, []string{"/1/value", "/2/sensor", "/3"}) {
+		t.Errorf("unexpected paths %v", paths)
+	}
+	if !errors.Is(err, ErrMissingField) {
+		t.Errorf("expected ErrMissingField among the errors, got %v", err)
+	}
+
+	var n Nullable[[]testReading]
+	if err := n.Scan(doc); !errors.As(err, &skipped) {
+		t.Fatalf("expected SkippedElementsError, got %v", err)
+	}
+	if !n.Valid || len(n.V) != 2 {
+		t.Errorf("expected valid partial result, got %+v", n)
+	}
+
+	if err := v.Scan(`[{"sensor":"a"}]`); err != nil {
+		t.Errorf("Scan failed: %v", err)
+	}
+	if err := v.Scan(`{"sensor":"a"}`); err == nil || errors.As(err, &skipped) {
+		t.Errorf("expected plain error for non-array document, got %v", err)
+	}
+	if err := v.Scan(`[{"sensor":"a"}] x`); !errors.Is(err, ErrTrailingData) {
+		t.Errorf("expected ErrTrailingData, got %v", err)
+	}
+}
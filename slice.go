@@ -0,0 +1,59 @@
+package jsonsql
+
+import "slices"
+
+// Contains reports whether the slice held by v contains x.
+func Contains[T comparable](v Value[[]T], x T) bool {
+	return slices.Contains(v.V, x)
+}
+
+// IndexOf returns the index of the first occurrence of x in the slice held by v, or -1.
+func IndexOf[T comparable](v Value[[]T], x T) int {
+	return slices.Index(v.V, x)
+}
+
+// Dedupe returns a Value[[]T] holding the elements of v without duplicates, keeping the first
+// occurrence of each element in order. The slice held by v is not modified.
+func Dedupe[T comparable](v Value[[]T]) Value[[]T] {
+	return Value[[]T]{V: dedupe(v.V)}
+}
+
+// NullableContains reports whether the slice held by n contains x. It returns false when n is NULL.
+func NullableContains[T comparable](n Nullable[[]T], x T) bool {
+	return n.Valid && slices.Contains(n.V, x)
+}
+
+// NullableIndexOf returns the index of the first occurrence of x in the slice held by n,
+// or -1 if x is not present or n is NULL.
+func NullableIndexOf[T comparable](n Nullable[[]T], x T) int {
+	if !n.Valid {
+		return -1
+	}
+	return slices.Index(n.V, x)
+}
+
+// NullableDedupe is like Dedupe for Nullable[[]T]. NULL stays NULL.
+func NullableDedupe[T comparable](n Nullable[[]T]) Nullable[[]T] {
+	if !n.Valid {
+		return n
+	}
+	return NullableFrom(dedupe(n.V))
+}
+
+// dedupe returns a copy of s without duplicates, keeping the first occurrence of each element.
+// A nil slice stays nil, so it is still stored as JSON null.
+func dedupe[T comparable](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, x := range s {
+		if _, ok := seen[x]; ok {
+			continue
+		}
+		seen[x] = struct{}{}
+		out = append(out, x)
+	}
+	return out
+}
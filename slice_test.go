@@ -0,0 +1,42 @@
+package jsonsql
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSliceHelpers(t *testing.T) {
+	v := NewValue([]string{"a", "b", "a", "c", "b"})
+
+	if !Contains(v, "c") || Contains(v, "x") {
+		t.Error("unexpected Contains result")
+	}
+	if IndexOf(v, "b") != 1 || IndexOf(v, "x") != -1 {
+		t.Error("unexpected IndexOf result")
+	}
+	d := Dedupe(v)
+	if !slices.Equal(d.V, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected Dedupe result %v", d.V)
+	}
+	if len(v.V) != 5 {
+		t.Errorf("Dedupe modified its input: %v", v.V)
+	}
+	if Dedupe(Value[[]int]{}).V != nil {
+		t.Error("expected nil slice to stay nil")
+	}
+}
+
+func TestNullableSliceHelpers(t *testing.T) {
+	n := NullableFrom([]int{3, 1, 3})
+	if !NullableContains(n, 1) || NullableIndexOf(n, 3) != 0 {
+		t.Error("unexpected result for valid Nullable")
+	}
+	if d := NullableDedupe(n); !d.Valid || !slices.Equal(d.V, []int{3, 1}) {
+		t.Errorf("unexpected NullableDedupe result %+v", d)
+	}
+
+	null := Null[[]int]()
+	if NullableContains(null, 0) || NullableIndexOf(null, 0) != -1 || NullableDedupe(null).Valid {
+		t.Error("unexpected result for NULL")
+	}
+}
@@ -0,0 +1,161 @@
+package jsonsql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ fmt.Stringer   = Snapshot[struct{}]{}
+	_ fmt.GoStringer = Snapshot[struct{}]{}
+)
+
+// Snapshot[T] is an immutable deep copy of a value, typically a wrapper, taken so that a request
+// handler can mutate a working copy and roll back before writing:
+//
+//	snap := order.Snapshot()
+//	if err := applyPatch(&order.V, patch); err != nil {
+//		order.Restore(snap)
+//	}
+//
+// The copy is made with reflection rather than through JSON, so every exported field is kept
+// even if it is not part of the document. Pointers, maps, slices and interface values are copied
+// recursively, with shared and cyclic pointers preserved; unexported struct fields, channels and
+// functions are copied shallowly.
+type Snapshot[T any] struct {
+	v T
+}
+
+// NewSnapshot takes a snapshot of v.
+func NewSnapshot[T any](v T) Snapshot[T] {
+	return Snapshot[T]{v: deepCopy(v)}
+}
+
+// Get returns a deep copy of the snapshot, which stays unchanged when the copy is modified.
+func (s Snapshot[T]) Get() T {
+	return deepCopy(s.v)
+}
+
+// String implements fmt.Stringer interface.
+// It formats the snapshot like its value, so Sensitive values stay redacted.
+func (s Snapshot[T]) String() string {
+	return fmt.Sprint(s.v)
+}
+
+// GoString implements fmt.GoStringer interface.
+func (s Snapshot[T]) GoString() string {
+	return fmt.Sprintf("jsonsql.Snapshot{%#v}", s.v)
+}
+
+// Snapshot takes a snapshot of v for Restore.
+func (v Value[T]) Snapshot() Snapshot[Value[T]] {
+	return NewSnapshot(v)
+}
+
+// Restore replaces v with a deep copy of s.
+func (v *Value[T]) Restore(s Snapshot[Value[T]]) {
+	*v = s.Get()
+}
+
+// Snapshot takes a snapshot of n, including Valid, for Restore.
+func (n Nullable[T]) Snapshot() Snapshot[Nullable[T]] {
+	return NewSnapshot(n)
+}
+
+// Restore replaces n with a deep copy of s.
+func (n *Nullable[T]) Restore(s Snapshot[Nullable[T]]) {
+	*n = s.Get()
+}
+
+// Snapshot takes a snapshot of s for Restore.
+func (s Sensitive[T]) Snapshot() Snapshot[Sensitive[T]] {
+	return NewSnapshot(s)
+}
+
+// Restore replaces s with a deep copy of snap.
+func (s *Sensitive[T]) Restore(snap Snapshot[Sensitive[T]]) {
+	*s = snap.Get()
+}
+
+// deepCopy returns a deep copy of v; see Snapshot.
+func deepCopy[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src, map[copiedPointer]reflect.Value{})
+	return dst.Interface().(T)
+}
+
+// copiedPointer identifies a pointer already copied by copyValue.
+type copiedPointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// copyValue sets dst, which must be settable, to a deep copy of src.
+// copied maps the pointers copied so far to their copies.
+func copyValue(dst, src reflect.Value, copied map[copiedPointer]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := copiedPointer{addr: src.Pointer(), typ: src.Type()}
+		if p, ok := copied[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		copied[key] = p
+		copyValue(p.Elem(), src.Elem(), copied)
+		dst.Set(p)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		copyValue(elem, src.Elem(), copied)
+		dst.Set(elem)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		elem := reflect.New(src.Type().Elem()).Elem()
+		for iter := src.MapRange(); iter.Next(); {
+			elem.SetZero()
+			copyValue(elem, iter.Value(), copied)
+			m.SetMapIndex(iter.Key(), elem)
+		}
+		dst.Set(m)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			copyValue(s.Index(i), src.Index(i), copied)
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := range src.Len() {
+			copyValue(dst.Index(i), src.Index(i), copied)
+		}
+
+	case reflect.Struct:
+		dst.Set(src)
+		for i := range src.NumField() {
+			if f := dst.Field(i); f.CanSet() {
+				f.SetZero()
+				copyValue(f, src.Field(i), copied)
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,95 @@
+package jsonsql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSnapshotNode struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]any
+	Next     *testSnapshotNode
+	Created  time.Time
+	Internal string `json:"-"`
+	secret   *int
+}
+
+func TestValue_SnapshotRestore(t *testing.T) {
+	secret := 1
+	v := NewValue(testSnapshotNode{
+		Name:    "a",
+		Tags:    []string{"x"},
+		Attrs:   map[string]any{"nested": map[string]any{"n": 1}, "list": []any{"p"}},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		secret:  &secret,
+	})
+	v.V.Next = &testSnapshotNode{Name: "b"}
+	v.V.Next.Next = &v.V // cycle
+	snap := v.Snapshot()
+
+	v.V.Name = "changed"
+	v.V.Tags[0] = "changed"
+	v.V.Attrs["nested"].(map[string]any)["n"] = 2
+	v.V.Attrs["list"].([]any)[0] = "changed"
+	v.V.Next.Name = "changed"
+	v.V.Internal = "changed"
+
+	v.Restore(snap)
+	if v.V.Name != "a" || v.V.Tags[0] != "x" || v.V.Next.Name != "b" || v.V.Internal != "" {
+		t.Errorf("unexpected restored value %+v", v.V)
+	}
+	if v.V.Attrs["nested"].(map[string]any)["n"] != 1 || v.V.Attrs["list"].([]any)[0] != "p" {
+		t.Errorf("unexpected restored attrs %v", v.V.Attrs)
+	}
+	if v.V.Next.Next.Name != "a" {
+		t.Errorf("expected cycle to be preserved")
+	}
+	if v.V.Next.Next.Next != v.V.Next {
+		t.Errorf("expected shared pointers to stay shared")
+	}
+	if !v.V.Created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || v.V.secret != &secret {
+		t.Errorf("expected opaque and unexported fields to be copied, got %+v", v.V)
+	}
+
+	// Restoring twice from the same snapshot yields independent copies.
+	v.V.Tags[0] = "again"
+	v.Restore(snap)
+	if v.V.Tags[0] != "x" {
+		t.Errorf("snapshot was modified through a restored copy: %v", v.V.Tags)
+	}
+}
+
+func TestNullable_SnapshotRestore(t *testing.T) {
+	n := NullableFrom(map[string][]int{"a": {1}})
+	snap := n.Snapshot()
+	n.V["a"][0] = 2
+	n.SetNull()
+	n.Restore(snap)
+	if !n.Valid || n.V["a"][0] != 1 {
+		t.Errorf("unexpected restored value %+v", n)
+	}
+
+	var zero Nullable[int]
+	zero.Restore(Snapshot[Nullable[int]]{})
+	if zero.Valid {
+		t.Errorf("expected zero snapshot to restore NULL")
+	}
+}
+
+func TestSensitive_SnapshotRedacted(t *testing.T) {
+	s := NewSensitive("token-123")
+	snap := s.Snapshot()
+	for _, out := range []string{fmt.Sprint(snap), fmt.Sprintf("%v", snap), fmt.Sprintf("%#v", snap), fmt.Sprintf("%+v", snap)} {
+		if strings.Contains(out, "token-123") {
+			t.Errorf("snapshot leaked the secret: %s", out)
+		}
+	}
+	s.Set("other")
+	s.Restore(snap)
+	if s.Get() != "token-123" {
+		t.Errorf("unexpected restored value")
+	}
+}
@@ -0,0 +1,216 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*SoftDelete[struct{}])(nil)
+	_ ContextScanner = (*SoftDelete[struct{}])(nil)
+	_ driver.Valuer  = SoftDelete[struct{}]{}
+)
+
+// DeletedAtKey is the key of the deletion time in documents stored by SoftDelete[T].
+const DeletedAtKey = "deleted_at"
+
+// SoftDelete[T] is a generic type for NOT NULL JSON columns of tables where logical deletion
+// lives inside the document. Value stores V, which must encode as a JSON object, with the
+// deletion time added under DeletedAtKey:
+//
+//	{"deleted_at":"2024-05-01T12:00:00Z","name":"Alice"}
+//
+// The key is omitted while the document is not deleted, and Scan treats a missing key and
+// JSON null alike. T must not have a field of its own stored under DeletedAtKey.
+// Use SoftDeleted and NotSoftDeleted to filter rows in queries.
+type SoftDelete[T any] struct {
+	V T
+	// DeletedAt is the deletion time, or nil if the document is not deleted.
+	DeletedAt *time.Time
+}
+
+// NewSoftDelete creates a new SoftDelete[T] with the given value that is not deleted.
+func NewSoftDelete[T any](v T) SoftDelete[T] {
+	return SoftDelete[T]{V: v}
+}
+
+// Get returns the value.
+func (s SoftDelete[T]) Get() T {
+	return s.V
+}
+
+// IsDeleted reports whether the document is deleted.
+func (s SoftDelete[T]) IsDeleted() bool {
+	return s.DeletedAt != nil
+}
+
+// MarkDeleted marks the document as deleted at the given time, stored in UTC.
+func (s *SoftDelete[T]) MarkDeleted(at time.Time) {
+	at = at.UTC()
+	s.DeletedAt = &at
+}
+
+// Undelete clears the deletion time.
+func (s *SoftDelete[T]) Undelete() {
+	s.DeletedAt = nil
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals JSON data from the database into V and reads the deletion time.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (s *SoftDelete[T]) Scan(src any) error {
+	return s.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (s *SoftDelete[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeSourceContext(ctx, src, &s.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.SoftDelete.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	deletedAt, err := scanDeletedAt[T](src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.SoftDelete.Scan: %s: %w", DeletedAtKey, err)
+	}
+	s.DeletedAt = deletedAt
+	dropMetadataKey(&s.V, DeletedAtKey)
+	return nil
+}
+
+// scanDeletedAt reads the deletion time from a source value already decoded successfully.
+func scanDeletedAt[T any](src any) (*time.Time, error) {
+	var doc struct {
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+	if err := scanMetadata[T](src, &doc); err != nil {
+		return nil, err
+	}
+	return doc.DeletedAt, nil
+}
+
+// scanMetadata decodes the keys a wrapper adds to the documents of T from a source value
+// already decoded successfully into dst with encoding/json. The source is normalized like it
+// was for T, except that the stored keys are kept. Data after the document is ignored, as
+// decoding T already rejected it unless AllowTrailingData is enabled.
+func scanMetadata[T any](src, dst any) error {
+	cfg := configFor[T]()
+	src, err := resolveSource(src)
+	if err != nil {
+		return err
+	}
+	data, err := jsonBytes[T](src, cfg)
+	if err != nil {
+		return err
+	}
+	if data, err = normalizeSyntax(data, cfg); err != nil {
+		return err
+	}
+	return json.NewDecoder(bytes.NewReader(data)).Decode(dst)
+}
+
+// dropMetadataKey removes the key a wrapper adds to the documents of T from v when T is
+// a map type, so that it is not written twice by Value.
+func dropMetadataKey[T any](v *T, key string) {
+	rv := reflect.ValueOf(v).Elem()
+	if rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Map || rv.IsNil() || rv.Type().Key().Kind() != reflect.String {
+		return
+	}
+	rv.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), reflect.Value{})
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V to a JSON object with the deletion time for database storage.
+func (s SoftDelete[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := s.encode(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.SoftDelete.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
+
+func (s SoftDelete[T]) encode(cfg *config) ([]byte, error) {
+	if cfg.documentCodec != nil {
+		return nil, errors.New("jsonsql: SoftDelete does not support document codecs")
+	}
+	data, err := encodeValue(s.V, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if s.DeletedAt == nil {
+		return objectDocument(data, "SoftDelete")
+	}
+	at, err := json.Marshal(s.DeletedAt.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return withLeadingKey(data, DeletedAtKey, at, "SoftDelete")
+}
+
+// objectDocument returns data if it is a JSON object, or an error naming wrapper otherwise.
+func objectDocument(data []byte, wrapper string) ([]byte, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, fmt.Errorf("jsonsql: %s requires a JSON object, got %s", wrapper, jsonKind(trimmed))
+	}
+	return data, nil
+}
+
+// withLeadingKey returns the JSON object data with the member key: value inserted first.
+// The key is not escaped. It fails with an error naming wrapper if data is not an object.
+func withLeadingKey(data []byte, key string, value []byte, wrapper string) ([]byte, error) {
+	if _, err := objectDocument(data, wrapper); err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	rest := bytes.TrimLeft(trimmed[1:], " \t\r\n")
+	out := make([]byte, 0, len(data)+len(key)+len(value)+4)
+	out = append(out, '{', '"')
+	out = append(out, key...)
+	out = append(out, '"', ':')
+	out = append(out, value...)
+	if len(rest) > 0 && rest[0] != '}' {
+		out = append(out, ',')
+	}
+	return append(out, trimmed[1:]...), nil
+}
+
+// SoftDeleted returns an SQL condition matching rows whose JSON column stores a deleted
+// SoftDelete document, for use in WHERE clauses:
+//
+//	query := `SELECT doc FROM users WHERE ` + jsonsql.NotSoftDeleted(jsonsql.Postgres, "doc")
+//
+// column is inserted as is and must be a trusted identifier or expression.
+func SoftDeleted(d Dialect, column string) string {
+	return "NOT (" + NotSoftDeleted(d, column) + ")"
+}
+
+// NotSoftDeleted returns an SQL condition matching rows whose JSON column stores a
+// SoftDelete document that is not deleted. See SoftDeleted.
+func NotSoftDeleted(d Dialect, column string) string {
+	path := "'$." + DeletedAtKey + "'"
+	switch d {
+	case MySQL:
+		// JSON_EXTRACT returns a JSON null rather than SQL NULL for "deleted_at":null.
+		return "COALESCE(JSON_TYPE(JSON_EXTRACT(" + column + ", " + path + ")), 'NULL') = 'NULL'"
+	case SQLite:
+		return "json_extract(" + column + ", " + path + ") IS NULL"
+	default:
+		return "(" + column + " ->> '" + DeletedAtKey + "') IS NULL"
+	}
+}
@@ -0,0 +1,140 @@
+package jsonsql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSoftDelete(t *testing.T) {
+	s := NewSoftDelete(testProfile{Name: "Alice"})
+	if s.IsDeleted() {
+		t.Error("expected new document not to be deleted")
+	}
+	result, err := s.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"name":"Alice","email":""}` {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	at := time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	s.MarkDeleted(at)
+	if !s.IsDeleted() || !s.DeletedAt.Equal(at) || s.DeletedAt.Location() != time.UTC {
+		t.Errorf("unexpected DeletedAt %v", s.DeletedAt)
+	}
+	result, err = s.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"deleted_at":"2024-05-01T12:00:00Z","name":"Alice","email":""}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	var scanned SoftDelete[testProfile]
+	if err := scanned.Scan(result); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.V.Name != "Alice" || !scanned.IsDeleted() || !scanned.DeletedAt.Equal(at) {
+		t.Errorf("unexpected value %+v", scanned)
+	}
+
+	if err := scanned.Scan(`{"name":"Bob","deleted_at":null}`); err != nil || scanned.IsDeleted() || scanned.V.Name != "Bob" {
+		t.Errorf("expected JSON null to mean not deleted, got %+v, %v", scanned, err)
+	}
+	scanned.MarkDeleted(at)
+	scanned.Undelete()
+	if scanned.IsDeleted() {
+		t.Error("expected Undelete to clear the deletion time")
+	}
+	if err := scanned.Scan(`{"deleted_at":"yesterday"}`); err == nil || !strings.Contains(err.Error(), "deleted_at") {
+		t.Errorf("expected deleted_at error, got %v", err)
+	}
+	if err := scanned.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestSoftDelete_Objects(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	empty := SoftDelete[map[string]int]{V: map[string]int{}}
+	empty.MarkDeleted(at)
+	result, err := empty.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"deleted_at":"2024-05-01T12:00:00Z"}` {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	list := SoftDelete[[]int]{V: []int{1}}
+	list.MarkDeleted(at)
+	if _, err := list.Value(); err == nil || !strings.Contains(err.Error(), "requires a JSON object, got array") {
+		t.Errorf("expected object error, got %v", err)
+	}
+}
+
+func TestSoftDeletePredicates(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, `(doc ->> 'deleted_at') IS NULL`},
+		{MySQL, `COALESCE(JSON_TYPE(JSON_EXTRACT(doc, '$.deleted_at')), 'NULL') = 'NULL'`},
+		{SQLite, `json_extract(doc, '$.deleted_at') IS NULL`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.String(), func(t *testing.T) {
+			if got := NotSoftDeleted(tt.dialect, "doc"); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+			if got := SoftDeleted(tt.dialect, "doc"); got != "NOT ("+tt.expected+")" {
+				t.Errorf("unexpected SoftDeleted %s", got)
+			}
+		})
+	}
+	if Dialect(9).String() != "Dialect(9)" {
+		t.Errorf("unexpected String %s", Dialect(9))
+	}
+}
+
+func TestSoftDelete_NormalizedInput(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](Lenient(true))
+
+	var s SoftDelete[testProfile]
+	src := "\xef\xbb\xbf{\"deleted_at\":\"2024-05-01T12:00:00Z\", // deleted by support\n\"name\":\"Alice\",}"
+	if err := s.Scan(src); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.V.Name != "Alice" || s.DeletedAt == nil || !s.DeletedAt.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected value %+v deleted at %v", s.V, s.DeletedAt)
+	}
+}
+
+func TestSoftDelete_Map(t *testing.T) {
+	var s SoftDelete[map[string]any]
+	if err := s.Scan([]byte(`{"deleted_at":"2024-05-01T12:00:00Z","name":"Alice"}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := s.V[DeletedAtKey]; ok || !s.IsDeleted() {
+		t.Errorf("expected the deletion time outside V, got %v deleted at %v", s.V, s.DeletedAt)
+	}
+
+	data, err := s.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(data.([]byte)) != `{"deleted_at":"2024-05-01T12:00:00Z","name":"Alice"}` {
+		t.Errorf("unexpected document %s", data)
+	}
+
+	s.Undelete()
+	if data, _ := s.Value(); string(data.([]byte)) != `{"name":"Alice"}` {
+		t.Errorf("expected deleted_at to be cleared, got %s", data)
+	}
+}
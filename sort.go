@@ -0,0 +1,74 @@
+package jsonsql
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+)
+
+// SortSlices makes Value store every []E inside the document in ascending order.
+// See SortSlicesFunc.
+func SortSlices[E cmp.Ordered]() Option {
+	return SortSlicesFunc(cmp.Compare[E])
+}
+
+// SortSlicesFunc makes Value store every []E inside the document sorted with compare, using a
+// stable sort, so documents whose slices are sets produce identical bytes regardless of the
+// order the elements were collected in. Together with map keys, which are always written
+// in sorted order, this gives deterministic documents for diffing and replication comparisons:
+//
+//	jsonsql.Configure[Policy](jsonsql.SortSlices[string](), jsonsql.SortSlicesFunc(CompareRule))
+//
+// V itself is not modified and Scan keeps the stored order. Only slices of exactly type []E
+// are sorted, not named slice types or arrays.
+func SortSlicesFunc[E any](compare func(a, b E) int) Option {
+	t := reflect.TypeFor[[]E]()
+	return func(c *config) {
+		c.setHook(t, typeHook{
+			encode: func(rv reflect.Value) ([]byte, error) {
+				if rv.IsNil() {
+					return []byte("null"), nil
+				}
+				sorted := slices.Clone(rv.Interface().([]E))
+				slices.SortStableFunc(sorted, compare)
+
+				sv := reflect.ValueOf(sorted)
+				w := &walker{cfg: c}
+				var buf bytes.Buffer
+				buf.WriteByte('[')
+				for i := range sv.Len() {
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					if err := w.encode(&buf, sv.Index(i)); err != nil {
+						return nil, err
+					}
+				}
+				buf.WriteByte(']')
+				return buf.Bytes(), nil
+			},
+			decode: func(data []byte, rv reflect.Value) error {
+				var elems []json.RawMessage
+				if err := decodeComposite(data, '[', &elems, t); err != nil {
+					return err
+				}
+				s := make([]E, len(elems))
+				sv := reflect.ValueOf(s)
+				w := &walker{cfg: c}
+				for i, raw := range elems {
+					if err := w.decode(raw, sv.Index(i)); err != nil {
+						return err
+					}
+				}
+				if err := errors.Join(w.errs...); err != nil {
+					return err
+				}
+				rv.Set(sv)
+				return nil
+			},
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package jsonsql
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testPolicy struct {
+	Roles []string         `json:"roles"`
+	Rules []testPolicyRule `json:"rules"`
+	Meta  map[string]int   `json:"meta"`
+	Empty []string         `json:"empty"`
+}
+
+type testPolicyRule struct {
+	Resource string `json:"resource"`
+	Actions  []string
+}
+
+func TestSortSlices(t *testing.T) {
+	resetOptions[testPolicy](t)
+	Configure[testPolicy](
+		SortSlices[string](),
+		SortSlicesFunc(func(a, b testPolicyRule) int { return strings.Compare(a.Resource, b.Resource) }),
+	)
+
+	p := testPolicy{
+		Roles: []string{"viewer", "admin", "editor"},
+		Rules: []testPolicyRule{
+			{Resource: "orders", Actions: []string{"write", "read"}},
+			{Resource: "billing", Actions: []string{"read"}},
+		},
+		Meta: map[string]int{"z": 1, "a": 2},
+	}
+	result, err := NewValue(p).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	expected := `{"roles":["admin","editor","viewer"],` +
+		`"rules":[{"resource":"billing","Actions":["read"]},{"resource":"orders","Actions":["read","write"]}],` +
+		`"meta":{"a":2,"z":1},"empty":null}`
+	if string(result.([]byte)) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+	if !slices.Equal(p.Roles, []string{"viewer", "admin", "editor"}) {
+		t.Errorf("Value modified V: %v", p.Roles)
+	}
+
+	var v Value[testPolicy]
+	if err := v.Scan(`{"roles":["b","a"],"rules":[{"resource":"x","Actions":["z","y"]}]}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !slices.Equal(v.V.Roles, []string{"b", "a"}) || !slices.Equal(v.V.Rules[0].Actions, []string{"z", "y"}) {
+		t.Errorf("Scan must keep the stored order, got %+v", v.V)
+	}
+	if err := v.Scan(`{"roles":{}}`); err == nil {
+		t.Error("expected type error")
+	}
+}
+
+func TestSortSlices_TopLevel(t *testing.T) {
+	resetOptions[[]int](t)
+	Configure[[]int](SortSlices[int]())
+
+	result, err := NewValue([]int{3, 1, 2}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `[1,2,3]` {
+		t.Errorf("unexpected result %s", result)
+	}
+}
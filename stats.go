@@ -0,0 +1,139 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*Measured[struct{}])(nil)
+	_ ContextScanner = (*Measured[struct{}])(nil)
+	_ driver.Valuer  = Measured[struct{}]{}
+)
+
+// Stats describes the size and shape of a scanned document.
+type Stats struct {
+	// Size is the size of the stored document in bytes.
+	Size int
+	// Keys is the number of keys of the top-level object, 0 for other documents.
+	Keys int
+	// Depth is the maximum nesting depth of objects and arrays, 0 for scalar documents.
+	Depth int
+}
+
+// DocumentStats returns the Stats of the JSON document data. It makes a single pass over
+// data without allocating or decoding it; malformed documents give meaningless results.
+func DocumentStats(data []byte) Stats {
+	return documentStats(data)
+}
+
+// documentStats implements DocumentStats for string and byte slice documents.
+func documentStats[D ~string | ~[]byte](data D) Stats {
+	s := Stats{Size: len(data)}
+	depth := 0
+	rootObject := false
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+		case '{', '[':
+			if depth == 0 {
+				rootObject = data[i] == '{'
+			}
+			depth++
+			s.Depth = max(s.Depth, depth)
+		case '}', ']':
+			depth--
+		case ':':
+			if depth == 1 && rootObject {
+				s.Keys++
+			}
+		}
+	}
+	return s
+}
+
+// Measured[T] is a generic type for NOT NULL JSON columns that records the Stats of the
+// document on Scan, so services can report how documents grow per table without parsing
+// them again:
+//
+//	var doc jsonsql.Measured[Settings]
+//	err := row.Scan(&doc)
+//	sizes.Observe(float64(doc.Stats().Size))
+//
+// It otherwise behaves like Value[T].
+type Measured[T any] struct {
+	V     T
+	stats Stats
+}
+
+// NewMeasured creates a new Measured[T] with the given value.
+func NewMeasured[T any](v T) Measured[T] {
+	return Measured[T]{V: v}
+}
+
+// Get returns the value.
+func (m Measured[T]) Get() T {
+	return m.V
+}
+
+// Stats returns the Stats of the document read by the last successful Scan, or zero Stats
+// if m was not scanned. Documents stored with a DocumentCodec or scanned from sources other
+// than JSON text only report their Size.
+func (m Measured[T]) Stats() Stats {
+	return m.stats
+}
+
+// Scan implements sql.Scanner interface.
+// It unmarshals JSON data from the database into V and records its Stats.
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+func (m *Measured[T]) Scan(src any) error {
+	return m.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (m *Measured[T]) ScanContext(ctx context.Context, src any) error {
+	src, err := resolveSource(src)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Measured.Scan: %w", err)
+	}
+	null, err := decodeSourceContext(ctx, src, &m.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Measured.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	m.stats = Stats{Size: payloadSize(src)}
+	if configFor[T]().documentCodec == nil {
+		switch s := src.(type) {
+		case []byte:
+			m.stats = documentStats(s)
+		case string:
+			m.stats = documentStats(s)
+		case json.RawMessage:
+			m.stats = documentStats(s)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals V to JSON bytes for database storage.
+func (m Measured[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := encodeValue(m.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Measured.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
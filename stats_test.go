@@ -0,0 +1,49 @@
+package jsonsql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocumentStats(t *testing.T) {
+	tests := []struct {
+		doc   string
+		stats Stats
+	}{
+		{`{"a":1,"b":{"c":[1,2,{"d":"x:{"}]},"e":"]"}`, Stats{Size: 43, Keys: 3, Depth: 4}},
+		{`[{"a":1},{"b":2}]`, Stats{Size: 17, Keys: 0, Depth: 2}},
+		{`{}`, Stats{Size: 2, Keys: 0, Depth: 1}},
+		{`"a\"{"`, Stats{Size: 6, Keys: 0, Depth: 0}},
+		{`42`, Stats{Size: 2}},
+	}
+	for _, tt := range tests {
+		if got := DocumentStats([]byte(tt.doc)); got != tt.stats {
+			t.Errorf("DocumentStats(%s): expected %+v, got %+v", tt.doc, tt.stats, got)
+		}
+	}
+}
+
+func TestMeasured(t *testing.T) {
+	var m Measured[testProfile]
+	if err := m.Scan([]byte(`{"name":"Alice","email":"alice@example.com"}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if m.V.Name != "Alice" {
+		t.Errorf("unexpected value %+v", m.V)
+	}
+	if s := m.Stats(); s != (Stats{Size: 44, Keys: 2, Depth: 1}) {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	if err := m.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+
+	result, err := NewMeasured(testProfile{Name: "Bob"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"name":"Bob","email":""}` {
+		t.Errorf("unexpected document %s", result)
+	}
+}
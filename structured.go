@@ -0,0 +1,25 @@
+package jsonsql
+
+import (
+	"reflect"
+	"time"
+)
+
+// isStructuredSource reports whether src is an already-decoded document, such as the
+// map[string]any, []any and tuple structs that clickhouse-go returns for JSON and
+// Object('json') columns. Scan re-encodes such values as JSON and decodes them with the usual
+// rules and options, so the wrappers work with drivers that do not return JSON text.
+// time.Time is excluded; it is a driver scalar handled by CoerceScalars.
+func isStructuredSource(src any) bool {
+	t := reflect.TypeOf(src)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	case reflect.Struct:
+		return t != reflect.TypeFor[time.Time]()
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package jsonsql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testCHJSON mimics a driver type that holds a decoded document and implements json.Marshaler.
+type testCHJSON struct {
+	paths map[string]any
+}
+
+func (j *testCHJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.paths)
+}
+
+func TestScan_StructuredSource(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+	}{
+		{"map", map[string]any{"name": "Alice", "email": "alice@example.com"}},
+		{"string map", map[string]string{"name": "Alice", "email": "alice@example.com"}},
+		{"marshaler", &testCHJSON{paths: map[string]any{"name": "Alice", "email": "alice@example.com"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value[testProfile]
+			if err := v.Scan(tt.src); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if v.V.Name != "Alice" || v.V.Email != "alice@example.com" {
+				t.Errorf("unexpected result: %+v", v.V)
+			}
+		})
+	}
+}
+
+func TestScan_StructuredSource_Tuple(t *testing.T) {
+	var v Value[[]any]
+	if err := v.Scan([]any{int64(1), "a", []any{true}}); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(v.V) != 3 || v.V[0] != float64(1) || v.V[1] != "a" {
+		t.Errorf("unexpected result: %v", v.V)
+	}
+}
+
+func TestScan_StructuredSource_NilMap(t *testing.T) {
+	n := NullableFrom(map[string]any{"a": 1})
+	if err := n.Scan(map[string]any(nil)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if n.Valid {
+		t.Errorf("expected NULL for nil map, got %+v", n)
+	}
+}
+
+func TestScan_StructuredSource_Unsupported(t *testing.T) {
+	var v Value[map[string]any]
+	if err := v.Scan(time.Now()); err == nil {
+		t.Error("expected error for time.Time without CoerceScalars")
+	}
+	if err := v.Scan(map[string]any{"c": make(chan int)}); err == nil {
+		t.Error("expected error for unencodable map")
+	} else if errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package jsonsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*SyncValue[struct{}])(nil)
+	_ ContextScanner = (*SyncValue[struct{}])(nil)
+	_ driver.Valuer  = (*SyncValue[struct{}])(nil)
+)
+
+// SyncValue[T] is a NOT NULL JSON column value that is safe for concurrent use, for long-lived
+// documents such as cached configuration that are scanned once or refreshed periodically
+// and read by many goroutines:
+//
+//	var settings jsonsql.SyncValue[Settings]
+//	err := db.QueryRowContext(ctx, "SELECT settings FROM config").Scan(&settings)
+//	...
+//	limit := settings.Load().RateLimit // from any goroutine
+//
+// Scan decodes into a new value and swaps it in, so readers see either the old or the new
+// document, never a partially decoded one. Load returns a shallow copy of the value: maps,
+// slices and pointers inside T are shared with other readers and must not be modified;
+// use Update to derive a modified value instead.
+// The zero value holds the zero value of T and is ready to use. A SyncValue must not be
+// copied after first use, so it is passed to Scan and Exec by pointer.
+type SyncValue[T any] struct {
+	mu sync.RWMutex
+	v  T
+}
+
+// NewSyncValue creates a new SyncValue[T] holding v.
+func NewSyncValue[T any](v T) *SyncValue[T] {
+	return &SyncValue[T]{v: v}
+}
+
+// Load returns the current value.
+func (s *SyncValue[T]) Load() T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.v
+}
+
+// Store replaces the value with v.
+func (s *SyncValue[T]) Store(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.v = v
+}
+
+// Update atomically replaces the value with the result of calling f with the current value
+// and returns the new value. f is called with the lock held and must not call other methods of s.
+func (s *SyncValue[T]) Update(f func(T) T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.v = f(s.v)
+	return s.v
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes like Value[T].Scan and stores the result only if decoding succeeds.
+func (s *SyncValue[T]) Scan(src any) error {
+	return s.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (s *SyncValue[T]) ScanContext(ctx context.Context, src any) error {
+	var v T
+	if cfg := configFor[T](); !cfg.resetOnScan {
+		// Decode into a deep copy, as maps and slices of the current value are shared with readers.
+		// The copy skips validation, size limits and hooks, which apply to the scanned document only.
+		data, err := encodePayload(s.Load(), cfg)
+		if err == nil {
+			_, err = decodePayload(data, &v, cfg, ErrorOnEmpty)
+		}
+		if err != nil {
+			return fmt.Errorf("jsonsql.SyncValue.Scan: %w", err)
+		}
+	}
+	null, err := decodeSourceContext(ctx, src, &v, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.SyncValue.Scan: %w", err)
+	}
+	if null {
+		return ErrNullNotAllowed
+	}
+	s.Store(v)
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// It marshals the current value to JSON bytes for database storage.
+func (s *SyncValue[T]) Value() (driver.Value, error) {
+	cfg := configFor[T]()
+	data, err := encodeValue(s.Load(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.SyncValue.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
@@ -0,0 +1,139 @@
+package jsonsql
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSyncValue(t *testing.T) {
+	var s SyncValue[testSettings]
+	if s.Load() != (testSettings{}) {
+		t.Fatalf("expected zero value, got %+v", s.Load())
+	}
+	if err := s.Scan(`{"theme":"dark","page_size":20}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.Load() != (testSettings{Theme: "dark", PageSize: 20}) {
+		t.Errorf("unexpected value %+v", s.Load())
+	}
+
+	if err := s.Scan(`{"theme":`); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := s.Scan(nil); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+	if s.Load().Theme != "dark" {
+		t.Errorf("failed scans must keep the value, got %+v", s.Load())
+	}
+
+	got := s.Update(func(v testSettings) testSettings {
+		v.PageSize++
+		return v
+	})
+	if got.PageSize != 21 || s.Load().PageSize != 21 {
+		t.Errorf("unexpected value after Update: %+v", s.Load())
+	}
+
+	result, err := s.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if string(result.([]byte)) != `{"theme":"dark","page_size":21}` {
+		t.Errorf("unexpected result %s", result)
+	}
+}
+
+func TestSyncValue_Concurrent(t *testing.T) {
+	s := NewSyncValue(testSettings{})
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				s.Update(func(v testSettings) testSettings {
+					v.PageSize++
+					return v
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if err := s.Scan(fmt.Sprintf(`{"theme":"t%d","page_size":0}`, i)); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_ = s.Load()
+				if _, err := s.Value(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSyncValue_MergeOnScan(t *testing.T) {
+	resetOptions[testSettings](t)
+	Configure[testSettings](MergeOnScan(true))
+
+	s := NewSyncValue(testSettings{PageSize: 50})
+	if err := s.Scan(`{"theme":"light"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.Load() != (testSettings{Theme: "light", PageSize: 50}) {
+		t.Errorf("expected merged value, got %+v", s.Load())
+	}
+}
+
+func TestSyncValue_MergeDoesNotShareMaps(t *testing.T) {
+	resetOptions[map[string]int](t)
+	Configure[map[string]int](MergeOnScan(true))
+
+	s := NewSyncValue(map[string]int{"a": 1})
+	before := s.Load()
+	if err := s.Scan(`{"b":2}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(before) != 1 {
+		t.Errorf("Scan modified a value returned by Load: %v", before)
+	}
+	if v := s.Load(); v["a"] != 1 || v["b"] != 2 {
+		t.Errorf("expected merged map, got %v", v)
+	}
+}
+
+type testNamed struct {
+	Name string `json:"name"`
+}
+
+func (n testNamed) Validate() error {
+	if n.Name == "" {
+		return errors.New("name required")
+	}
+	return nil
+}
+
+func TestSyncValue_MergeValidatesScannedDocumentOnly(t *testing.T) {
+	resetOptions[testNamed](t)
+	Configure[testNamed](ValidateValues(true))
+
+	var s SyncValue[testNamed]
+	if err := s.Scan(`{"name":"x"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if s.Load().Name != "x" {
+		t.Errorf("expected scanned value, got %+v", s.Load())
+	}
+	if err := s.Scan(`{"name":""}`); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation, got %v", err)
+	}
+}
@@ -0,0 +1,335 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Special layouts accepted by TimeFormat in addition to time package layouts.
+const (
+	// TimeUnix encodes time.Time as a JSON number of seconds since the Unix epoch.
+	TimeUnix = "unix"
+	// TimeUnixMilli encodes time.Time as a JSON number of milliseconds since the Unix epoch.
+	TimeUnixMilli = "unixmilli"
+)
+
+// TimeFormat encodes time.Time values inside documents using layout instead of the
+// encoding/json default (RFC 3339 with nanoseconds).
+// layout is either a time package layout such as time.RFC3339Nano, written as a JSON string,
+// or one of TimeUnix and TimeUnixMilli, written as a JSON number.
+//
+// On Scan, values in the configured representation are accepted as well as RFC 3339 strings,
+// so rows written before the option was enabled still decode.
+func TimeFormat(layout string) Option {
+	t := reflect.TypeFor[time.Time]()
+	hook := typeHook{
+		encode: func(rv reflect.Value) ([]byte, error) {
+			return encodeTime(rv.Interface().(time.Time), layout)
+		},
+		decode: func(data []byte, rv reflect.Value) error {
+			tm, err := decodeTime(data, layout)
+			if err != nil {
+				return err
+			}
+			rv.Set(reflect.ValueOf(tm))
+			return nil
+		},
+	}
+	return func(c *config) {
+		c.setHook(t, hook)
+	}
+}
+
+// encodeTime encodes tm as JSON according to the TimeFormat layout.
+func encodeTime(tm time.Time, layout string) ([]byte, error) {
+	switch layout {
+	case TimeUnix:
+		return strconv.AppendInt(nil, tm.Unix(), 10), nil
+	case TimeUnixMilli:
+		return strconv.AppendInt(nil, tm.UnixMilli(), 10), nil
+	}
+	return json.Marshal(tm.Format(layout))
+}
+
+// decodeTime decodes a JSON time in the TimeFormat layout, or an RFC 3339 string.
+func decodeTime(data []byte, layout string) (time.Time, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return time.Time{}, err
+		}
+		var layoutErr error
+		if layout != TimeUnix && layout != TimeUnixMilli {
+			tm, err := time.Parse(layout, s)
+			if err == nil {
+				return tm, nil
+			}
+			layoutErr = err
+		}
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			if layoutErr != nil && layout != time.RFC3339 {
+				return time.Time{}, fmt.Errorf("jsonsql: cannot parse %q as time in layout %q or RFC 3339: %w", s, layout, layoutErr)
+			}
+			return time.Time{}, fmt.Errorf("jsonsql: cannot parse %q as time: %w", s, err)
+		}
+		return tm, nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil || (layout != TimeUnix && layout != TimeUnixMilli) {
+		return time.Time{}, &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeFor[time.Time]()}
+	}
+	if i, err := num.Int64(); err == nil {
+		if layout == TimeUnixMilli {
+			return time.UnixMilli(i).UTC(), nil
+		}
+		return time.Unix(i, 0).UTC(), nil
+	}
+	f, err := num.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if layout == TimeUnixMilli {
+		f /= 1e3
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+}
+
+// DurationStyle selects how time.Duration values are written by DurationFormat.
+type DurationStyle int
+
+const (
+	// DurationNanos writes durations as a JSON number of nanoseconds, like encoding/json.
+	DurationNanos DurationStyle = iota
+	// DurationString writes durations as Go duration strings such as "1h30m0s".
+	DurationString
+	// DurationISO8601 writes durations as ISO 8601 strings such as "PT1H30M".
+	DurationISO8601
+)
+
+// DurationFormat encodes time.Duration values inside documents using style instead of the
+// encoding/json default of integer nanoseconds, for documents shared with other services.
+//
+// On Scan, numbers of nanoseconds, Go duration strings and ISO 8601 durations are all accepted,
+// so rows written with another style still decode. ISO 8601 durations with years or months are
+// rejected because their length varies; a day is 24 hours and a week 7 days.
+func DurationFormat(style DurationStyle) Option {
+	t := reflect.TypeFor[time.Duration]()
+	hook := typeHook{
+		encode: func(rv reflect.Value) ([]byte, error) {
+			return encodeDuration(time.Duration(rv.Int()), style), nil
+		},
+		decode: func(data []byte, rv reflect.Value) error {
+			d, err := decodeDuration(data)
+			if err != nil {
+				return err
+			}
+			rv.SetInt(int64(d))
+			return nil
+		},
+	}
+	return func(c *config) {
+		c.setHook(t, hook)
+	}
+}
+
+// encodeDuration encodes d as JSON in the DurationFormat style.
+func encodeDuration(d time.Duration, style DurationStyle) []byte {
+	switch style {
+	case DurationString:
+		return strconv.AppendQuote(nil, d.String())
+	case DurationISO8601:
+		return strconv.AppendQuote(nil, formatISODuration(d))
+	}
+	return strconv.AppendInt(nil, int64(d), 10)
+}
+
+// decodeDuration decodes a JSON duration written in any DurationStyle.
+func decodeDuration(data []byte) (time.Duration, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '"' {
+		var i int64
+		if err := json.Unmarshal(data, &i); err != nil {
+			return 0, &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeFor[time.Duration]()}
+		}
+		return time.Duration(i), nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return 0, err
+	}
+	if d, err := parseISODuration(s); err == nil {
+		return d, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("jsonsql: cannot parse %q as duration", s)
+	}
+	return d, nil
+}
+
+// formatISODuration formats d as an ISO 8601 duration of hours, minutes and seconds.
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var buf []byte
+	// Work with the magnitude as uint64 so that math.MinInt64 does not overflow.
+	u := uint64(d)
+	if d < 0 {
+		buf = append(buf, '-')
+		u = -u
+	}
+	buf = append(buf, 'P', 'T')
+	hours, u := u/uint64(time.Hour), u%uint64(time.Hour)
+	minutes, u := u/uint64(time.Minute), u%uint64(time.Minute)
+	seconds, nanos := u/uint64(time.Second), u%uint64(time.Second)
+	if hours > 0 {
+		buf = strconv.AppendUint(buf, hours, 10)
+		buf = append(buf, 'H')
+	}
+	if minutes > 0 {
+		buf = strconv.AppendUint(buf, minutes, 10)
+		buf = append(buf, 'M')
+	}
+	if seconds > 0 || nanos > 0 {
+		buf = strconv.AppendUint(buf, seconds, 10)
+		if nanos > 0 {
+			frac := fmt.Sprintf("%09d", nanos)
+			buf = append(buf, '.')
+			buf = append(buf, bytes.TrimRight([]byte(frac), "0")...)
+		}
+		buf = append(buf, 'S')
+	}
+	return string(buf)
+}
+
+// parseISODuration parses an ISO 8601 duration such as "P1DT2H30M" or "-PT0.5S".
+func parseISODuration(s string) (time.Duration, error) {
+	invalid := fmt.Errorf("jsonsql: invalid ISO 8601 duration %q", s)
+	rest := s
+	neg := false
+	if len(rest) > 0 && (rest[0] == '-' || rest[0] == '+') {
+		neg = rest[0] == '-'
+		rest = rest[1:]
+	}
+	if len(rest) < 2 || rest[0] != 'P' {
+		return 0, invalid
+	}
+	rest = rest[1:]
+
+	// Sum the magnitude in uint64 so that math.MinInt64 can be represented.
+	var total uint64
+	inTime := false
+	seen := false
+	for len(rest) > 0 {
+		if rest[0] == 'T' {
+			if inTime || len(rest) == 1 {
+				return 0, invalid
+			}
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.' || rest[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(rest) {
+			return 0, invalid
+		}
+		var unit time.Duration
+		switch {
+		case !inTime && rest[i] == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && rest[i] == 'D':
+			unit = 24 * time.Hour
+		case inTime && rest[i] == 'H':
+			unit = time.Hour
+		case inTime && rest[i] == 'M':
+			unit = time.Minute
+		case inTime && rest[i] == 'S':
+			unit = time.Second
+		default:
+			return 0, invalid
+		}
+		n, ok := durationComponent(rest[:i], uint64(unit))
+		if !ok {
+			return 0, invalid
+		}
+		var carry uint64
+		if total, carry = bits.Add64(total, n, 0); carry != 0 {
+			return 0, invalid
+		}
+		seen = true
+		rest = rest[i+1:]
+	}
+	if !seen {
+		return 0, invalid
+	}
+	// 2^63 nanoseconds only fit as the magnitude of math.MinInt64.
+	if total > 1<<63 || total == 1<<63 && !neg {
+		return 0, invalid
+	}
+	d := time.Duration(total)
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// maxFractionDigits is the number of fraction digits durationComponent takes into account;
+// later digits are below a nanosecond for every unit.
+const maxFractionDigits = 18
+
+// durationComponent returns the nanoseconds of the decimal number num, with a '.' or ','
+// before an optional fraction, times unit nanoseconds, rounded half away from zero.
+// It reports false for malformed numbers and results that do not fit in uint64.
+func durationComponent(num string, unit uint64) (uint64, bool) {
+	intPart, frac, _ := strings.Cut(strings.ReplaceAll(num, ",", "."), ".")
+	if intPart == "" && frac == "" || strings.Contains(frac, ".") {
+		return 0, false
+	}
+	var n uint64
+	if intPart != "" {
+		var err error
+		if n, err = strconv.ParseUint(intPart, 10, 64); err != nil {
+			return 0, false
+		}
+	}
+	hi, total := bits.Mul64(n, unit)
+	if hi != 0 {
+		return 0, false
+	}
+	frac = frac[:min(len(frac), maxFractionDigits)]
+	if frac == "" {
+		return total, true
+	}
+	f, err := strconv.ParseUint(frac, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	div := uint64(1)
+	for range len(frac) {
+		div *= 10
+	}
+	// f < div <= 10^18, so the high word of f*unit is below div and Div64 cannot overflow.
+	fhi, flo := bits.Mul64(f, unit)
+	q, r := bits.Div64(fhi, flo, div)
+	if 2*r >= div {
+		q++
+	}
+	var carry uint64
+	total, carry = bits.Add64(total, q, 0)
+	return total, carry == 0
+}
@@ -0,0 +1,190 @@
+package jsonsql
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Name string     `json:"name"`
+	At   time.Time  `json:"at"`
+	Seen *time.Time `json:"seen"`
+}
+
+func TestTimeFormat_Value(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{TimeUnix, `{"name":"e","at":1704164645,"seen":null}`},
+		{TimeUnixMilli, `{"name":"e","at":1704164645123,"seen":null}`},
+		{time.RFC3339Nano, `{"name":"e","at":"2024-01-02T03:04:05.123456789Z","seen":null}`},
+		{time.DateOnly, `{"name":"e","at":"2024-01-02","seen":null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			resetOptions[testEvent](t)
+			Configure[testEvent](TimeFormat(tt.layout))
+
+			result, err := NewValue(testEvent{Name: "e", At: at}).Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			if string(result.([]byte)) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTimeFormat_Scan_UnixMilli(t *testing.T) {
+	resetOptions[testEvent](t)
+	Configure[testEvent](TimeFormat(TimeUnixMilli))
+
+	var n Nullable[testEvent]
+	if err := n.Scan(`{"name":"e","at":1704164645123,"seen":1704164645000}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.UTC)
+	if !n.V.At.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, n.V.At)
+	}
+	if n.V.Seen == nil || !n.V.Seen.Equal(expected.Truncate(time.Second)) {
+		t.Errorf("unexpected seen: %v", n.V.Seen)
+	}
+}
+
+func TestTimeFormat_Scan_AcceptsRFC3339(t *testing.T) {
+	resetOptions[testEvent](t)
+	Configure[testEvent](TimeFormat(TimeUnix))
+
+	var v Value[testEvent]
+	if err := v.Scan(`{"at":"2024-01-02T03:04:05Z"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if !v.V.At.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected at: %v", v.V.At)
+	}
+}
+
+func TestTimeFormat_Scan_Layout(t *testing.T) {
+	resetOptions[testEvent](t)
+	Configure[testEvent](TimeFormat(time.DateOnly))
+
+	var v Value[testEvent]
+	if err := v.Scan(`{"at":"2024-01-02"}`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if !v.V.At.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected at: %v", v.V.At)
+	}
+}
+
+func TestTimeFormat_Scan_Invalid(t *testing.T) {
+	resetOptions[testEvent](t)
+	Configure[testEvent](TimeFormat(time.DateOnly))
+
+	var v Value[testEvent]
+	if err := v.Scan(`{"at":12345}`); err == nil {
+		t.Error("expected error for number with string layout")
+	}
+	if err := v.Scan(`{"at":"yesterday"}`); err == nil || !strings.Contains(err.Error(), `in layout "2006-01-02" or RFC 3339`) {
+		t.Errorf("expected error naming the layout, got %v", err)
+	}
+}
+
+type testTimeout struct {
+	Timeout time.Duration  `json:"timeout"`
+	Retry   *time.Duration `json:"retry,omitempty"`
+}
+
+func TestDurationFormat_Value(t *testing.T) {
+	retry := -1500 * time.Millisecond
+	v := testTimeout{Timeout: 90*time.Minute + 5*time.Second + 250*time.Millisecond, Retry: &retry}
+	tests := []struct {
+		style    DurationStyle
+		expected string
+	}{
+		{DurationNanos, `{"timeout":5405250000000,"retry":-1500000000}`},
+		{DurationString, `{"timeout":"1h30m5.25s","retry":"-1.5s"}`},
+		{DurationISO8601, `{"timeout":"PT1H30M5.25S","retry":"-PT1.5S"}`},
+	}
+	for _, tt := range tests {
+		resetOptions[testTimeout](t)
+		Configure[testTimeout](DurationFormat(tt.style))
+		result, err := NewValue(v).Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if string(result.([]byte)) != tt.expected {
+			t.Errorf("style %d: expected %s, got %s", tt.style, tt.expected, result)
+		}
+		var scanned Value[testTimeout]
+		if err := scanned.Scan(result); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if scanned.V.Timeout != v.Timeout || *scanned.V.Retry != retry {
+			t.Errorf("style %d: round trip mismatch %+v", tt.style, scanned.V)
+		}
+	}
+}
+
+func TestDurationFormat_Scan(t *testing.T) {
+	resetOptions[testTimeout](t)
+	Configure[testTimeout](DurationFormat(DurationISO8601))
+
+	tests := map[string]time.Duration{
+		`1000`:             time.Microsecond,
+		`"5m"`:             5 * time.Minute,
+		`"PT5M"`:           5 * time.Minute,
+		`"P1DT2H"`:         26 * time.Hour,
+		`"P1W"`:            7 * 24 * time.Hour,
+		`"PT0,5S"`:         500 * time.Millisecond,
+		`"-PT1H0.001S"`:    -time.Hour - time.Millisecond,
+		`"PT0S"`:           0,
+		`"PT0.000000001S"`: time.Nanosecond,
+	}
+	for in, expected := range tests {
+		var v Value[testTimeout]
+		if err := v.Scan(`{"timeout":` + in + `}`); err != nil {
+			t.Errorf("%s: Scan failed: %v", in, err)
+			continue
+		}
+		if v.V.Timeout != expected {
+			t.Errorf("%s: expected %v, got %v", in, expected, v.V.Timeout)
+		}
+	}
+
+	for _, in := range []string{`"P1Y"`, `"P1M"`, `"PT"`, `"P"`, `"PT5"`, `"T5M"`, `"soon"`, `true`, `1.5`, `"PT9223372036.854775808S"`, `"PT2562048H"`, `"PT.S"`, `"PT1.2.3S"`} {
+		var v Value[testTimeout]
+		if err := v.Scan(`{"timeout":` + in + `}`); err == nil {
+			t.Errorf("%s: expected error, got %v", in, v.V.Timeout)
+		}
+	}
+
+	if s := formatISODuration(time.Duration(math.MinInt64)); s != "-PT2562047H47M16.854775808S" {
+		t.Errorf("unexpected minimum duration %s", s)
+	}
+	if d, err := parseISODuration("-PT2562047H47M16.854775808S"); err != nil || d != math.MinInt64 {
+		t.Errorf("expected minimum duration to round trip, got %v, %v", d, err)
+	}
+	for _, d := range []time.Duration{200*24*time.Hour + 1, math.MaxInt64, -math.MaxInt64} {
+		if got, err := parseISODuration(formatISODuration(d)); err != nil || got != d {
+			t.Errorf("expected %d to round trip, got %d, %v", d, got, err)
+		}
+	}
+	if d, err := parseISODuration("P10000DT0.0000000005S"); err != nil || d != 10000*24*time.Hour+1 {
+		t.Errorf("expected the fraction to round half away from zero, got %d, %v", d, err)
+	}
+	if s := formatISODuration(0); s != "PT0S" {
+		t.Errorf("unexpected zero duration %s", s)
+	}
+}
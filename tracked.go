@@ -0,0 +1,168 @@
+package jsonsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ sql.Scanner    = (*TrackedNullable[struct{}])(nil)
+	_ ContextScanner = (*TrackedNullable[struct{}])(nil)
+	_ driver.Valuer  = TrackedNullable[struct{}]{}
+)
+
+// NullKind records which representation of "no value" a TrackedNullable was scanned from.
+type NullKind int
+
+const (
+	// SQLNull is SQL NULL. It is the zero value, so a zero TrackedNullable[T] is SQL NULL.
+	SQLNull NullKind = iota
+	// JSONNull is the JSON literal null.
+	JSONNull
+	// EmptyNull is an empty or whitespace-only []byte or string.
+	EmptyNull
+	// EmptyObjectNull is the empty JSON object {}, read as null with EmptyObjectAsNull.
+	EmptyObjectNull
+	// EmptyArrayNull is the empty JSON array [], read as null with EmptyArrayAsNull.
+	EmptyArrayNull
+)
+
+// String returns the name of k.
+func (k NullKind) String() string {
+	switch k {
+	case SQLNull:
+		return "SQL NULL"
+	case JSONNull:
+		return "JSON null"
+	case EmptyNull:
+		return "empty"
+	case EmptyObjectNull:
+		return "empty object"
+	case EmptyArrayNull:
+		return "empty array"
+	}
+	return fmt.Sprintf("NullKind(%d)", int(k))
+}
+
+// TrackedNullable[T] is like Nullable[T], but remembers whether an invalid value was scanned from
+// SQL NULL, JSON null or empty input, and writes the same representation back.
+// This keeps the distinction for auditing and for columns that are read and rewritten
+// without changing their null representation.
+// Kind is only meaningful when Valid is false.
+// T must not be a pointer type; Scan and Value fail with a *TypeParamError for TrackedNullable[*T].
+type TrackedNullable[T any] struct {
+	V     T
+	Valid bool
+	Kind  NullKind
+}
+
+// Nullable returns n as a Nullable[T], dropping Kind.
+func (n TrackedNullable[T]) Nullable() Nullable[T] {
+	return Nullable[T]{V: n.V, Valid: n.Valid}
+}
+
+// Get returns the value and a boolean indicating whether it is valid.
+func (n TrackedNullable[T]) Get() (T, bool) {
+	return n.V, n.Valid
+}
+
+// Scan implements sql.Scanner interface.
+// It decodes like Nullable[T].Scan and records the kind of null in Kind when Valid is false.
+func (n *TrackedNullable[T]) Scan(src any) error {
+	return n.ScanContext(context.Background(), src)
+}
+
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (n *TrackedNullable[T]) ScanContext(ctx context.Context, src any) error {
+	if err := checkNullableParam[T](); err != nil {
+		return fmt.Errorf("jsonsql.TrackedNullable.Scan: %w", err)
+	}
+	kind := SQLNull
+	null, err := decodeSourceWith(ctx, src, &n.V, configFor[T](), NullOnEmpty, func(src any, v *T, cfg *config, emptyDefault EmptyPolicy) (bool, error) {
+		null, err := decodePayload(src, v, cfg, emptyDefault)
+		if null {
+			kind = nullKindOf[T](src, cfg)
+		}
+		return null, err
+	})
+	if err != nil {
+		return fmt.Errorf("jsonsql.TrackedNullable.Scan: %w", err)
+	}
+	if null {
+		var zero T
+		n.V = zero
+		n.Valid = false
+		n.Kind = kind
+		return nil
+	}
+	n.Valid = true
+	n.Kind = SQLNull
+	return nil
+}
+
+// nullKindOf classifies a resolved source that decoded as null according to cfg.
+func nullKindOf[T any](src any, cfg *config) NullKind {
+	if src == nil {
+		return SQLNull
+	}
+	data, err := jsonBytes[T](src, cfg)
+	if err != nil {
+		return JSONNull
+	}
+	if cfg.documentCodec == nil {
+		if data, err = normalizeSyntax(data, cfg); err != nil {
+			return JSONNull
+		}
+	}
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+		return EmptyNull
+	case cfg.documentCodec != nil || isJSONNull(data):
+		return JSONNull
+	case data[0] == '{':
+		return EmptyObjectNull
+	case data[0] == '[':
+		return EmptyArrayNull
+	}
+	return JSONNull
+}
+
+// Value implements driver.Valuer interface.
+// When Valid is false it returns nil for SQLNull, the JSON literal null for JSONNull,
+// an empty document for EmptyNull and {} or [] for EmptyObjectNull and EmptyArrayNull.
+// Otherwise marshals V to JSON bytes.
+func (n TrackedNullable[T]) Value() (driver.Value, error) {
+	if err := checkNullableParam[T](); err != nil {
+		return nil, fmt.Errorf("jsonsql.TrackedNullable.Value: %w", err)
+	}
+	cfg := configFor[T]()
+	if !n.Valid {
+		var data []byte
+		switch n.Kind {
+		case JSONNull:
+			data = []byte("null")
+		case EmptyNull:
+			data = []byte{}
+		case EmptyObjectNull:
+			data = []byte("{}")
+		case EmptyArrayNull:
+			data = []byte("[]")
+		}
+		reportNull[T](cfg, data)
+		if data == nil {
+			return nil, nil
+		}
+		return cfg.driverValue(data), nil
+	}
+	data, err := encodeValue(n.V, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.TrackedNullable.Value: %w", err)
+	}
+	return cfg.driverValue(data), nil
+}
@@ -0,0 +1,133 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestTrackedNullable_Scan(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   any
+		valid bool
+		kind  NullKind
+		value any
+	}{
+		{"sql null", nil, false, SQLNull, nil},
+		{"json null bytes", []byte("null"), false, JSONNull, []byte("null")},
+		{"json null string", " null ", false, JSONNull, []byte("null")},
+		{"empty string", "", false, EmptyNull, []byte{}},
+		{"blank bytes", []byte("  "), false, EmptyNull, []byte{}},
+		{"valid", `{"name":"Ann"}`, true, SQLNull, []byte(`{"name":"Ann","email":""}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := TrackedNullable[testProfile]{V: testProfile{Name: "stale"}, Valid: true, Kind: JSONNull}
+			if err := n.Scan(tt.src); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if n.Valid != tt.valid || n.Kind != tt.kind {
+				t.Fatalf("expected Valid=%v Kind=%v, got Valid=%v Kind=%v", tt.valid, tt.kind, n.Valid, n.Kind)
+			}
+			if !tt.valid && n.V != (testProfile{}) {
+				t.Errorf("expected zero value, got %+v", n.V)
+			}
+
+			result, err := n.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			if tt.value == nil {
+				if result != nil {
+					t.Errorf("expected nil, got %v", result)
+				}
+				return
+			}
+			if result == nil || !bytes.Equal(result.([]byte), tt.value.([]byte)) {
+				t.Errorf("expected %q, got %v", tt.value, result)
+			}
+		})
+	}
+}
+
+func TestTrackedNullable_ZeroIsSQLNull(t *testing.T) {
+	result, err := TrackedNullable[testProfile]{}.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected nil, got %v", result)
+	}
+	if n := (TrackedNullable[testProfile]{Valid: true}).Nullable(); !n.Valid {
+		t.Errorf("expected valid Nullable, got %+v", n)
+	}
+}
+
+func TestTrackedNullable_RejectsPointer(t *testing.T) {
+	var n TrackedNullable[*testProfile]
+	if err := n.Scan(`{}`); err == nil {
+		t.Error("expected error for TrackedNullable[*T]")
+	}
+}
+
+func TestTrackedNullable_EmptyDocumentAsNull(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](EmptyObjectAsNull(true), EmptyArrayAsNull(true))
+
+	tests := []struct {
+		src   any
+		kind  NullKind
+		value string
+	}{
+		{`{}`, EmptyObjectNull, `{}`},
+		{[]byte(" [ ] "), EmptyArrayNull, `[]`},
+		{"\ufeff", EmptyNull, ``},
+		{"\ufeffnull", JSONNull, `null`},
+	}
+	for _, tt := range tests {
+		var n TrackedNullable[testProfile]
+		if err := n.Scan(tt.src); err != nil {
+			t.Fatalf("Scan(%q) failed: %v", tt.src, err)
+		}
+		if n.Valid || n.Kind != tt.kind {
+			t.Errorf("Scan(%q): expected Kind=%v, got Valid=%v Kind=%v", tt.src, tt.kind, n.Valid, n.Kind)
+		}
+		result, err := n.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if string(result.([]byte)) != tt.value {
+			t.Errorf("Scan(%q): expected %q to be written back, got %q", tt.src, tt.value, result)
+		}
+	}
+}
+
+func TestTrackedNullable_ValueAsRawMessage(t *testing.T) {
+	resetOptions[testProfile](t)
+	Configure[testProfile](ValueAsRawMessage(true))
+
+	for _, kind := range []NullKind{JSONNull, EmptyNull, EmptyObjectNull, EmptyArrayNull} {
+		result, err := TrackedNullable[testProfile]{Kind: kind}.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if _, ok := result.(json.RawMessage); !ok {
+			t.Errorf("%v: expected json.RawMessage, got %T", kind, result)
+		}
+	}
+}
+
+func TestTrackedNullable_ReportsResolveErrors(t *testing.T) {
+	resetOptions[testProfile](t)
+
+	var errs []error
+	Configure[testProfile](Observe(Hooks{OnError: func(e HookEvent) { errs = append(errs, e.Err) }}))
+	var n TrackedNullable[testProfile]
+	if err := n.Scan(testFailingValuer{}); err == nil {
+		t.Fatal("expected error for failing driver.Valuer")
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected 1 reported error, got %v", errs)
+	}
+}
@@ -0,0 +1,77 @@
+package jsonsql
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NormalizeUUIDs makes the string-based UUID type U canonical wherever it appears inside
+// a document, for tables written by several services that format UUIDs differently.
+// Scan accepts UUIDs with or without dashes, in any case, in braces or with a "urn:uuid:"
+// prefix, and Value writes them in the canonical lowercase 8-4-4-4-12 form:
+//
+//	type UserID string
+//	jsonsql.SetDefaults(jsonsql.NormalizeUUIDs[UserID]())
+//
+// Both fail for values that are not UUIDs; the empty string is left as is.
+// UUID types based on [16]byte, such as github.com/google/uuid.UUID, already parse all of
+// these forms and write the canonical form.
+func NormalizeUUIDs[U ~string]() Option {
+	t := reflect.TypeFor[U]()
+	hook := typeHook{
+		encode: func(rv reflect.Value) ([]byte, error) {
+			s, err := canonicalUUID(rv.String())
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(s)
+		},
+		decode: func(data []byte, rv reflect.Value) error {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return &json.UnmarshalTypeError{Value: jsonKind(data), Type: t}
+			}
+			s, err := canonicalUUID(s)
+			if err != nil {
+				return err
+			}
+			rv.SetString(s)
+			return nil
+		},
+	}
+	return func(c *config) {
+		c.setHook(t, hook)
+	}
+}
+
+// canonicalUUID returns s in the canonical lowercase 8-4-4-4-12 form.
+func canonicalUUID(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	text := strings.TrimSpace(s)
+	if len(text) > 9 && strings.EqualFold(text[:9], "urn:uuid:") {
+		text = text[9:]
+	}
+	if len(text) > 2 && text[0] == '{' && text[len(text)-1] == '}' {
+		text = text[1 : len(text)-1]
+	}
+	if len(text) == 36 {
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return "", fmt.Errorf("jsonsql: invalid UUID %q", s)
+		}
+		text = text[0:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+	}
+	var b [16]byte
+	if len(text) != 32 {
+		return "", fmt.Errorf("jsonsql: invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(b[:], []byte(text)); err != nil {
+		return "", fmt.Errorf("jsonsql: invalid UUID %q", s)
+	}
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
@@ -0,0 +1,58 @@
+package jsonsql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testUUID string
+
+type testUUIDOrder struct {
+	ID       testUUID            `json:"id"`
+	Customer *testUUID           `json:"customer,omitempty"`
+	Related  []testUUID          `json:"related"`
+	ByID     map[string]testUUID `json:"by_id"`
+}
+
+func TestNormalizeUUIDs(t *testing.T) {
+	resetOptions[testUUIDOrder](t)
+	Configure[testUUIDOrder](NormalizeUUIDs[testUUID]())
+
+	const canonical = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	var v Value[testUUIDOrder]
+	err := v.Scan(`{"id":"6BA7B810-9DAD-11D1-80B4-00C04FD430C8","customer":"6ba7b8109dad11d180b400c04fd430c8",` +
+		`"related":["{6ba7b810-9dad-11d1-80b4-00c04fd430c8}","urn:uuid:6BA7B810-9dad-11d1-80b4-00c04fd430c8",""],` +
+		`"by_id":{"a":"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}}`)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	expected := testUUIDOrder{
+		ID:      canonical,
+		Related: []testUUID{canonical, canonical, ""},
+		ByID:    map[string]testUUID{"a": canonical},
+	}
+	c := testUUID(canonical)
+	expected.Customer = &c
+	if !reflect.DeepEqual(v.V, expected) {
+		t.Errorf("expected %+v, got %+v", expected, v.V)
+	}
+
+	result, err := NewValue(testUUIDOrder{ID: "6BA7B8109DAD11D180B400C04FD430C8"}).Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if !strings.HasPrefix(string(result.([]byte)), `{"id":"`+canonical+`"`) {
+		t.Errorf("unexpected document %s", result)
+	}
+
+	for _, bad := range []string{`{"id":"not-a-uuid"}`, `{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430cz"}`,
+		`{"id":"6ba7b810x9dad-11d1-80b4-00c04fd430c8"}`, `{"id":42}`} {
+		if err := v.Scan(bad); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	if _, err := NewValue(testUUIDOrder{ID: "123"}).Value(); err == nil {
+		t.Error("expected error for invalid UUID on Value")
+	}
+}
@@ -1,22 +1,21 @@
 package jsonsql
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
-	"encoding/json"
-	"errors"
 	"fmt"
 )
 
 // Compile-time interface satisfaction checks
 var (
-	_ sql.Scanner   = (*Value[struct{}])(nil)
-	_ driver.Valuer = Value[struct{}]{}
+	_ sql.Scanner    = (*Value[struct{}])(nil)
+	_ ContextScanner = (*Value[struct{}])(nil)
+	_ driver.Valuer  = Value[struct{}]{}
 )
 
 // ErrNullNotAllowed is returned when Scan receives nil for Value[T] (NOT NULL).
-var ErrNullNotAllowed = errors.New("jsonsql: null value not allowed for NOT NULL field")
+var ErrNullNotAllowed = permanent("jsonsql: null value not allowed for NOT NULL field")
 
 // Value[T] is a generic type for NOT NULL JSON columns.
 // It wraps any type T and provides Scan/Value methods for database/sql compatibility.
@@ -34,43 +33,44 @@ func (v Value[T]) Get() T {
 	return v.V
 }
 
+// Set replaces the value with x.
+func (v *Value[T]) Set(x T) {
+	v.V = x
+}
+
+// Replace replaces the value with the result of calling f with the current value.
+func (v *Value[T]) Replace(f func(T) T) {
+	v.V = f(v.V)
+}
+
 // Scan implements sql.Scanner interface.
 // It unmarshals JSON data from the database into V.
-// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation).
+// Returns ErrNullNotAllowed if src is nil or JSON literal "null" (NOT NULL constraint violation),
+// and ErrEmptyInput for empty input unless another EmptyPolicy is configured.
 func (v *Value[T]) Scan(src any) error {
-	if src == nil {
-		return ErrNullNotAllowed
-	}
+	return v.ScanContext(context.Background(), src)
+}
 
-	var data []byte
-	switch s := src.(type) {
-	case []byte:
-		data = s
-	case string:
-		data = []byte(s)
-	case json.RawMessage:
-		data = s
-	default:
-		return fmt.Errorf("jsonsql.Value.Scan: unsupported type %T", src)
+// ScanContext implements ContextScanner interface.
+// It behaves like Scan, failing with the context error if ctx is already done.
+func (v *Value[T]) ScanContext(ctx context.Context, src any) error {
+	null, err := decodeSourceContext(ctx, src, &v.V, ErrorOnEmpty)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Value.Scan: %w", err)
 	}
-
-	// JSON literal null (with optional whitespace) is not allowed for NOT NULL field
-	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+	if null {
 		return ErrNullNotAllowed
 	}
-
-	if err := json.Unmarshal(data, &v.V); err != nil {
-		return fmt.Errorf("jsonsql.Value.Scan: %w", err)
-	}
 	return nil
 }
 
 // Value implements driver.Valuer interface.
 // It marshals V to JSON bytes for database storage.
 func (v Value[T]) Value() (driver.Value, error) {
-	data, err := json.Marshal(v.V)
+	cfg := configFor[T]()
+	data, err := encodeValue(v.V, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("jsonsql.Value.Value: %w", err)
 	}
-	return data, nil
+	return cfg.driverValue(data), nil
 }
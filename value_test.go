@@ -332,3 +332,20 @@ func TestValue_Get(t *testing.T) {
 		t.Errorf("expected Email=alice@example.com, got %s", got.Email)
 	}
 }
+
+func TestValue_SetReplace(t *testing.T) {
+	var v Value[testProfile]
+
+	v.Set(testProfile{Name: "Alice"})
+	if v.V.Name != "Alice" {
+		t.Errorf("expected Name=Alice, got %s", v.V.Name)
+	}
+
+	v.Replace(func(p testProfile) testProfile {
+		p.Email = "alice@example.com"
+		return p
+	})
+	if v.V.Name != "Alice" || v.V.Email != "alice@example.com" {
+		t.Errorf("unexpected result: %+v", v.V)
+	}
+}
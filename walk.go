@@ -0,0 +1,621 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// typeHook is a custom JSON encoder/decoder for values of one Go type inside a document.
+type typeHook struct {
+	// encode returns the JSON encoding of rv.
+	encode func(rv reflect.Value) ([]byte, error)
+	// decode decodes the JSON value data into rv, which is always settable.
+	decode func(data []byte, rv reflect.Value) error
+}
+
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// walks reports whether cfg requires the reflective walker instead of plain encoding/json.
+func (c *config) walks() bool {
+	return len(c.hooks) > 0 || len(c.aliases) > 0 || len(c.fieldHooks) > 0 || c.collectErrors || c.persistPolicy != PersistAll
+}
+
+// needsWalk reports whether values of type t contain anything the walker must handle itself.
+// Types that do not are encoded and decoded directly with encoding/json.
+func (c *config) needsWalk(t reflect.Type) bool {
+	if v, ok := c.walkCache.Load(t); ok {
+		return v.(bool)
+	}
+	visiting := map[reflect.Type]bool{}
+	needs := c.needsWalkIn(t, visiting)
+	if !needs {
+		// Nothing reachable from t needs the walker, including the types visited on the way.
+		for v := range visiting {
+			c.walkCache.Store(v, false)
+		}
+	}
+	return needs
+}
+
+// needsWalkIn is needsWalk for a type reached from the types in visiting, whose results are
+// not known yet. A type already in visiting is reported as not needing the walker so recursive
+// types terminate; such results are provisional and only cached once the outermost type is done.
+func (c *config) needsWalkIn(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if v, ok := c.walkCache.Load(t); ok {
+		return v.(bool)
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	needs := c.computeNeedsWalk(t, visiting)
+	if needs {
+		c.walkCache.Store(t, true)
+	}
+	return needs
+}
+
+// computeNeedsWalk reports whether t itself or a type reachable from it needs the walker; it is
+// uncached and only called by needsWalkIn, which caches the result.
+func (c *config) computeNeedsWalk(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if _, ok := c.hooks[t]; ok {
+		return true
+	}
+	if _, ok := c.aliases[t]; ok {
+		return true
+	}
+	if implementsJSON(t) {
+		return false
+	}
+	if t.Kind() == reflect.Struct && c.filtersFields(t) {
+		return true
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !implementsJSON(t.Elem()) {
+		// []byte is encoded as a base64 string.
+		return false
+	}
+	if c.collectErrors {
+		// Every composite is walked so errors inside it are collected individually.
+		switch t.Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+			return true
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return c.needsWalkIn(t.Elem(), visiting)
+	case reflect.Map:
+		return c.needsWalkIn(t.Elem(), visiting)
+	case reflect.Struct:
+		for _, f := range typeFields(t) {
+			if _, ok := c.fieldHookFor(f); ok || c.needsWalkIn(f.typ, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// implementsJSON reports whether t (or *t) controls its own JSON encoding.
+func implementsJSON(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface {
+		return false
+	}
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) ||
+		pt.Implements(jsonUnmarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textMarshalerType) ||
+		pt.Implements(textUnmarshalerType)
+}
+
+// walker encodes and decodes values reflectively, delegating to encoding/json
+// for every subtree that does not need special handling.
+type walker struct {
+	cfg *config
+
+	// path is the JSON Pointer reference tokens of the value being decoded.
+	path []string
+	// errs collects field errors when cfg.collectErrors is set.
+	errs []error
+}
+
+// fail handles a decode error at the current path, returning it as a *FieldError below the root.
+// When errors are collected it records the error and returns nil so decoding continues with
+// the next value.
+func (w *walker) fail(err error) error {
+	fieldErr := &FieldError{Path: w.pointer(), Err: err}
+	if !w.cfg.collectErrors {
+		if len(w.path) == 0 {
+			return err
+		}
+		return fieldErr
+	}
+	w.errs = append(w.errs, fieldErr)
+	return nil
+}
+
+// failIn is like fail for an error decoding data with encoding/json, locating the failing
+// value inside data.
+func (w *walker) failIn(data []byte, err error) error {
+	offset, ok := errorOffset(err)
+	if !ok {
+		return w.fail(err)
+	}
+	n := len(w.path)
+	w.path = append(w.path, tokensAt(data, int(offset)-1)...)
+	err = w.fail(err)
+	w.path = w.path[:n]
+	return err
+}
+
+// decodeAt decodes data into rv with token appended to the current path.
+func (w *walker) decodeAt(token string, data []byte, rv reflect.Value) error {
+	w.path = append(w.path, token)
+	err := w.decode(data, rv)
+	w.path = w.path[:len(w.path)-1]
+	return err
+}
+
+// pointer returns the current path as a JSON Pointer (RFC 6901).
+func (w *walker) pointer() string {
+	return jsonPointer(w.path)
+}
+
+// jsonPointer returns the JSON Pointer (RFC 6901) made of the reference tokens path.
+func jsonPointer(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// decode decodes the JSON value data into rv, which must be settable.
+func (w *walker) decode(data []byte, rv reflect.Value) error {
+	t := rv.Type()
+	if h, ok := w.cfg.hooks[t]; ok {
+		if isJSONNull(data) {
+			return nil
+		}
+		if err := h.decode(data, rv); err != nil {
+			return w.fail(err)
+		}
+		return nil
+	}
+	if !w.cfg.needsWalk(t) {
+		if err := decodeLeaf(data, rv, w.cfg); err != nil {
+			return w.failIn(data, err)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if isJSONNull(data) {
+			rv.SetZero()
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return w.decode(data, rv.Elem())
+
+	case reflect.Struct:
+		if isJSONNull(data) {
+			return nil
+		}
+		var obj map[string]json.RawMessage
+		if err := decodeComposite(data, '{', &obj, t); err != nil {
+			return w.fail(err)
+		}
+		aliases := w.cfg.aliases[t]
+		for _, f := range typeFields(t) {
+			raw, ok := lookupKey(obj, f.name)
+			for _, alias := range aliases[f.name] {
+				if ok {
+					break
+				}
+				raw, ok = lookupKey(obj, alias)
+			}
+			if !ok {
+				continue
+			}
+			fv, ok := fieldByIndex(rv, f.index, true)
+			if !ok {
+				continue
+			}
+			if err := w.decodeField(raw, fv, f); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if isJSONNull(data) {
+			rv.SetZero()
+			return nil
+		}
+		var arr []json.RawMessage
+		if err := decodeComposite(data, '[', &arr, t); err != nil {
+			return w.fail(err)
+		}
+		s := reflect.MakeSlice(t, len(arr), len(arr))
+		for i, raw := range arr {
+			if err := w.decodeAt(strconv.Itoa(i), raw, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+		return nil
+
+	case reflect.Array:
+		if isJSONNull(data) {
+			return nil
+		}
+		var arr []json.RawMessage
+		if err := decodeComposite(data, '[', &arr, t); err != nil {
+			return w.fail(err)
+		}
+		for i := range rv.Len() {
+			if i >= len(arr) {
+				rv.Index(i).SetZero()
+				continue
+			}
+			if err := w.decodeAt(strconv.Itoa(i), arr[i], rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if isJSONNull(data) {
+			rv.SetZero()
+			return nil
+		}
+		var obj map[string]json.RawMessage
+		if err := decodeComposite(data, '{', &obj, t); err != nil {
+			return w.fail(err)
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(t, len(obj)))
+		}
+		for _, k := range slices.Sorted(maps.Keys(obj)) {
+			key := reflect.New(t.Key()).Elem()
+			if err := decodeMapKey(k, key); err != nil {
+				w.path = append(w.path, k)
+				err = w.fail(err)
+				w.path = w.path[:len(w.path)-1]
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if err := w.decodeAt(k, obj[k], elem); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, elem)
+		}
+		return nil
+	}
+
+	if err := decodeLeaf(data, rv, w.cfg); err != nil {
+		return w.failIn(data, err)
+	}
+	return nil
+}
+
+// decodeField decodes a struct field value, honoring field hooks and the ",string" tag option.
+func (w *walker) decodeField(data []byte, fv reflect.Value, f field) error {
+	w.path = append(w.path, f.name)
+	defer func() { w.path = w.path[:len(w.path)-1] }()
+
+	if h, ok := w.cfg.fieldHookFor(f); ok {
+		if err := h.decodeField(data, fv); err != nil {
+			return w.fail(err)
+		}
+		return nil
+	}
+	if f.quoted && !isJSONNull(data) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return w.fail(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %s into %v", data, f.typ))
+		}
+		data = []byte(s)
+	}
+	return w.decode(data, fv)
+}
+
+// encode appends the JSON encoding of rv to buf.
+func (w *walker) encode(buf *bytes.Buffer, rv reflect.Value) error {
+	t := rv.Type()
+	if h, ok := w.cfg.hooks[t]; ok {
+		data, err := h.encode(rv)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		return nil
+	}
+	if !w.cfg.needsWalk(t) {
+		return encodeLeaf(buf, rv, w.cfg)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return w.encode(buf, rv.Elem())
+
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		for _, f := range typeFields(t) {
+			if !w.cfg.persists(f) {
+				continue
+			}
+			fv, ok := fieldByIndex(rv, f.index, false)
+			if !ok || omitField(fv, f) {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			w.writeString(buf, f.name)
+			buf.WriteByte(':')
+			if err := w.encodeField(buf, fv, f); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := range rv.Len() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := w.encode(buf, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+
+	case reflect.Map:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		type entry struct {
+			key string
+			val reflect.Value
+		}
+		entries := make([]entry, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := encodeMapKey(iter.Key())
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{key: key, val: iter.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return strings.Compare(a.key, b.key)
+		})
+		buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			w.writeString(buf, e.key)
+			buf.WriteByte(':')
+			if err := w.encode(buf, e.val); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	}
+
+	return encodeLeaf(buf, rv, w.cfg)
+}
+
+// encodeField appends the JSON encoding of a struct field value, honoring field hooks and the
+// ",string" tag option.
+func (w *walker) encodeField(buf *bytes.Buffer, fv reflect.Value, f field) error {
+	if h, ok := w.cfg.fieldHookFor(f); ok {
+		return h.encodeField(buf, fv)
+	}
+	if !f.quoted {
+		return w.encode(buf, fv)
+	}
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	var inner bytes.Buffer
+	if err := w.encode(&inner, fv); err != nil {
+		return err
+	}
+	w.writeString(buf, inner.String())
+	return nil
+}
+
+// decodeLeaf decodes data into rv with encoding/json.
+func decodeLeaf(data []byte, rv reflect.Value, cfg *config) error {
+	if !rv.CanAddr() {
+		tmp := reflect.New(rv.Type())
+		if err := decodeLeaf(data, tmp.Elem(), cfg); err != nil {
+			return err
+		}
+		rv.Set(tmp.Elem())
+		return nil
+	}
+	return decodeJSON(data, rv.Addr().Interface(), cfg)
+}
+
+// encodeLeaf appends the encoding/json encoding of rv to buf.
+func encodeLeaf(buf *bytes.Buffer, rv reflect.Value, cfg *config) error {
+	data, err := encodeJSON(rv.Interface(), cfg)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// decodeComposite decodes data into dst after checking that it starts with the expected delimiter,
+// so type mismatches are reported against the target type t.
+func decodeComposite(data []byte, delim byte, dst any, t reflect.Type) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != delim {
+		if !json.Valid(data) {
+			return json.Unmarshal(data, dst)
+		}
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: t}
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// jsonKind describes the kind of the JSON value data as used in json.UnmarshalTypeError.
+func jsonKind(data []byte) string {
+	if len(data) == 0 {
+		return "value"
+	}
+	switch data[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// lookupKey finds the value for a struct field name in obj,
+// preferring an exact match and falling back to a case-insensitive one like encoding/json.
+func lookupKey(obj map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if raw, ok := obj[name]; ok {
+		return raw, true
+	}
+	for k, raw := range obj {
+		if bytes.EqualFold([]byte(k), []byte(name)) {
+			return raw, true
+		}
+	}
+	return nil, false
+}
+
+// decodeMapKey decodes an object key into key using the encoding/json key rules.
+func decodeMapKey(k string, key reflect.Value) error {
+	if key.Kind() == reflect.String && !reflect.PointerTo(key.Type()).Implements(textUnmarshalerType) {
+		key.SetString(k)
+		return nil
+	}
+	quoted, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	tmp := reflect.New(reflect.MapOf(key.Type(), reflect.TypeFor[struct{}]()))
+	if err := json.Unmarshal([]byte("{"+string(quoted)+":{}}"), tmp.Interface()); err != nil {
+		return err
+	}
+	iter := tmp.Elem().MapRange()
+	for iter.Next() {
+		key.Set(iter.Key())
+	}
+	return nil
+}
+
+// encodeMapKey returns the object key for a map key using the encoding/json key rules.
+func encodeMapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String && !k.Type().Implements(textMarshalerType) {
+		return k.String(), nil
+	}
+	if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+// omitField reports whether a struct field is skipped by its omitempty/omitzero options.
+func omitField(fv reflect.Value, f field) bool {
+	if f.omitEmpty && isEmptyValue(fv) {
+		return true
+	}
+	if f.omitZero {
+		if z, ok := fv.Interface().(interface{ IsZero() bool }); ok {
+			if fv.Kind() == reflect.Pointer && fv.IsNil() {
+				return true
+			}
+			return z.IsZero()
+		}
+		return fv.IsZero()
+	}
+	return false
+}
+
+// isEmptyValue reports whether v is empty in the sense of the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// writeString appends s to buf as a JSON string.
+func (w *walker) writeString(buf *bytes.Buffer, s string) {
+	data, _ := encodeJSON(s, w.cfg)
+	buf.Write(data)
+}
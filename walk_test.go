@@ -0,0 +1,200 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testMarker is hooked with an encoding identical to encoding/json,
+// so documents containing it go through the walker without changing the output.
+type testMarker struct {
+	N int `json:"n"`
+}
+
+func newWalkTestConfig() *config {
+	cfg := &config{}
+	cfg.setHook(reflect.TypeFor[testMarker](), typeHook{
+		encode: func(rv reflect.Value) ([]byte, error) {
+			return json.Marshal(rv.Interface())
+		},
+		decode: func(data []byte, rv reflect.Value) error {
+			return json.Unmarshal(data, rv.Addr().Interface())
+		},
+	})
+	return cfg
+}
+
+type testWalkEmbedded struct {
+	Shared   string `json:"shared"`
+	Promoted int
+}
+
+type testWalkDoc struct {
+	testWalkEmbedded
+	*testWalkPtrEmbedded
+	Name      string                `json:"name"`
+	Shared    string                `json:"shared"`
+	Skip      string                `json:"-"`
+	Empty     string                `json:"empty,omitempty"`
+	Zero      time.Time             `json:"zero,omitzero"`
+	Quoted    int64                 `json:"quoted,string"`
+	Marker    testMarker            `json:"marker"`
+	MarkerPtr *testMarker           `json:"marker_ptr"`
+	Markers   []testMarker          `json:"markers"`
+	Fixed     [2]testMarker         `json:"fixed"`
+	ByName    map[string]testMarker `json:"by_name"`
+	ByID      map[int]testMarker    `json:"by_id"`
+	Any       any                   `json:"any"`
+	Raw       json.RawMessage       `json:"raw"`
+	When      time.Time             `json:"when"`
+	unexport  int
+}
+
+type testWalkPtrEmbedded struct {
+	Deep string `json:"deep"`
+}
+
+func TestWalker_EncodeMatchesEncodingJSON(t *testing.T) {
+	doc := testWalkDoc{
+		testWalkEmbedded:    testWalkEmbedded{Shared: "hidden", Promoted: 7},
+		testWalkPtrEmbedded: &testWalkPtrEmbedded{Deep: "deep"},
+		Name:                "<name>",
+		Shared:              "visible",
+		Skip:                "skip",
+		Quoted:              42,
+		Marker:              testMarker{N: 1},
+		MarkerPtr:           &testMarker{N: 2},
+		Markers:             []testMarker{{N: 3}, {N: 4}},
+		Fixed:               [2]testMarker{{N: 5}},
+		ByName:              map[string]testMarker{"b": {N: 6}, "a": {N: 7}},
+		ByID:                map[int]testMarker{10: {N: 8}, 2: {N: 9}},
+		Any:                 map[string]any{"x": 1.5},
+		Raw:                 json.RawMessage(`{"r":true}`),
+		When:                time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		unexport:            1,
+	}
+
+	expected, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := &walker{cfg: newWalkTestConfig()}
+	if err := w.encode(&buf, reflect.ValueOf(doc)); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	if buf.String() != string(expected) {
+		t.Errorf("walker output differs\nexpected: %s\ngot:      %s", expected, buf.String())
+	}
+}
+
+func TestWalker_DecodeMatchesEncodingJSON(t *testing.T) {
+	input := []byte(`{
+		"Promoted": 7,
+		"shared": "visible",
+		"NAME": "case-insensitive",
+		"-": "skip",
+		"quoted": "42",
+		"marker": {"n": 1},
+		"marker_ptr": {"n": 2},
+		"markers": [{"n": 3}, {"n": 4}],
+		"fixed": [{"n": 5}],
+		"by_name": {"a": {"n": 7}},
+		"by_id": {"10": {"n": 8}},
+		"any": {"x": 1.5},
+		"raw": {"r": true},
+		"when": "2024-01-02T03:04:05Z",
+		"unknown": 1
+	}`)
+
+	var expected testWalkDoc
+	if err := json.Unmarshal(input, &expected); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+
+	var got testWalkDoc
+	w := &walker{cfg: newWalkTestConfig()}
+	if err := w.decode(input, reflect.ValueOf(&got).Elem()); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	// RawMessage keeps the original formatting, compare it semantically.
+	expected.Raw, got.Raw = nil, nil
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("walker result differs\nexpected: %+v\ngot:      %+v", expected, got)
+	}
+}
+
+func TestWalker_DecodeTypeMismatch(t *testing.T) {
+	var got testWalkDoc
+	w := &walker{cfg: newWalkTestConfig()}
+
+	err := w.decode([]byte(`{"markers":{"n":1}}`), reflect.ValueOf(&got).Elem())
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected *json.UnmarshalTypeError, got %v", err)
+	}
+	if typeErr.Value != "object" {
+		t.Errorf("expected Value=object, got %s", typeErr.Value)
+	}
+}
+
+func TestWalker_DecodeNull(t *testing.T) {
+	got := testWalkDoc{
+		MarkerPtr: &testMarker{N: 1},
+		Markers:   []testMarker{{N: 1}},
+		Marker:    testMarker{N: 1},
+	}
+	w := &walker{cfg: newWalkTestConfig()}
+
+	err := w.decode([]byte(`{"marker_ptr":null,"markers":null,"marker":null}`), reflect.ValueOf(&got).Elem())
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	if got.MarkerPtr != nil || got.Markers != nil {
+		t.Errorf("expected nil pointer and slice, got %+v", got)
+	}
+	if got.Marker.N != 1 {
+		t.Errorf("expected null to leave struct unchanged, got %+v", got.Marker)
+	}
+}
+
+type testTreeNode struct {
+	Children []testTreeNode `json:"children"`
+	Val      *big.Int       `json:"val"`
+}
+
+func TestNeedsWalk_RecursiveType(t *testing.T) {
+	resetOptions[testTreeNode](t)
+	Configure[testTreeNode](BigNumbersAsStrings())
+
+	node := testTreeNode{Val: big.NewInt(1), Children: []testTreeNode{{Val: big.NewInt(2)}}}
+	data, err := ValueJSON(node)
+	if err != nil {
+		t.Fatalf("ValueJSON failed: %v", err)
+	}
+	if string(data.([]byte)) != `{"children":[{"children":null,"val":"2"}],"val":"1"}` {
+		t.Errorf("unexpected document %s", data)
+	}
+
+	cfg := configFor[testTreeNode]()
+	for _, typ := range []reflect.Type{reflect.TypeFor[[]testTreeNode](), reflect.TypeFor[testTreeNode]()} {
+		if !cfg.needsWalk(typ) {
+			t.Errorf("expected %v to need the walker", typ)
+		}
+	}
+
+	cfg = &config{}
+	if cfg.needsWalk(reflect.TypeFor[testTreeNode]()) || cfg.needsWalk(reflect.TypeFor[[]testTreeNode]()) {
+		t.Error("expected recursive type without hooks not to need the walker")
+	}
+}
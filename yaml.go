@@ -0,0 +1,149 @@
+package jsonsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// The YAML methods below follow the gopkg.in/yaml.v3 Marshaler interface and the function-based
+// Unmarshaler form that yaml.v3 (as well as yaml.v2 and sigs.k8s.io/yaml) still accepts,
+// so configuration structs containing wrappers can be dumped to and loaded from YAML files
+// without this package depending on a YAML library.
+//
+// Documents go through JSON in both directions, so json struct tags and all options apply
+// exactly as they do for the database.
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (v Value[T]) MarshalYAML() (any, error) {
+	out, err := yamlValue(v.V, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Value.MarshalYAML: %w", err)
+	}
+	return out, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface (function form).
+// It decodes the YAML node with the same rules as Scan.
+func (v *Value[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	data, err := yamlJSON(unmarshal)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Value.UnmarshalYAML: %w", err)
+	}
+	return v.Scan(data)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// It returns nil (YAML null) when Valid is false.
+func (n Nullable[T]) MarshalYAML() (any, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	out, err := yamlValue(n.V, configFor[T]())
+	if err != nil {
+		return nil, fmt.Errorf("jsonsql.Nullable.MarshalYAML: %w", err)
+	}
+	return out, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface (function form).
+// It decodes the YAML node with the same rules as Scan.
+func (n *Nullable[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	data, err := yamlJSON(unmarshal)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Nullable.UnmarshalYAML: %w", err)
+	}
+	return n.Scan(data)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// Like String, it always returns the redaction placeholder.
+func (s Sensitive[T]) MarshalYAML() (any, error) {
+	return sensitivePlaceholder, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface (function form).
+// It decodes the YAML node with the same rules as Scan, so secrets can be loaded from config files.
+func (s *Sensitive[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	data, err := yamlJSON(unmarshal)
+	if err != nil {
+		return fmt.Errorf("jsonsql.Sensitive.UnmarshalYAML: %w", err)
+	}
+	return s.Scan(data)
+}
+
+// yamlValue encodes v as JSON according to cfg and decodes it back into generic values
+// (maps, slices, strings, bools and numbers) that YAML libraries can encode.
+// Integers are returned as int64 or uint64 when they fit, so they are not written as floats.
+func yamlValue(v any, cfg *config) (any, error) {
+	data, err := marshal(v, cfg)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return yamlNumbers(out), nil
+}
+
+// yamlNumbers replaces, in place, the json.Number values in v with int64, uint64 or float64,
+// trying them in that order, because YAML encoders write json.Number as a string.
+// A number that fits none of them is kept as its decimal string.
+func yamlNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = yamlNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = yamlNumbers(e)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return v
+}
+
+// yamlJSON decodes a YAML node into generic values with unmarshal and encodes them as JSON.
+func yamlJSON(unmarshal func(any) error) ([]byte, error) {
+	var raw any
+	if err := unmarshal(&raw); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonCompatible(raw))
+}
+
+// jsonCompatible converts the map[any]any values produced by some YAML libraries to map[string]any.
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonCompatible(e)
+		}
+		return m
+	case map[string]any:
+		for k, e := range v {
+			v[k] = jsonCompatible(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = jsonCompatible(e)
+		}
+	}
+	return v
+}
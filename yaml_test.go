@@ -0,0 +1,78 @@
+package jsonsql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// yamlNode returns an unmarshal function like the one YAML libraries pass to UnmarshalYAML.
+func yamlNode(node any) func(any) error {
+	return func(out any) error {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(&node).Elem())
+		return nil
+	}
+}
+
+func TestValue_MarshalYAML(t *testing.T) {
+	out, err := NewValue(map[string]any{"name": "Alice", "big": uint64(1 << 63), "ratio": 0.5, "tags": []int{1}}).MarshalYAML()
+	if err != nil {
+		t.Fatalf("MarshalYAML failed: %v", err)
+	}
+
+	expected := map[string]any{"name": "Alice", "big": uint64(1 << 63), "ratio": 0.5, "tags": []any{int64(1)}}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("expected %#v, got %#v", expected, out)
+	}
+}
+
+func TestValue_UnmarshalYAML(t *testing.T) {
+	var v Value[testProfile]
+	if err := v.UnmarshalYAML(yamlNode(map[any]any{"name": "Alice", "email": "alice@example.com"})); err != nil {
+		t.Fatalf("UnmarshalYAML failed: %v", err)
+	}
+	if v.V.Name != "Alice" || v.V.Email != "alice@example.com" {
+		t.Errorf("unexpected result: %+v", v.V)
+	}
+
+	if err := v.UnmarshalYAML(yamlNode(nil)); !errors.Is(err, ErrNullNotAllowed) {
+		t.Errorf("expected ErrNullNotAllowed, got %v", err)
+	}
+}
+
+func TestNullable_YAML(t *testing.T) {
+	out, err := Null[testProfile]().MarshalYAML()
+	if err != nil || out != nil {
+		t.Fatalf("expected nil for NULL, got %v, %v", out, err)
+	}
+
+	n := NullableFrom(testProfile{Name: "Bob"})
+	if err := n.UnmarshalYAML(yamlNode(nil)); err != nil {
+		t.Fatalf("UnmarshalYAML failed: %v", err)
+	}
+	if n.Valid {
+		t.Errorf("expected NULL, got %+v", n)
+	}
+
+	if err := n.UnmarshalYAML(yamlNode(map[string]any{"name": "Bob"})); err != nil {
+		t.Fatalf("UnmarshalYAML failed: %v", err)
+	}
+	if !n.Valid || n.V.Name != "Bob" {
+		t.Errorf("unexpected result: %+v", n)
+	}
+}
+
+func TestSensitive_YAML(t *testing.T) {
+	out, err := NewSensitive("secret").MarshalYAML()
+	if err != nil || out != sensitivePlaceholder {
+		t.Errorf("expected placeholder, got %v, %v", out, err)
+	}
+
+	var s Sensitive[string]
+	if err := s.UnmarshalYAML(yamlNode("secret")); err != nil {
+		t.Fatalf("UnmarshalYAML failed: %v", err)
+	}
+	if s.V != "secret" {
+		t.Errorf("expected secret, got %s", s.V)
+	}
+}